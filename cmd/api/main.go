@@ -66,29 +66,66 @@ func main() {
 	caseRepo := repository.NewCaseRepository(db.DB)
 	radioRepo := repository.NewRadioRepository(db.DB)
 	statsRepo := repository.NewStatsRepository(db.DB)
+	serviceRepo := repository.NewServiceRepository(db.DB)
+	usageLogRepo := repository.NewUsageLogRepository(db.DB)
+	smsOutboxRepo := repository.NewSmsOutboxRepository(db.DB)
+	smsTemplateRepo := repository.NewSmsTemplateRepository(db.DB)
+	smsBlocklistRepo := repository.NewSmsBlocklistRepository(db.DB)
+	staffRepo := repository.NewStaffRepository(db.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.DB)
+	webhookEventRepo := repository.NewWebhookEventRepository(db.DB)
+	recordQueueRepo := repository.NewRecordQueueRepository(db.DB)
+	encodeJobRepo := repository.NewEncodeJobRepository(db.DB)
+	retentionPolicyRepo := repository.NewRetentionPolicyRepository(db.DB)
+	recordAuditRepo := repository.NewRecordAuditRepository(db.DB)
 
 	// Initialize services
-	authService := service.NewAuthService(cfg.LiveKitAPISecret)
+	authService, err := service.NewAuthService(cfg, redis)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize token signing")
+	}
+	staffService := service.NewStaffService(staffRepo, authService, cfg)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, redis, cfg)
 	roomService := service.NewRoomService(roomRepo, livekit, cfg)
 	userService := service.NewUserService(userRepo, roomRepo, livekit, cfg)
-	linkService := service.NewLinkService(linkRepo, roomRepo, cfg)
 	chatService := service.NewChatService(chatRepo)
 	notificationService := service.NewNotificationService(notificationRepo)
 	recordService := service.NewRecordService(recordRepo, roomRepo, livekit, cfg)
+	recordQueueService := service.NewRecordQueueService(recordQueueRepo, recordService, cfg)
+	autoRecordService := service.NewAutoRecordService(redis)
+	encodeService := service.NewEncodeService(encodeJobRepo, recordRepo, cfg)
+	recordRetentionService := service.NewRecordRetentionService(retentionPolicyRepo, recordRepo, encodeJobRepo, recordAuditRepo, caseRepo, roomRepo, cfg)
 	carService := service.NewCarService(carRepo)
 	caseService := service.NewCaseService(caseRepo)
 	radioService := service.NewRadioService(radioRepo)
 	statsService := service.NewStatsService(statsRepo)
 	smsService := service.NewSMSService(cfg)
+	smsLimiterService := service.NewSmsLimiterService(redis, smsBlocklistRepo, cfg)
+	smsOutboxService := service.NewSmsOutboxService(smsOutboxRepo, linkRepo, smsService, smsLimiterService, cfg)
+	smsTemplateService := service.NewSmsTemplateService(smsTemplateRepo, cfg)
+	linkService := service.NewLinkService(linkRepo, roomRepo, serviceRepo, usageLogRepo, smsOutboxService, smsTemplateService, smsLimiterService, cfg)
+	playbackService := service.NewPlaybackService(linkService, recordRepo, cfg)
 	fileService := service.NewFileService(cfg)
+	waitingRoomService := service.NewWaitingRoomService(redis, linkService, roomRepo, usageLogRepo, cfg)
+	webhookEventService := service.NewWebhookEventService(webhookEventRepo, cfg)
+
+	if err := staffService.EnsureBootstrapAdmin(context.Background()); err != nil {
+		log.Warn().Err(err).Msg("Failed to create bootstrap staff account")
+	}
 
 	// Initialize crontab service
-	crontabService := service.NewCrontabService(roomService, linkService, livekit, cfg)
+	crontabService := service.NewCrontabService(roomService, linkService, recordService, recordRetentionService, livekit, cfg)
 	if err := crontabService.InitCronJobs(); err != nil {
 		log.Warn().Err(err).Msg("Failed to initialize cron jobs")
 	}
 	defer crontabService.Stop()
 
+	// Start SMS outbox worker, waiting room sweeper, record queue and encode workers
+	smsOutboxService.Start()
+	waitingRoomService.Start()
+	recordQueueService.Start()
+	encodeService.Start()
+
 	// Initialize Socket.IO hub
 	socketHub, err := socket.NewHub(
 		cfg,
@@ -98,6 +135,7 @@ func main() {
 		chatService,
 		carService,
 		linkService,
+		waitingRoomService,
 		roomRepo,
 		userRepo,
 		chatRepo,
@@ -105,15 +143,14 @@ func main() {
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to initialize Socket.IO hub")
 	} else {
+		waitingRoomService.SetNotifier(socketHub)
+
 		// Start Socket.IO hub
 		if err := socketHub.Start(); err != nil {
 			log.Warn().Err(err).Msg("Failed to start Socket.IO hub")
 		}
 	}
 
-	// Suppress unused variable warnings
-	_ = smsService
-
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:      "SMS API Gateway",
@@ -137,6 +174,7 @@ func main() {
 	app.Use(recover.New())
 	app.Use(middleware.CORSMiddleware())
 	app.Use(middleware.LoggerMiddleware())
+	app.Use(middleware.APIKeyMiddleware(apiKeyService))
 
 	// Setup Socket.IO routes
 	if socketHub != nil {
@@ -145,25 +183,39 @@ func main() {
 
 	// Initialize handlers
 	handlers := &router.Handlers{
-		Auth:         handler.NewAuthHandler(authService),
+		Auth:         handler.NewAuthHandler(authService, staffService),
 		Room:         handler.NewRoomHandler(roomService, authService),
-		User:         handler.NewUserHandler(userService),
+		User:         handler.NewUserHandler(userService, linkService, waitingRoomService),
 		Link:         handler.NewLinkHandler(linkService, userService),
 		System:       handler.NewSystemHandler(db, redis, livekit, crontabService, cfg),
 		Chat:         handler.NewChatHandler(chatService),
 		Notification: handler.NewNotificationHandler(notificationService),
-		Record:       handler.NewRecordHandler(recordService),
+		Record:       handler.NewRecordHandler(recordService, recordQueueService, encodeService, recordRetentionService),
+		Playback:     handler.NewPlaybackHandler(playbackService),
 		Car:          handler.NewCarHandler(carService),
-		Case:         handler.NewCaseHandler(caseService),
+		Case:         handler.NewCaseHandler(caseService, recordRetentionService),
 		Radio:        handler.NewRadioHandler(radioService),
 		Stats:        handler.NewStatsHandler(statsService),
 		Upload:       handler.NewUploadHandler(fileService),
-		Webhook:      handler.NewWebhookHandler(roomService, userService, recordService, recordRepo, livekit, cfg),
+		SMS:          handler.NewSMSHandler(smsOutboxService, smsTemplateService, smsLimiterService),
+		Staff:        handler.NewStaffHandler(staffService),
+		APIKey:       handler.NewAPIKeyHandler(apiKeyService),
+		WaitingRoom:  handler.NewWaitingRoomHandler(waitingRoomService, userService),
+		Webhook:      handler.NewWebhookHandler(roomService, userService, recordService, recordQueueService, autoRecordService, encodeService, smsOutboxService, webhookEventService, recordRepo, livekit, cfg),
 		Test:         handler.NewTestHandler(cfg, db, redis, livekit),
 	}
 
+	if socketHub != nil {
+		handlers.Webhook.SetSocketHub(socketHub)
+	}
+
+	// Start webhook event workers once the webhook handler has registered its processor
+	webhookEventService.Start()
+
 	// Setup routes
-	router.SetupRoutes(app, handlers, authService, cfg, db, redis, livekit, recordRepo)
+	if err := router.SetupRoutes(app, handlers, authService, cfg, db, redis, livekit, recordRepo); err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up routes")
+	}
 
 	// Start server
 	go func() {
@@ -176,11 +228,16 @@ func main() {
 	}()
 
 	// Graceful shutdown
-	gracefulShutdown(app, db, redis, crontabService, socketHub)
+	gracefulShutdown(app, db, redis, crontabService, socketHub, smsOutboxService, waitingRoomService, recordQueueService, encodeService, webhookEventService)
+}
+
+// backgroundWorker is a long-running job that must stop before connections close
+type backgroundWorker interface {
+	Stop()
 }
 
 // gracefulShutdown handles graceful shutdown of the application
-func gracefulShutdown(app *fiber.App, db *config.Database, redis *config.RedisManager, crontab *service.CrontabService, socketHub *socket.Hub) {
+func gracefulShutdown(app *fiber.App, db *config.Database, redis *config.RedisManager, crontab *service.CrontabService, socketHub *socket.Hub, workers ...backgroundWorker) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
@@ -191,6 +248,11 @@ func gracefulShutdown(app *fiber.App, db *config.Database, redis *config.RedisMa
 	log.Info().Msg("Stopping cron jobs...")
 	crontab.Stop()
 
+	log.Info().Msg("Stopping background workers...")
+	for _, worker := range workers {
+		worker.Stop()
+	}
+
 	// Phase 2: Stop Socket.IO
 	if socketHub != nil {
 		log.Info().Msg("Stopping Socket.IO...")
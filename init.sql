@@ -19,6 +19,32 @@
 CREATE DATABASE IF NOT EXISTS `conference` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci */ /*!80016 DEFAULT ENCRYPTION='N' */;
 USE `conference`;
 
+-- Dumping structure for table conference.api_key
+CREATE TABLE IF NOT EXISTS `api_key` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `keyPrefix` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `keyHash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `previousKeyHash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmPreviousExpired` datetime DEFAULT NULL,
+  `scopes` varchar(1000) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `rateLimit` int NOT NULL DEFAULT '0',
+  `service` int NOT NULL DEFAULT '0',
+  `organization` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `enabled` int DEFAULT '1',
+  `lastUsedIp` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmLastUsed` datetime DEFAULT NULL,
+  `dtmRotated` datetime DEFAULT NULL,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_api_key_keyHash` (`keyHash`),
+  KEY `idx_api_key_previousKeyHash` (`previousKeyHash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
 -- Dumping structure for table conference.car_track
 CREATE TABLE IF NOT EXISTS `car_track` (
   `id` int NOT NULL AUTO_INCREMENT,
@@ -61,6 +87,10 @@ CREATE TABLE IF NOT EXISTS `case_data` (
   `userName` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `dtmCreated` datetime DEFAULT NULL,
   `organization` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `legalHold` tinyint NOT NULL DEFAULT '0',
+  `legalHoldReason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `legalHoldBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmLegalHold` datetime DEFAULT NULL,
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB AUTO_INCREMENT=378 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
@@ -105,6 +135,32 @@ CREATE TABLE IF NOT EXISTS `data_log` (
 
 -- Data exporting was unselected.
 
+-- Dumping structure for table conference.encode_job
+CREATE TABLE IF NOT EXISTS `encode_job` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `recordId` int NOT NULL,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `source` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'queued',
+  `attempts` int NOT NULL DEFAULT '0',
+  `maxAttempts` int NOT NULL DEFAULT '3',
+  `callbackToken` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `encoderJobId` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `hls` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `thumbnail` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `lastError` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `dtmNextAttempt` datetime DEFAULT NULL,
+  `dtmSubmitted` datetime DEFAULT NULL,
+  `dtmCompleted` datetime DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_encode_job_recordId` (`recordId`),
+  KEY `idx_encode_job_status_next` (`status`,`dtmNextAttempt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
 -- Dumping structure for table conference.files
 CREATE TABLE IF NOT EXISTS `files` (
   `id` int NOT NULL AUTO_INCREMENT,
@@ -130,9 +186,10 @@ CREATE TABLE IF NOT EXISTS `link_connect` (
   `id` int unsigned NOT NULL AUTO_INCREMENT,
   `roomUserId` int DEFAULT NULL,
   `sms` int DEFAULT '1',
+  `smsDeliveryStatus` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `recordId` int DEFAULT NULL,
   `mobile` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
-  `linkID` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `linkID` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL,
   `domainIndex` int DEFAULT '0',
   `share` int DEFAULT '0',
   `enabled` int DEFAULT '1',
@@ -162,11 +219,12 @@ CREATE TABLE IF NOT EXISTS `link_connect` (
   `dtmDisconnect` datetime DEFAULT NULL,
   `dtmCreated` datetime DEFAULT NULL,
   `dtmExpired` datetime DEFAULT NULL,
+  `dtmRedeemed` datetime DEFAULT NULL,
   `sync_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_link_connect_linkID` (`linkID`),
   KEY `idx_link_connect_expired_enabled` (`dtmExpired`,`enabled`),
   KEY `idx_link_connect_room` (`room`),
-  KEY `idx_link_connect_linkID` (`linkID`),
   KEY `FK_link_connect_room_user` (`roomUserId`),
   CONSTRAINT `FK_link_connect_room_user` FOREIGN KEY (`roomUserId`) REFERENCES `room_user` (`id`)
 ) ENGINE=InnoDB AUTO_INCREMENT=111528 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -244,11 +302,32 @@ CREATE TABLE IF NOT EXISTS `radio_locations` (
 
 -- Data exporting was unselected.
 
+-- Dumping structure for table conference.record_audit
+CREATE TABLE IF NOT EXISTS `record_audit` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `action` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `recordId` int DEFAULT NULL,
+  `caseId` int DEFAULT NULL,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `service` int DEFAULT NULL,
+  `files` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `reason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `actor` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_record_audit_recordId` (`recordId`),
+  KEY `idx_record_audit_room` (`room`),
+  KEY `idx_record_audit_dtmCreated` (`dtmCreated`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
 -- Dumping structure for table conference.record_media
 CREATE TABLE IF NOT EXISTS `record_media` (
   `id` int NOT NULL AUTO_INCREMENT,
   `egressId` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `identity` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `fileName` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `filePath` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `fileSize` int DEFAULT NULL,
@@ -257,30 +336,74 @@ CREATE TABLE IF NOT EXISTS `record_media` (
   `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT 'completed',
   `dtmCreated` datetime DEFAULT NULL,
   `dtmCompleted` datetime DEFAULT NULL,
-  `hls` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmArchived` datetime DEFAULT NULL,
+  `hls` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `thumbnail` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `archivePath` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `encode` int DEFAULT NULL COMMENT '0=à¸£à¸­ Encode, 1=à¸ªà¸³à¹€à¸£à¹‡à¸ˆ, 2=à¸¥à¹‰à¸¡à¹€à¸«à¸¥à¸§, 3=à¸žà¸±à¸à¹„à¸Ÿà¸¥à¹Œ',
   `uploader` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `startRecord` datetime DEFAULT NULL,
   `endRecord` datetime DEFAULT NULL,
   `dtmUpdated` datetime DEFAULT NULL,
-  PRIMARY KEY (`id`)
+  PRIMARY KEY (`id`),
+  KEY `idx_record_media_room_status` (`room`,`status`)
 ) ENGINE=InnoDB AUTO_INCREMENT=542 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Data exporting was unselected.
 
+-- Dumping structure for table conference.record_queue
+CREATE TABLE IF NOT EXISTS `record_queue` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `identity` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `recordType` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `options` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `priority` int NOT NULL DEFAULT '0',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'queued',
+  `egressId` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `lastError` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmStarted` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_record_queue_status_priority` (`status`,`priority`,`id`),
+  KEY `idx_record_queue_room` (`room`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
+-- Dumping structure for table conference.retention_policy
+CREATE TABLE IF NOT EXISTS `retention_policy` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `service` int NOT NULL,
+  `retainDays` int NOT NULL DEFAULT '0',
+  `archiveDays` int NOT NULL DEFAULT '0',
+  `enabled` tinyint NOT NULL DEFAULT '1',
+  `updatedBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_retention_policy_service` (`service`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
 -- Dumping structure for table conference.room_conference
 CREATE TABLE IF NOT EXISTS `room_conference` (
   `id` int unsigned NOT NULL AUTO_INCREMENT,
   `nodeLivekitId` int DEFAULT NULL,
   `status` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `roomType` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
-  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL,
   `service` int DEFAULT NULL,
   `recordStatus` int DEFAULT '0',
   `recordId` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `autoRecord` int DEFAULT '0',
   `recordType` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
-  `encodingOptionsPreset` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `encodingOptionsPreset` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `recordLayout` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `recordFileType` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `chatEnabled` int DEFAULT '0',
   `messageUnread` int DEFAULT '0',
   `agentSeen` datetime DEFAULT NULL,
@@ -296,9 +419,9 @@ CREATE TABLE IF NOT EXISTS `room_conference` (
   `dtmStopRecord` datetime DEFAULT NULL,
   `sync_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_room_conference_room` (`room`),
   KEY `idx_room_conference_status_dtmexpired` (`status`,`dtmExpired`),
   KEY `FK_room_conference_node_livekit` (`nodeLivekitId`) USING BTREE,
-  KEY `idx_room_conference_room` (`room`),
   KEY `idx_room_conference_service` (`service`),
   CONSTRAINT `FK_room_conference_node_livekit` FOREIGN KEY (`nodeLivekitId`) REFERENCES `node_livekit` (`id`)
 ) ENGINE=InnoDB AUTO_INCREMENT=28648 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -343,6 +466,7 @@ CREATE TABLE IF NOT EXISTS `services` (
   `domainsVideo` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
   `domainsLocation` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
   `smsSenderName` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `smsProvider` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `logo` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `titleColor` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
   `latitude` decimal(12,9) DEFAULT NULL,
@@ -352,6 +476,116 @@ CREATE TABLE IF NOT EXISTS `services` (
 
 -- Data exporting was unselected.
 
+-- Dumping structure for table conference.sms_blocklist
+CREATE TABLE IF NOT EXISTS `sms_blocklist` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `mobile` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `service` int NOT NULL DEFAULT '0',
+  `source` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `reason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_sms_blocklist_mobile_service` (`mobile`,`service`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
+-- Dumping structure for table conference.sms_outbox
+CREATE TABLE IF NOT EXISTS `sms_outbox` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `service` int DEFAULT NULL,
+  `linkID` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `mobile` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `sender` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `provider` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `messageType` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `message` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'pending',
+  `attempts` int NOT NULL DEFAULT '0',
+  `maxAttempts` int NOT NULL DEFAULT '5',
+  `providerMessageId` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `lastError` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `deliveryStatus` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `deliveryError` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmNextAttempt` datetime DEFAULT NULL,
+  `dtmSent` datetime DEFAULT NULL,
+  `dtmDelivered` datetime DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_sms_outbox_status_next` (`status`,`dtmNextAttempt`),
+  KEY `idx_sms_outbox_room` (`room`),
+  KEY `idx_sms_outbox_linkID` (`linkID`),
+  KEY `idx_sms_outbox_providerMessageId` (`providerMessageId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
+-- Dumping structure for table conference.sms_template
+CREATE TABLE IF NOT EXISTS `sms_template` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `service` int NOT NULL DEFAULT '0',
+  `messageType` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `locale` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'th',
+  `body` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `enabled` int DEFAULT '1',
+  `updatedBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_sms_template_service_type_locale` (`service`,`messageType`,`locale`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
+-- Dumping structure for table conference.staff_account
+CREATE TABLE IF NOT EXISTS `staff_account` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `userName` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `passwordHash` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `displayName` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `role` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'agent',
+  `service` int NOT NULL DEFAULT '0',
+  `organization` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `enabled` int DEFAULT '1',
+  `failedAttempts` int NOT NULL DEFAULT '0',
+  `dtmLocked` datetime DEFAULT NULL,
+  `dtmLastLogin` datetime DEFAULT NULL,
+  `dtmPasswordChanged` datetime DEFAULT NULL,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_staff_account_userName` (`userName`),
+  KEY `idx_staff_account_service` (`service`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
+-- Dumping structure for table conference.staff_refresh_token
+CREATE TABLE IF NOT EXISTS `staff_refresh_token` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `staffId` int unsigned NOT NULL,
+  `tokenHash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `familyId` char(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `userAgent` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `ip` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmExpired` datetime DEFAULT NULL,
+  `dtmRevoked` datetime DEFAULT NULL,
+  `replacedBy` int unsigned DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_staff_refresh_token_tokenHash` (`tokenHash`),
+  KEY `idx_staff_refresh_token_staffId` (`staffId`),
+  KEY `idx_staff_refresh_token_familyId` (`familyId`),
+  CONSTRAINT `FK_staff_refresh_token_staff_account` FOREIGN KEY (`staffId`) REFERENCES `staff_account` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
 -- Dumping structure for table conference.usage_status_log
 CREATE TABLE IF NOT EXISTS `usage_status_log` (
   `id` int unsigned NOT NULL AUTO_INCREMENT,
@@ -376,6 +610,30 @@ CREATE TABLE IF NOT EXISTS `usage_status_log` (
 
 -- Data exporting was unselected.
 
+-- Dumping structure for table conference.webhook_event
+CREATE TABLE IF NOT EXISTS `webhook_event` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `eventId` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `event` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `room` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `createdAt` bigint NOT NULL DEFAULT '0',
+  `payload` mediumtext CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'pending',
+  `attempts` int NOT NULL DEFAULT '0',
+  `lastError` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `owner` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmReceived` datetime DEFAULT NULL,
+  `dtmClaimed` datetime DEFAULT NULL,
+  `dtmNextAttempt` datetime DEFAULT NULL,
+  `dtmProcessed` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_webhook_event_eventId` (`eventId`),
+  KEY `idx_webhook_event_room_status` (`room`,`status`),
+  KEY `idx_webhook_event_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Data exporting was unselected.
+
 /*!40103 SET TIME_ZONE=IFNULL(@OLD_TIME_ZONE, 'system') */;
 /*!40101 SET SQL_MODE=IFNULL(@OLD_SQL_MODE, '') */;
 /*!40014 SET FOREIGN_KEY_CHECKS=IFNULL(@OLD_FOREIGN_KEY_CHECKS, 1) */;
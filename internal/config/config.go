@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -8,6 +9,9 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// maxGeneratedIDLength is the size of the linkID and room columns
+const maxGeneratedIDLength = 50
+
 // Config holds all application configuration
 type Config struct {
 	// Server
@@ -23,24 +27,42 @@ type Config struct {
 	// API
 	APIURL string
 
+	// Auth
+	Auth AuthConfig
+
 	// SMS
-	SMSEnable bool
-	SMSAPIURL string
+	SMSEnable        bool
+	SMSAPIURL        string
+	SMSProvider      string
+	SMSSender        string
+	SMSFilePath      string
+	SMSDefaultLocale string
+	SMSDLRSecret     string
+	SMSForm          SMSFormConfig
+	SMPP             SMPPConfig
+	SMSOutbox        SMSOutboxConfig
+	SMSRateLimit     SMSRateLimitConfig
 
 	// File
 	RecordPath    string
 	FileSizeLimit int64
+	Playback      PlaybackConfig
 
 	// Room
 	JoinRoomRepeatDelay   time.Duration
 	AutoCloseRoom         bool
 	RoomDayDefaultTimeout int
+	WaitingRoom           WaitingRoomConfig
 
 	// LiveKit
 	LiveKitAPIKey    string
 	LiveKitAPISecret string
 	LiveKitHost      string
 	EgressLimit      int
+	LiveKitWebhook   WebhookConfig
+	RecordQueue      RecordQueueConfig
+	RecordReconcile  RecordReconcileConfig
+	RecordRetention  RecordRetentionConfig
 
 	// Radio API
 	RadioLocationAPIURL                string
@@ -49,9 +71,12 @@ type Config struct {
 
 	// Encode API
 	EncodeAPI string
+	Encode    EncodeConfig
 
 	// Custom
-	CustomCharset string
+	CustomCharset  string
+	LinkIDLength   int
+	RoomNameLength int
 }
 
 // MySQLConfig holds MySQL database configuration
@@ -72,6 +97,105 @@ type RedisConfig struct {
 	StateDB    int
 }
 
+// AuthConfig holds staff login and token configuration
+type AuthConfig struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+	AdminUserName   string
+	AdminPassword   string
+	JWTSecret       string
+	JWTKeyDir       string
+	JWTSigningKeyID string
+
+	// APIKeyRotationGrace is how long the old key of a rotated API key keeps working
+	APIKeyRotationGrace time.Duration
+}
+
+// SMSFormConfig holds form-encoded HTTP SMS gateway configuration
+type SMSFormConfig struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// SMPPConfig holds SMPP SMS gateway configuration
+type SMPPConfig struct {
+	Host       string
+	Port       string
+	SystemID   string
+	Password   string
+	SystemType string
+	Timeout    time.Duration
+}
+
+// SMSOutboxConfig holds SMS outbox worker configuration
+type SMSOutboxConfig struct {
+	Interval    time.Duration
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// SMSRateLimitConfig holds sliding-window SMS limits. A zero limit disables that scope.
+type SMSRateLimitConfig struct {
+	MobileLimit   int
+	MobileWindow  time.Duration
+	ServiceLimit  int
+	ServiceWindow time.Duration
+	GlobalLimit   int
+	GlobalWindow  time.Duration
+}
+
+// WaitingRoomConfig holds join-permission waiting room configuration
+type WaitingRoomConfig struct {
+	Timeout       time.Duration
+	Retention     time.Duration
+	SweepInterval time.Duration
+}
+
+// WebhookConfig holds LiveKit webhook event worker configuration
+type WebhookConfig struct {
+	Workers       int
+	SweepInterval time.Duration
+	MaxAttempts   int
+	BackoffBase   time.Duration
+	BackoffMax    time.Duration
+}
+
+// RecordQueueConfig holds recording queue worker configuration
+type RecordQueueConfig struct {
+	Interval time.Duration
+}
+
+// PlaybackConfig holds signed recording playback URL configuration
+type PlaybackConfig struct {
+	Secret string
+	TTL    time.Duration
+}
+
+// EncodeConfig holds post-recording encode job worker configuration
+type EncodeConfig struct {
+	Interval    time.Duration
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Timeout     time.Duration
+}
+
+// RecordRetentionConfig holds the recording retention cron job configuration
+type RecordRetentionConfig struct {
+	Schedule    string
+	ArchivePath string
+}
+
+// RecordReconcileConfig holds the recording reconciliation cron job configuration
+type RecordReconcileConfig struct {
+	Schedule string
+	Grace    time.Duration
+}
+
 var cfg *Config
 
 // Load loads configuration from environment variables
@@ -111,35 +235,126 @@ func Load() (*Config, error) {
 		// API
 		APIURL: getEnv("API_URL", "http://localhost:5500"),
 
+		// Auth
+		Auth: AuthConfig{
+			AccessTokenTTL:  time.Duration(getEnvAsInt("AUTH_ACCESS_TOKEN_TTL", 900)) * time.Second,
+			RefreshTokenTTL: time.Duration(getEnvAsInt("AUTH_REFRESH_TOKEN_TTL", 2592000)) * time.Second,
+			MaxFailedLogins: getEnvAsInt("AUTH_MAX_FAILED_LOGINS", 5),
+			LockoutDuration: time.Duration(getEnvAsInt("AUTH_LOCKOUT_DURATION", 900)) * time.Second,
+			AdminUserName:   getEnv("AUTH_ADMIN_USERNAME", ""),
+			AdminPassword:   getEnv("AUTH_ADMIN_PASSWORD", ""),
+			JWTSecret:       getEnv("JWT_SECRET", ""),
+			JWTKeyDir:       getEnv("JWT_KEY_DIR", ""),
+			JWTSigningKeyID: getEnv("JWT_SIGNING_KEY_ID", ""),
+
+			APIKeyRotationGrace: time.Duration(getEnvAsInt("API_KEY_ROTATION_GRACE", 86400)) * time.Second,
+		},
+
 		// SMS
-		SMSEnable: getEnvAsBool("SMS_ENABLE", false),
-		SMSAPIURL: getEnv("SMS_API_URL", ""),
+		SMSEnable:        getEnvAsBool("SMS_ENABLE", false),
+		SMSAPIURL:        getEnv("SMS_API_URL", ""),
+		SMSProvider:      getEnv("SMS_PROVIDER", "json"),
+		SMSSender:        getEnv("SMS_SENDER", ""),
+		SMSFilePath:      getEnv("SMS_FILE_PATH", "./sms-outbox.log"),
+		SMSDefaultLocale: getEnv("SMS_DEFAULT_LOCALE", "th"),
+		SMSDLRSecret:     getEnv("SMS_DLR_SECRET", ""),
+		SMSForm: SMSFormConfig{
+			URL:      getEnv("SMS_FORM_URL", ""),
+			Username: getEnv("SMS_FORM_USERNAME", ""),
+			Password: getEnv("SMS_FORM_PASSWORD", ""),
+		},
+		SMPP: SMPPConfig{
+			Host:       getEnv("SMPP_HOST", ""),
+			Port:       getEnv("SMPP_PORT", "2775"),
+			SystemID:   getEnv("SMPP_SYSTEM_ID", ""),
+			Password:   getEnv("SMPP_PASSWORD", ""),
+			SystemType: getEnv("SMPP_SYSTEM_TYPE", ""),
+			Timeout:    time.Duration(getEnvAsInt("SMPP_TIMEOUT", 10)) * time.Second,
+		},
+		SMSOutbox: SMSOutboxConfig{
+			Interval:    time.Duration(getEnvAsInt("SMS_OUTBOX_INTERVAL", 10)) * time.Second,
+			MaxAttempts: getEnvAsInt("SMS_OUTBOX_MAX_ATTEMPTS", 5),
+			BackoffBase: time.Duration(getEnvAsInt("SMS_OUTBOX_BACKOFF_BASE", 30)) * time.Second,
+			BackoffMax:  time.Duration(getEnvAsInt("SMS_OUTBOX_BACKOFF_MAX", 3600)) * time.Second,
+		},
+		SMSRateLimit: SMSRateLimitConfig{
+			MobileLimit:   getEnvAsInt("SMS_RATE_LIMIT_MOBILE", 5),
+			MobileWindow:  time.Duration(getEnvAsInt("SMS_RATE_LIMIT_MOBILE_WINDOW", 3600)) * time.Second,
+			ServiceLimit:  getEnvAsInt("SMS_RATE_LIMIT_SERVICE", 1000),
+			ServiceWindow: time.Duration(getEnvAsInt("SMS_RATE_LIMIT_SERVICE_WINDOW", 3600)) * time.Second,
+			GlobalLimit:   getEnvAsInt("SMS_RATE_LIMIT_GLOBAL", 5000),
+			GlobalWindow:  time.Duration(getEnvAsInt("SMS_RATE_LIMIT_GLOBAL_WINDOW", 3600)) * time.Second,
+		},
 
 		// File
 		RecordPath:    getEnv("RECORD_PATH", "./record-file"),
 		FileSizeLimit: getEnvAsInt64("FILE_SIZE_LIMIT", 524288000),
+		Playback: PlaybackConfig{
+			Secret: getEnv("PLAYBACK_SECRET", ""),
+			TTL:    time.Duration(getEnvAsInt("PLAYBACK_URL_TTL", 3600)) * time.Second,
+		},
 
 		// Room
 		JoinRoomRepeatDelay:   time.Duration(getEnvAsInt("JOIN_ROOM_REPEAT_DELAY", 5000)) * time.Millisecond,
 		AutoCloseRoom:         getEnvAsBool("AUTO_CLOSE_ROOM", false),
 		RoomDayDefaultTimeout: getEnvAsInt("ROOM_DAY_DEFAULT_TIMEOUT", 365),
+		WaitingRoom: WaitingRoomConfig{
+			Timeout:       time.Duration(getEnvAsInt("WAITING_ROOM_TIMEOUT", 300)) * time.Second,
+			Retention:     time.Duration(getEnvAsInt("WAITING_ROOM_RETENTION", 3600)) * time.Second,
+			SweepInterval: time.Duration(getEnvAsInt("WAITING_ROOM_SWEEP_INTERVAL", 15)) * time.Second,
+		},
 
 		// LiveKit
 		LiveKitAPIKey:    getEnv("LIVEKIT_API_KEY", ""),
 		LiveKitAPISecret: getEnv("LIVEKIT_API_SECRET", ""),
 		LiveKitHost:      getEnv("LIVEKIT_HOST", ""),
 		EgressLimit:      getEnvAsInt("EGRESS_LIMIT", 4),
+		LiveKitWebhook: WebhookConfig{
+			Workers:       getEnvAsInt("WEBHOOK_WORKERS", 8),
+			SweepInterval: time.Duration(getEnvAsInt("WEBHOOK_SWEEP_INTERVAL", 30)) * time.Second,
+			MaxAttempts:   getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 5),
+			BackoffBase:   time.Duration(getEnvAsInt("WEBHOOK_BACKOFF_BASE", 10)) * time.Second,
+			BackoffMax:    time.Duration(getEnvAsInt("WEBHOOK_BACKOFF_MAX", 600)) * time.Second,
+		},
+		RecordQueue: RecordQueueConfig{
+			Interval: time.Duration(getEnvAsInt("RECORD_QUEUE_INTERVAL", 30)) * time.Second,
+		},
+		RecordReconcile: RecordReconcileConfig{
+			Schedule: getEnv("RECORD_RECONCILE_SCHEDULE", "*/5 * * * *"),
+			Grace:    time.Duration(getEnvAsInt("RECORD_RECONCILE_GRACE", 120)) * time.Second,
+		},
+		RecordRetention: RecordRetentionConfig{
+			Schedule:    getEnv("RECORD_RETENTION_SCHEDULE", "0 3 * * *"),
+			ArchivePath: getEnv("RECORD_ARCHIVE_PATH", "./record-archive"),
+		},
 
 		// Radio API
 		RadioLocationAPIURL:                 getEnv("RADIO_LOCATION_API_URL", ""),
 		RadioLocationAPICredentialsUser:     getEnv("RADIO_LOCATION_API_CREDENTIALS_USERNAME", ""),
 		RadioLocationAPICredentialsPassword: getEnv("RADIO_LOCATION_API_CREDENTIALS_PASSWORD", ""),
 
-		// Encode API
+		// Encode API; set ENCODE_API to an empty value to stop encoding recordings
 		EncodeAPI: getEnv("ENCODE_API", "http://encode-api:5600"),
+		Encode: EncodeConfig{
+			Interval:    time.Duration(getEnvAsInt("ENCODE_INTERVAL", 15)) * time.Second,
+			MaxAttempts: getEnvAsInt("ENCODE_MAX_ATTEMPTS", 3),
+			BackoffBase: time.Duration(getEnvAsInt("ENCODE_BACKOFF_BASE", 60)) * time.Second,
+			BackoffMax:  time.Duration(getEnvAsInt("ENCODE_BACKOFF_MAX", 1800)) * time.Second,
+			Timeout:     time.Duration(getEnvAsInt("ENCODE_TIMEOUT", 7200)) * time.Second,
+		},
 
 		// Custom
-		CustomCharset: getEnv("CUSTOM_CHARSET", "ABCDEFGHIJKLMOPQRSTUVWXYZabcdefghijklmopqrstuvwxyz"),
+		CustomCharset:  getEnv("CUSTOM_CHARSET", "ABCDEFGHIJKLMOPQRSTUVWXYZabcdefghijklmopqrstuvwxyz"),
+		LinkIDLength:   getEnvAsInt("LINK_ID_LENGTH", 10),
+		RoomNameLength: getEnvAsInt("ROOM_NAME_LENGTH", 10),
+	}
+
+	// Link IDs and room names are stored in varchar(50) columns
+	if cfg.LinkIDLength > maxGeneratedIDLength {
+		return nil, fmt.Errorf("LINK_ID_LENGTH must not exceed %d", maxGeneratedIDLength)
+	}
+	if cfg.RoomNameLength > maxGeneratedIDLength {
+		return nil, fmt.Errorf("ROOM_NAME_LENGTH must not exceed %d", maxGeneratedIDLength)
 	}
 
 	return cfg, nil
@@ -222,18 +222,34 @@ func (lm *LiveKitManager) GenerateToken(identity, name, room string, grants *aut
 	return at.ToJWT()
 }
 
+// EgressEncoding holds how a composite egress encodes its output. Advanced
+// encoding options take precedence over Preset when set.
+type EgressEncoding struct {
+	AudioOnly bool
+	Layout    string
+	Preset    livekit.EncodingOptionsPreset
+	Advanced  *livekit.EncodingOptions
+}
+
 // StartRoomCompositeEgress starts room composite egress recording
-func (lm *LiveKitManager) StartRoomCompositeEgress(ctx context.Context, room string, output *livekit.EncodedFileOutput) (*livekit.EgressInfo, error) {
+func (lm *LiveKitManager) StartRoomCompositeEgress(ctx context.Context, room string, output *livekit.EncodedFileOutput, encoding EgressEncoding) (*livekit.EgressInfo, error) {
 	if lm.egressClient == nil {
 		return nil, fmt.Errorf("LiveKit egress client not initialized")
 	}
 
 	req := &livekit.RoomCompositeEgressRequest{
-		RoomName: room,
+		RoomName:  room,
+		Layout:    encoding.Layout,
+		AudioOnly: encoding.AudioOnly,
 		Output: &livekit.RoomCompositeEgressRequest_File{
 			File: output,
 		},
 	}
+	if encoding.Advanced != nil {
+		req.Options = &livekit.RoomCompositeEgressRequest_Advanced{Advanced: encoding.Advanced}
+	} else {
+		req.Options = &livekit.RoomCompositeEgressRequest_Preset{Preset: encoding.Preset}
+	}
 
 	info, err := lm.egressClient.StartRoomCompositeEgress(ctx, req)
 	if err != nil {
@@ -244,6 +260,58 @@ func (lm *LiveKitManager) StartRoomCompositeEgress(ctx context.Context, room str
 	return info, nil
 }
 
+// StartTrackCompositeEgress starts egress recording the audio and video track of one participant into a single file
+func (lm *LiveKitManager) StartTrackCompositeEgress(ctx context.Context, room, audioTrackID, videoTrackID string, output *livekit.EncodedFileOutput, encoding EgressEncoding) (*livekit.EgressInfo, error) {
+	if lm.egressClient == nil {
+		return nil, fmt.Errorf("LiveKit egress client not initialized")
+	}
+
+	req := &livekit.TrackCompositeEgressRequest{
+		RoomName:     room,
+		AudioTrackId: audioTrackID,
+		VideoTrackId: videoTrackID,
+		Output: &livekit.TrackCompositeEgressRequest_File{
+			File: output,
+		},
+	}
+	if encoding.Advanced != nil {
+		req.Options = &livekit.TrackCompositeEgressRequest_Advanced{Advanced: encoding.Advanced}
+	} else {
+		req.Options = &livekit.TrackCompositeEgressRequest_Preset{Preset: encoding.Preset}
+	}
+
+	info, err := lm.egressClient.StartTrackCompositeEgress(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start track composite egress: %w", err)
+	}
+
+	log.Info().Msgf("Started track composite egress for room %s: %s", room, info.EgressId)
+	return info, nil
+}
+
+// StartTrackEgress starts egress writing a single track to a file without transcoding
+func (lm *LiveKitManager) StartTrackEgress(ctx context.Context, room, trackID string, output *livekit.DirectFileOutput) (*livekit.EgressInfo, error) {
+	if lm.egressClient == nil {
+		return nil, fmt.Errorf("LiveKit egress client not initialized")
+	}
+
+	req := &livekit.TrackEgressRequest{
+		RoomName: room,
+		TrackId:  trackID,
+		Output: &livekit.TrackEgressRequest_File{
+			File: output,
+		},
+	}
+
+	info, err := lm.egressClient.StartTrackEgress(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start track egress: %w", err)
+	}
+
+	log.Info().Msgf("Started track egress for room %s: %s", room, info.EgressId)
+	return info, nil
+}
+
 // StopEgress stops an active egress
 func (lm *LiveKitManager) StopEgress(ctx context.Context, egressID string) (*livekit.EgressInfo, error) {
 	if lm.egressClient == nil {
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"strconv"
+
+	"api-gateway-go/internal/service"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHandler handles integration API key routes
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// ListAPIKeys lists API keys
+// GET /apikey
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.apiKeyService.ListAPIKeys(c.Context())
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, keys)
+}
+
+// CreateAPIKey issues an API key for an integration. The key is only shown once.
+// POST /apikey
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	type CreateRequest struct {
+		Name         string   `json:"name"`
+		Scopes       []string `json:"scopes"`
+		RateLimit    int      `json:"rateLimit"`
+		Service      int      `json:"service"`
+		Organization string   `json:"organization"`
+	}
+
+	var req CreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	result, err := h.apiKeyService.CreateAPIKey(c.Context(), service.CreateAPIKeyOptions{
+		Name:         req.Name,
+		Scopes:       req.Scopes,
+		RateLimit:    req.RateLimit,
+		Service:      req.Service,
+		Organization: req.Organization,
+		CreatedBy:    createdBy(c),
+	})
+	if err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, result)
+}
+
+// UpdateAPIKey changes the scopes and rate limit of an API key
+// PUT /apikey/:id
+func (h *APIKeyHandler) UpdateAPIKey(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid API key ID")
+	}
+
+	type UpdateRequest struct {
+		Scopes    []string `json:"scopes"`
+		RateLimit int      `json:"rateLimit"`
+	}
+
+	var req UpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	apiKey, err := h.apiKeyService.UpdateAPIKey(c.Context(), uint(id), req.Scopes, req.RateLimit)
+	if err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, apiKey)
+}
+
+// RotateAPIKey issues a new key; the old one keeps working for a grace period
+// POST /apikey/:id/rotate
+func (h *APIKeyHandler) RotateAPIKey(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid API key ID")
+	}
+
+	result, err := h.apiKeyService.RotateAPIKey(c.Context(), uint(id))
+	if err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, result)
+}
+
+// SetEnabled enables or disables an API key
+// PUT /apikey/:id/enabled
+func (h *APIKeyHandler) SetEnabled(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid API key ID")
+	}
+
+	type EnabledRequest struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	var req EnabledRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	if err := h.apiKeyService.SetAPIKeyEnabled(c.Context(), uint(id), req.Enabled); err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"enabled": req.Enabled,
+	})
+}
+
+// DeleteAPIKey deletes an API key
+// DELETE /apikey/:id
+func (h *APIKeyHandler) DeleteAPIKey(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid API key ID")
+	}
+
+	if err := h.apiKeyService.DeleteAPIKey(c.Context(), uint(id)); err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"deleted": true,
+	})
+}
+
+// apiKeyErrorResponse maps API key errors to responses
+func apiKeyErrorResponse(c *fiber.Ctx, err error) error {
+	switch err {
+	case service.ErrAPIKeyNotFound:
+		return utils.NotFoundResponse(c, "API key not found")
+	case service.ErrAPIKeyNameRequired, service.ErrInvalidScope, service.ErrScopeNotAllowed:
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	return utils.ErrorResponse(c, err.Error())
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"strconv"
+
+	"api-gateway-go/internal/middleware"
+	"api-gateway-go/internal/repository"
 	"api-gateway-go/internal/service"
 	"api-gateway-go/pkg/utils"
 
@@ -9,25 +13,33 @@ import (
 
 // AuthHandler handles authentication routes
 type AuthHandler struct {
-	authService *service.AuthService
+	authService  *service.AuthService
+	staffService *service.StaffService
 }
 
 // NewAuthHandler creates a new AuthHandler
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, staffService *service.StaffService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:  authService,
+		staffService: staffService,
 	}
 }
 
-// CreateToken creates a new JWT token
+// CreateToken creates a new JWT token bound to the caller's service. Callers
+// without a tenant, such as admins, may choose the service.
 // GET /auth/create
 func (h *AuthHandler) CreateToken(c *fiber.Ctx) error {
 	userName := c.Query("userName")
 	room := c.Query("room")
 	identity := c.Query("identity")
 	userType := c.Query("userType")
+	serviceID, _ := strconv.Atoi(c.Query("service", "0"))
+
+	if tenant, ok := repository.TenantFromContext(c.Context()); ok {
+		serviceID = tenant.Service
+	}
 
-	token, err := h.authService.CreateToken(c.Context(), userName, room, identity, userType, 0)
+	token, err := h.authService.CreateToken(c.Context(), userName, room, identity, userType, serviceID, 0)
 	if err != nil {
 		return utils.ErrorResponse(c, err.Error())
 	}
@@ -37,6 +49,14 @@ func (h *AuthHandler) CreateToken(c *fiber.Ctx) error {
 	})
 }
 
+// JWKS publishes the public keys gateway tokens are signed with. The body is
+// a bare JSON Web Key Set, as verifiers expect, not the usual response envelope.
+// GET /.well-known/jwks.json
+func (h *AuthHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.authService.JWKS())
+}
+
 // VerifyToken verifies a JWT token
 // GET /auth/verify
 func (h *AuthHandler) VerifyToken(c *fiber.Ctx) error {
@@ -50,6 +70,9 @@ func (h *AuthHandler) VerifyToken(c *fiber.Ctx) error {
 	}
 
 	claims, err := h.authService.VerifyToken(c.Context(), token)
+	if err == nil {
+		err = h.authService.CheckRevoked(c.Context(), claims)
+	}
 	if err != nil {
 		return utils.ErrorResponseWithStatus(c, fiber.StatusUnauthorized, err.Error())
 	}
@@ -57,7 +80,7 @@ func (h *AuthHandler) VerifyToken(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, claims)
 }
 
-// VerifyUser verifies a user by username and password
+// VerifyUser logs a staff account in and returns an access token and a refresh token
 // POST /auth/verifyuser
 func (h *AuthHandler) VerifyUser(c *fiber.Ctx) error {
 	type VerifyRequest struct {
@@ -70,10 +93,80 @@ func (h *AuthHandler) VerifyUser(c *fiber.Ctx) error {
 		return utils.BadRequestResponse(c, "Invalid request body")
 	}
 
-	// This is a placeholder - actual implementation would check against a user database
-	// For now, we just return success
+	result, err := h.staffService.Login(c.Context(), service.LoginOptions{
+		UserName:  req.UserName,
+		Password:  req.Password,
+		UserAgent: c.Get("User-Agent"),
+		IP:        c.IP(),
+	})
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, result)
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token
+// POST /auth/refresh
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	type RefreshRequest struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	result, err := h.staffService.Refresh(c.Context(), service.RefreshOptions{
+		RefreshToken: req.RefreshToken,
+		UserAgent:    c.Get("User-Agent"),
+		IP:           c.IP(),
+	})
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, result)
+}
+
+// Logout revokes the caller's access token and refresh token
+// POST /auth/logout
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	type LogoutRequest struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	var req LogoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.BadRequestResponse(c, "Invalid request body")
+		}
+	}
+
+	claims := middleware.GetUserFromContext(c)
+	if claims == nil {
+		return utils.UnauthorizedResponse(c, "Invalid token")
+	}
+
+	if err := h.staffService.Logout(c.Context(), claims, req.RefreshToken); err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
 	return utils.SuccessResponse(c, fiber.Map{
-		"verified": true,
-		"userName": req.UserName,
+		"loggedOut": true,
 	})
 }
+
+// loginErrorResponse maps staff login errors to HTTP responses
+func loginErrorResponse(c *fiber.Ctx, err error) error {
+	switch code := service.AuthErrorCode(err); code {
+	case service.AuthCodeInvalidCredentials, service.AuthCodeInvalidRefresh, service.AuthCodeRefreshReused:
+		return utils.ErrorResponseWithCode(c, fiber.StatusUnauthorized, code, err.Error())
+	case service.AuthCodeAccountLocked:
+		return utils.ErrorResponseWithCode(c, fiber.StatusLocked, code, err.Error())
+	case service.AuthCodeAccountDisabled:
+		return utils.ErrorResponseWithCode(c, fiber.StatusForbidden, code, err.Error())
+	}
+	return utils.ErrorResponse(c, err.Error())
+}
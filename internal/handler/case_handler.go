@@ -12,12 +12,14 @@ import (
 // CaseHandler handles case routes
 type CaseHandler struct {
 	caseService *service.CaseService
+	retention   *service.RecordRetentionService
 }
 
 // NewCaseHandler creates a new CaseHandler
-func NewCaseHandler(caseService *service.CaseService) *CaseHandler {
+func NewCaseHandler(caseService *service.CaseService, retention *service.RecordRetentionService) *CaseHandler {
 	return &CaseHandler{
 		caseService: caseService,
+		retention:   retention,
 	}
 }
 
@@ -275,6 +277,9 @@ func (h *CaseHandler) DeleteCase(c *fiber.Ctx) error {
 
 	err = h.caseService.DeleteCase(c.Context(), uint(id))
 	if err != nil {
+		if err == service.ErrCaseLegalHold {
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		}
 		return utils.ErrorResponse(c, err.Error())
 	}
 
@@ -282,3 +287,36 @@ func (h *CaseHandler) DeleteCase(c *fiber.Ctx) error {
 		"deleted": true,
 	})
 }
+
+// SetLegalHold places a case under legal hold, blocking deletion of its recordings, or releases it
+// PUT /case/legalhold/:id
+func (h *CaseHandler) SetLegalHold(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid case ID")
+	}
+
+	type LegalHoldRequest struct {
+		Hold   bool   `json:"hold"`
+		Reason string `json:"reason"`
+	}
+
+	var req LegalHoldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	if req.Hold && req.Reason == "" {
+		return utils.BadRequestResponse(c, "Reason required")
+	}
+
+	caseData, err := h.retention.SetLegalHold(c.Context(), uint(id), req.Hold, req.Reason, createdBy(c))
+	if err != nil {
+		if err == service.ErrCaseNotFound {
+			return utils.NotFoundResponse(c, "Case not found")
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, caseData)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"strconv"
 
+	"api-gateway-go/internal/middleware"
 	"api-gateway-go/internal/service"
 	"api-gateway-go/pkg/utils"
 
@@ -66,6 +67,9 @@ func (h *LinkHandler) CreateLink(c *fiber.Ctx) error {
 		OneTimeLink           int    `json:"oneTimeLink"`
 		UserAgent             string `json:"userAgent"`
 		DaysExpired           int    `json:"daysExpired"`
+		Service               int    `json:"service"`
+		SendSMS               bool   `json:"sendSms"`
+		Locale                string `json:"locale"`
 	}
 
 	var req CreateRequest
@@ -85,8 +89,18 @@ func (h *LinkHandler) CreateLink(c *fiber.Ctx) error {
 		OneTimeLink:           req.OneTimeLink,
 		UserAgent:             req.UserAgent,
 		DaysExpired:           req.DaysExpired,
+		Service:               req.Service,
+		SendSMS:               req.SendSMS,
+		Locale:                req.Locale,
+		CreatedBy:             createdBy(c),
 	})
 	if err != nil {
+		if code := service.SMSErrorCode(err); code != "" {
+			return smsRejectedResponse(c, code, err)
+		}
+		if err == service.ErrRoomNotFound {
+			return utils.NotFoundResponse(c, "Room not found")
+		}
 		return utils.ErrorResponse(c, err.Error())
 	}
 
@@ -113,6 +127,9 @@ func (h *LinkHandler) CreateHLSLink(c *fiber.Ctx) error {
 		DaysExpired: req.DaysExpired,
 	})
 	if err != nil {
+		if err == service.ErrRoomNotFound {
+			return utils.NotFoundResponse(c, "Room not found")
+		}
 		return utils.ErrorResponse(c, err.Error())
 	}
 
@@ -254,3 +271,96 @@ func (h *LinkHandler) GetLinkList(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, links)
 }
+
+// RevokeLink disables a link before it expires
+// POST /link/revoke
+func (h *LinkHandler) RevokeLink(c *fiber.Ctx) error {
+	type RevokeRequest struct {
+		LinkID string `json:"linkID"`
+		Reason string `json:"reason"`
+	}
+
+	var req RevokeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	link, err := h.linkService.RevokeLink(c.Context(), req.LinkID, req.Reason, createdBy(c))
+	if err != nil {
+		return linkLifecycleErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, link)
+}
+
+// ExtendLink moves the expiry of a link
+// POST /link/extend
+func (h *LinkHandler) ExtendLink(c *fiber.Ctx) error {
+	type ExtendRequest struct {
+		LinkID      string `json:"linkID"`
+		DaysExpired int    `json:"daysExpired"`
+		DtmExpired  string `json:"dtmExpired"`
+	}
+
+	var req ExtendRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	link, err := h.linkService.ExtendLink(c.Context(), req.LinkID, req.DaysExpired, req.DtmExpired, createdBy(c))
+	if err != nil {
+		return linkLifecycleErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, link)
+}
+
+// EnableLink re-enables a revoked or expired link
+// POST /link/enable
+func (h *LinkHandler) EnableLink(c *fiber.Ctx) error {
+	type EnableRequest struct {
+		LinkID string `json:"linkID"`
+	}
+
+	var req EnableRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	link, err := h.linkService.EnableLink(c.Context(), req.LinkID, createdBy(c))
+	if err != nil {
+		return linkLifecycleErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, link)
+}
+
+// linkLifecycleErrorResponse maps link lifecycle errors to responses
+func linkLifecycleErrorResponse(c *fiber.Ctx, err error) error {
+	switch err {
+	case service.ErrLinkNotFound:
+		return utils.NotFoundResponse(c, "Link not found")
+	case service.ErrInvalidExpiry:
+		return utils.BadRequestResponse(c, err.Error())
+	case service.ErrLinkDisabled, service.ErrLinkEnabled, service.ErrLinkExpired:
+		return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+	}
+	return utils.ErrorResponse(c, err.Error())
+}
+
+// smsRejectedResponse reports a blocked or rate limited SMS with its error code
+func smsRejectedResponse(c *fiber.Ctx, code string, err error) error {
+	status := fiber.StatusTooManyRequests
+	if code == service.SMSCodeBlocked {
+		status = fiber.StatusForbidden
+	}
+	return utils.ErrorResponseWithCode(c, status, code, err.Error())
+}
+
+// createdBy returns the user name of the authenticated caller
+func createdBy(c *fiber.Ctx) string {
+	if claims := middleware.GetUserFromContext(c); claims != nil {
+		return claims.UserName
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+
+	"api-gateway-go/internal/service"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PlaybackHandler handles recording playback routes
+type PlaybackHandler struct {
+	playbackService *service.PlaybackService
+}
+
+// NewPlaybackHandler creates a new PlaybackHandler
+func NewPlaybackHandler(playbackService *service.PlaybackService) *PlaybackHandler {
+	return &PlaybackHandler{
+		playbackService: playbackService,
+	}
+}
+
+// ResolveLink resolves an HLS link to signed playback URLs for its room's recordings
+// POST /playback/link
+func (h *PlaybackHandler) ResolveLink(c *fiber.Ctx) error {
+	type ResolveRequest struct {
+		LinkID   string `json:"linkID"`
+		Password string `json:"password"`
+		UserName string `json:"userName"`
+	}
+
+	var req ResolveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	if req.LinkID == "" {
+		return utils.BadRequestResponse(c, "Link ID required")
+	}
+
+	result, err := h.playbackService.ResolveLink(c.Context(), service.RedeemLinkOptions{
+		LinkID:   req.LinkID,
+		Password: req.Password,
+		UserName: req.UserName,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrPlaybackNotHLSLink:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusForbidden, err.Error())
+		case service.ErrPlaybackNotConfigured:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusServiceUnavailable, err.Error())
+		}
+		return linkRedeemErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, result)
+}
+
+// ServeFile serves a playlist, segment or thumbnail of a recording through a signed URL
+// GET /playback/:recordId/*
+func (h *PlaybackHandler) ServeFile(c *fiber.Ctx) error {
+	recordID, err := strconv.Atoi(c.Params("recordId"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid record ID")
+	}
+
+	file, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid file path")
+	}
+
+	playback, err := h.playbackService.OpenFile(c.Context(), recordID, file, c.Query("expires"), c.Query("sig"))
+	if err != nil {
+		switch err {
+		case service.ErrPlaybackSignature:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusForbidden, err.Error())
+		case service.ErrPlaybackNotFound:
+			return utils.NotFoundResponse(c, "Recording file not found")
+		case service.ErrPlaybackNotConfigured:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusServiceUnavailable, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	// Signed URLs expire, so shared caches must not keep serving them
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	if playback.Content != nil {
+		c.Set(fiber.HeaderContentType, playback.ContentType)
+		return c.Send(playback.Content)
+	}
+
+	if err := c.SendFile(playback.Path); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, playback.ContentType)
+	return nil
+}
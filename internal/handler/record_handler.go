@@ -3,6 +3,7 @@ package handler
 import (
 	"strconv"
 
+	"api-gateway-go/internal/repository"
 	"api-gateway-go/internal/service"
 	"api-gateway-go/pkg/utils"
 
@@ -12,22 +13,36 @@ import (
 // RecordHandler handles recording routes
 type RecordHandler struct {
 	recordService *service.RecordService
+	recordQueue   *service.RecordQueueService
+	encode        *service.EncodeService
+	retention     *service.RecordRetentionService
 }
 
 // NewRecordHandler creates a new RecordHandler
-func NewRecordHandler(recordService *service.RecordService) *RecordHandler {
+func NewRecordHandler(recordService *service.RecordService, recordQueue *service.RecordQueueService, encode *service.EncodeService, retention *service.RecordRetentionService) *RecordHandler {
 	return &RecordHandler{
 		recordService: recordService,
+		recordQueue:   recordQueue,
+		encode:        encode,
+		retention:     retention,
 	}
 }
 
-// StartRecord starts a recording
+// StartRecord starts a recording, or queues it when every egress slot is in use
 // POST /record/start
 func (h *RecordHandler) StartRecord(c *fiber.Ctx) error {
 	type StartRequest struct {
-		Room       string `json:"room"`
-		RecordType string `json:"recordType"`
-		FilePath   string `json:"filePath"`
+		Room                  string `json:"room"`
+		RecordType            string `json:"recordType"`
+		FilePath              string `json:"filePath"`
+		Identity              string `json:"identity"`
+		AudioTrackID          string `json:"audioTrackId"`
+		VideoTrackID          string `json:"videoTrackId"`
+		TrackID               string `json:"trackId"`
+		EncodingOptionsPreset string `json:"encodingOptionsPreset"`
+		Layout                string `json:"layout"`
+		FileType              string `json:"fileType"`
+		Priority              int    `json:"priority"`
 	}
 
 	var req StartRequest
@@ -39,22 +54,44 @@ func (h *RecordHandler) StartRecord(c *fiber.Ctx) error {
 		return utils.BadRequestResponse(c, "Room name required")
 	}
 
-	info, err := h.recordService.StartRecord(c.Context(), service.StartRecordOptions{
-		Room:       req.Room,
-		RecordType: req.RecordType,
-		FilePath:   req.FilePath,
+	result, err := h.recordQueue.Request(c.Context(), service.RecordRequestOptions{
+		StartRecordOptions: service.StartRecordOptions{
+			Room:                  req.Room,
+			RecordType:            req.RecordType,
+			FilePath:              req.FilePath,
+			Identity:              req.Identity,
+			AudioTrackID:          req.AudioTrackID,
+			VideoTrackID:          req.VideoTrackID,
+			TrackID:               req.TrackID,
+			EncodingOptionsPreset: req.EncodingOptionsPreset,
+			Layout:                req.Layout,
+			FileType:              req.FileType,
+		},
+		Priority:  req.Priority,
+		CreatedBy: createdBy(c),
 	})
 	if err != nil {
-		if err == service.ErrEgressLimit {
-			return utils.ErrorResponseWithStatus(c, fiber.StatusTooManyRequests, "Egress limit reached")
+		if service.IsRecordSettingsError(err) {
+			return utils.BadRequestResponse(c, err.Error())
 		}
 		return utils.ErrorResponse(c, err.Error())
 	}
 
+	// Past the egress limit the recording waits in the queue
+	if result.Queued != nil {
+		c.Status(fiber.StatusAccepted)
+		return utils.SuccessResponse(c, fiber.Map{
+			"queueId":  result.Queued.ID,
+			"room":     req.Room,
+			"status":   result.Queued.Status,
+			"priority": result.Queued.Priority,
+		})
+	}
+
 	return utils.SuccessResponse(c, fiber.Map{
-		"egressId": info.EgressId,
+		"egressId": result.Egress.EgressId,
 		"room":     req.Room,
-		"status":   info.Status.String(),
+		"status":   result.Egress.Status.String(),
 	})
 }
 
@@ -181,10 +218,10 @@ func (h *RecordHandler) CheckEgressAvailable(c *fiber.Ctx) error {
 	})
 }
 
-// GetRecordQueue gets the record queue
+// GetRecordQueue gets the recordings waiting for an egress slot, in start order
 // GET /record/queue
 func (h *RecordHandler) GetRecordQueue(c *fiber.Ctx) error {
-	queue, err := h.recordService.GetRecordQueue(c.Context())
+	queue, err := h.recordQueue.ListQueued(c.Context())
 	if err != nil {
 		return utils.ErrorResponse(c, err.Error())
 	}
@@ -192,6 +229,212 @@ func (h *RecordHandler) GetRecordQueue(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, queue)
 }
 
+// CancelQueuedRecord cancels a recording waiting for an egress slot
+// DELETE /record/queue/:id
+func (h *RecordHandler) CancelQueuedRecord(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid queue ID")
+	}
+
+	entry, err := h.recordQueue.Cancel(c.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case service.ErrRecordQueueNotFound:
+			return utils.NotFoundResponse(c, "Queued recording not found")
+		case service.ErrRecordQueueNotCancellable:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, entry)
+}
+
+// ListEncodeJobs lists the HLS encode jobs of completed recordings
+// GET /record/encode
+func (h *RecordHandler) ListEncodeJobs(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "100"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	jobs, err := h.encode.ListJobs(c.Context(), repository.EncodeJobFilter{
+		Room:   c.Query("room"),
+		Status: c.Query("status"),
+	}, limit, offset)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, jobs)
+}
+
+// RetryEncodeJob queues a failed encode job again
+// POST /record/encode/:id/retry
+func (h *RecordHandler) RetryEncodeJob(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid encode job ID")
+	}
+
+	job, err := h.encode.Retry(c.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case service.ErrEncodeJobNotFound:
+			return utils.NotFoundResponse(c, "Encode job not found")
+		case service.ErrEncodeJobNotRetryable:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		case service.ErrEncodeNotConfigured:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusServiceUnavailable, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, job)
+}
+
+// DeleteRecord deletes a finished recording with its files. The deletion is audited.
+// DELETE /record/:id
+func (h *RecordHandler) DeleteRecord(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid record ID")
+	}
+
+	type DeleteRequest struct {
+		Reason string `json:"reason"`
+	}
+
+	var req DeleteRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.BadRequestResponse(c, "Invalid request body")
+		}
+	}
+	if req.Reason == "" {
+		req.Reason = c.Query("reason")
+	}
+	if req.Reason == "" {
+		return utils.BadRequestResponse(c, "Reason required")
+	}
+
+	err = h.retention.DeleteRecord(c.Context(), id, req.Reason, createdBy(c))
+	if err != nil {
+		switch err {
+		case service.ErrRecordNotFound:
+			return utils.NotFoundResponse(c, "Record not found")
+		case service.ErrRecordActive, service.ErrRecordOnLegalHold:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"deleted": true,
+	})
+}
+
+// ListRetentionPolicies lists the per-service recording retention policies
+// GET /record/retention
+func (h *RecordHandler) ListRetentionPolicies(c *fiber.Ctx) error {
+	policies, err := h.retention.ListPolicies(c.Context())
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, policies)
+}
+
+// SetRetentionPolicy creates or replaces the recording retention policy of a service
+// PUT /record/retention/:service
+func (h *RecordHandler) SetRetentionPolicy(c *fiber.Ctx) error {
+	serviceID, err := strconv.Atoi(c.Params("service"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid service")
+	}
+
+	type PolicyRequest struct {
+		RetainDays  int   `json:"retainDays"`
+		ArchiveDays int   `json:"archiveDays"`
+		Enabled     *bool `json:"enabled"`
+	}
+
+	var req PolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	policy, err := h.retention.SetPolicy(c.Context(), service.RetentionPolicyOptions{
+		Service:     serviceID,
+		RetainDays:  req.RetainDays,
+		ArchiveDays: req.ArchiveDays,
+		Enabled:     enabled,
+		UpdatedBy:   createdBy(c),
+	})
+	if err != nil {
+		if err == service.ErrInvalidRetentionPolicy {
+			return utils.BadRequestResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, policy)
+}
+
+// DeleteRetentionPolicy removes the recording retention policy of a service
+// DELETE /record/retention/:service
+func (h *RecordHandler) DeleteRetentionPolicy(c *fiber.Ctx) error {
+	serviceID, err := strconv.Atoi(c.Params("service"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid service")
+	}
+
+	if err := h.retention.DeletePolicy(c.Context(), serviceID); err != nil {
+		if err == service.ErrRetentionPolicyNotFound {
+			return utils.NotFoundResponse(c, "Retention policy not found")
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"deleted": true,
+	})
+}
+
+// RunRetention applies the retention policies now instead of waiting for the cron job
+// POST /record/retention/run
+func (h *RecordHandler) RunRetention(c *fiber.Ctx) error {
+	report, err := h.retention.Apply(c.Context())
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, report)
+}
+
+// ListRecordAudit lists the audit trail of recording deletions, archives and legal holds
+// GET /record/audit
+func (h *RecordHandler) ListRecordAudit(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "100"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	recordID, _ := strconv.Atoi(c.Query("recordId", "0"))
+
+	entries, err := h.retention.ListAudit(c.Context(), repository.RecordAuditFilter{
+		Action:   c.Query("action"),
+		Room:     c.Query("room"),
+		RecordID: recordID,
+	}, limit, offset)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, entries)
+}
+
 // GetActiveRecordCount gets count of active recordings
 // GET /record/activecount
 func (h *RecordHandler) GetActiveRecordCount(c *fiber.Ctx) error {
@@ -130,6 +130,9 @@ func (h *RoomHandler) DeleteRoom(c *fiber.Ctx) error {
 
 	err := h.roomService.DeleteRoom(c.Context(), req.Room)
 	if err != nil {
+		if err == service.ErrRoomLegalHold {
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		}
 		return utils.ErrorResponse(c, err.Error())
 	}
 
@@ -231,6 +234,8 @@ func (h *RoomHandler) CreateRoom(c *fiber.Ctx) error {
 		AutoRecord            int    `json:"autoRecord"`
 		RecordType            string `json:"recordType"`
 		EncodingOptionsPreset string `json:"encodingOptionsPreset"`
+		RecordLayout          string `json:"recordLayout"`
+		RecordFileType        string `json:"recordFileType"`
 		ChatEnabled           int    `json:"chatEnabled"`
 		WebSocketURL          string `json:"webSocketURL"`
 		UserAgent             string `json:"userAgent"`
@@ -248,12 +253,17 @@ func (h *RoomHandler) CreateRoom(c *fiber.Ctx) error {
 		AutoRecord:            req.AutoRecord,
 		RecordType:            req.RecordType,
 		EncodingOptionsPreset: req.EncodingOptionsPreset,
+		RecordLayout:          req.RecordLayout,
+		RecordFileType:        req.RecordFileType,
 		ChatEnabled:           req.ChatEnabled,
 		WebSocketURL:          req.WebSocketURL,
 		UserAgent:             req.UserAgent,
 		DaysExpired:           req.DaysExpired,
 	})
 	if err != nil {
+		if service.IsRecordSettingsError(err) {
+			return utils.BadRequestResponse(c, err.Error())
+		}
 		return utils.ErrorResponse(c, err.Error())
 	}
 
@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"strconv"
+
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/internal/service"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SMSHandler handles SMS routes
+type SMSHandler struct {
+	smsOutbox   *service.SmsOutboxService
+	smsTemplate *service.SmsTemplateService
+	smsLimiter  *service.SmsLimiterService
+}
+
+// NewSMSHandler creates a new SMSHandler
+func NewSMSHandler(smsOutbox *service.SmsOutboxService, smsTemplate *service.SmsTemplateService, smsLimiter *service.SmsLimiterService) *SMSHandler {
+	return &SMSHandler{
+		smsOutbox:   smsOutbox,
+		smsTemplate: smsTemplate,
+		smsLimiter:  smsLimiter,
+	}
+}
+
+// ListOutbox lists queued SMS
+// GET /sms/outbox
+func (h *SMSHandler) ListOutbox(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "100"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	messages, err := h.smsOutbox.ListMessages(c.Context(), repository.SmsOutboxFilter{
+		Room:   c.Query("room"),
+		LinkID: c.Query("linkID"),
+		Mobile: c.Query("mobile"),
+		Status: c.Query("status"),
+	}, limit, offset)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, messages)
+}
+
+// GetOutbox gets a queued SMS
+// GET /sms/outbox/:id
+func (h *SMSHandler) GetOutbox(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid SMS ID")
+	}
+
+	msg, err := h.smsOutbox.GetMessage(c.Context(), id)
+	if err != nil {
+		if err == service.ErrSMSOutboxNotFound {
+			return utils.NotFoundResponse(c, "SMS not found")
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, msg)
+}
+
+// RetryOutbox requeues a queued SMS and attempts delivery
+// POST /sms/outbox/:id/retry
+func (h *SMSHandler) RetryOutbox(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid SMS ID")
+	}
+
+	msg, err := h.smsOutbox.Retry(c.Context(), id)
+	if err != nil {
+		switch err {
+		case service.ErrSMSOutboxNotFound:
+			return utils.NotFoundResponse(c, "SMS not found")
+		case service.ErrSMSOutboxNotRetryable:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, msg)
+}
+
+// CancelOutbox cancels a pending SMS
+// POST /sms/outbox/:id/cancel
+func (h *SMSHandler) CancelOutbox(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid SMS ID")
+	}
+
+	msg, err := h.smsOutbox.Cancel(c.Context(), id)
+	if err != nil {
+		switch err {
+		case service.ErrSMSOutboxNotFound:
+			return utils.NotFoundResponse(c, "SMS not found")
+		case service.ErrSMSOutboxNotCancellable:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, msg)
+}
+
+// SMSTemplateRequest is the request body for creating or updating a template
+type SMSTemplateRequest struct {
+	Service     int    `json:"service"`
+	MessageType string `json:"messageType"`
+	Locale      string `json:"locale"`
+	Body        string `json:"body"`
+	Enabled     *bool  `json:"enabled"`
+}
+
+// ListTemplates lists SMS templates
+// GET /sms/template
+func (h *SMSHandler) ListTemplates(c *fiber.Ctx) error {
+	serviceID, err := strconv.Atoi(c.Query("service", "-1"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid service")
+	}
+
+	templates, err := h.smsTemplate.ListTemplates(c.Context(), serviceID, c.Query("messageType"))
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, templates)
+}
+
+// GetTemplate gets an SMS template
+// GET /sms/template/:id
+func (h *SMSHandler) GetTemplate(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid template ID")
+	}
+
+	tpl, err := h.smsTemplate.GetTemplate(c.Context(), id)
+	if err != nil {
+		return templateErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, tpl)
+}
+
+// CreateTemplate creates an SMS template
+// POST /sms/template
+func (h *SMSHandler) CreateTemplate(c *fiber.Ctx) error {
+	var req SMSTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	tpl, err := h.smsTemplate.CreateTemplate(c.Context(), service.SMSTemplateOptions{
+		Service:     req.Service,
+		MessageType: req.MessageType,
+		Locale:      req.Locale,
+		Body:        req.Body,
+		Enabled:     req.Enabled,
+		UpdatedBy:   createdBy(c),
+	})
+	if err != nil {
+		return templateErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, tpl)
+}
+
+// UpdateTemplate updates an SMS template
+// PUT /sms/template/:id
+func (h *SMSHandler) UpdateTemplate(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid template ID")
+	}
+
+	var req SMSTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	tpl, err := h.smsTemplate.UpdateTemplate(c.Context(), id, service.SMSTemplateOptions{
+		Service:     req.Service,
+		MessageType: req.MessageType,
+		Locale:      req.Locale,
+		Body:        req.Body,
+		Enabled:     req.Enabled,
+		UpdatedBy:   createdBy(c),
+	})
+	if err != nil {
+		return templateErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, tpl)
+}
+
+// DeleteTemplate deletes an SMS template
+// DELETE /sms/template/:id
+func (h *SMSHandler) DeleteTemplate(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid template ID")
+	}
+
+	if err := h.smsTemplate.DeleteTemplate(c.Context(), id); err != nil {
+		return templateErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"deleted": true,
+	})
+}
+
+// PreviewTemplate renders a template body or a stored template with sample variables
+// POST /sms/template/preview
+func (h *SMSHandler) PreviewTemplate(c *fiber.Ctx) error {
+	type PreviewRequest struct {
+		Service     int               `json:"service"`
+		MessageType string            `json:"messageType"`
+		Locale      string            `json:"locale"`
+		Body        string            `json:"body"`
+		Variables   map[string]string `json:"variables"`
+	}
+
+	var req PreviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	if req.Body != "" {
+		return utils.SuccessResponse(c, h.smsTemplate.Preview(req.Body, req.Variables))
+	}
+
+	rendered, err := h.smsTemplate.PreviewStored(c.Context(), req.Service, req.MessageType, req.Locale, req.Variables)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+	if rendered == nil {
+		return utils.NotFoundResponse(c, "SMS template not found")
+	}
+
+	return utils.SuccessResponse(c, rendered)
+}
+
+// ListBlocklist lists blocked mobile numbers
+// GET /sms/blocklist
+func (h *SMSHandler) ListBlocklist(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "100"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	entries, err := h.smsLimiter.ListBlocked(c.Context(), c.Query("mobile"), limit, offset)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, entries)
+}
+
+// AddBlocklist blocks a mobile number from receiving SMS
+// POST /sms/blocklist
+func (h *SMSHandler) AddBlocklist(c *fiber.Ctx) error {
+	type BlockRequest struct {
+		Mobile  string `json:"mobile"`
+		Service int    `json:"service"`
+		Source  string `json:"source"`
+		Reason  string `json:"reason"`
+	}
+
+	var req BlockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	entry, err := h.smsLimiter.Block(c.Context(), service.BlockMobileOptions{
+		Mobile:    req.Mobile,
+		Service:   req.Service,
+		Source:    req.Source,
+		Reason:    req.Reason,
+		CreatedBy: createdBy(c),
+	})
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMobile:
+			return utils.BadRequestResponse(c, err.Error())
+		case service.ErrSMSBlocklistGlobal:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, entry)
+}
+
+// RemoveBlocklist unblocks a mobile number
+// DELETE /sms/blocklist/:id
+func (h *SMSHandler) RemoveBlocklist(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid blocklist ID")
+	}
+
+	if err := h.smsLimiter.Unblock(c.Context(), id); err != nil {
+		if err == service.ErrSMSBlocklistNotFound {
+			return utils.NotFoundResponse(c, "Blocklist entry not found")
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"deleted": true,
+	})
+}
+
+// templateErrorResponse maps template errors to responses
+func templateErrorResponse(c *fiber.Ctx, err error) error {
+	switch err {
+	case service.ErrSMSTemplateNotFound:
+		return utils.NotFoundResponse(c, "SMS template not found")
+	case service.ErrSMSTemplateInvalidType,
+		service.ErrSMSTemplateInvalidLocale,
+		service.ErrSMSTemplateBodyRequired,
+		service.ErrSMSTemplateUnknownVariable:
+		return utils.BadRequestResponse(c, err.Error())
+	case service.ErrSMSTemplateDefaultAdmin:
+		return utils.ErrorResponseWithStatus(c, fiber.StatusForbidden, err.Error())
+	}
+	return utils.ErrorResponse(c, err.Error())
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"strconv"
+
+	"api-gateway-go/internal/service"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StaffHandler handles staff account routes
+type StaffHandler struct {
+	staffService *service.StaffService
+}
+
+// NewStaffHandler creates a new StaffHandler
+func NewStaffHandler(staffService *service.StaffService) *StaffHandler {
+	return &StaffHandler{
+		staffService: staffService,
+	}
+}
+
+// ListStaff lists staff accounts
+// GET /staff
+func (h *StaffHandler) ListStaff(c *fiber.Ctx) error {
+	svc, err := strconv.Atoi(c.Query("service", "-1"))
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid service")
+	}
+
+	staff, err := h.staffService.ListStaff(c.Context(), svc)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	return utils.SuccessResponse(c, staff)
+}
+
+// CreateStaff creates a staff account
+// POST /staff
+func (h *StaffHandler) CreateStaff(c *fiber.Ctx) error {
+	type CreateRequest struct {
+		UserName     string `json:"userName"`
+		Password     string `json:"password"`
+		DisplayName  string `json:"displayName"`
+		Role         string `json:"role"`
+		Service      int    `json:"service"`
+		Organization string `json:"organization"`
+	}
+
+	var req CreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	staff, err := h.staffService.CreateStaff(c.Context(), service.CreateStaffOptions{
+		UserName:     req.UserName,
+		Password:     req.Password,
+		DisplayName:  req.DisplayName,
+		Role:         req.Role,
+		Service:      req.Service,
+		Organization: req.Organization,
+		CreatedBy:    createdBy(c),
+	})
+	if err != nil {
+		return staffErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, staff)
+}
+
+// ChangePassword sets a new password for a staff account
+// PUT /staff/:id/password
+func (h *StaffHandler) ChangePassword(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid staff ID")
+	}
+
+	type PasswordRequest struct {
+		Password string `json:"password"`
+	}
+
+	var req PasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	if err := h.staffService.ChangePassword(c.Context(), uint(id), req.Password); err != nil {
+		return staffErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"updated": true,
+	})
+}
+
+// SetRole changes the role of a staff account
+// PUT /staff/:id/role
+func (h *StaffHandler) SetRole(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid staff ID")
+	}
+
+	type RoleRequest struct {
+		Role string `json:"role"`
+	}
+
+	var req RoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	if err := h.staffService.SetRole(c.Context(), uint(id), req.Role); err != nil {
+		return staffErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"role": req.Role,
+	})
+}
+
+// SetEnabled enables or disables a staff account
+// PUT /staff/:id/enabled
+func (h *StaffHandler) SetEnabled(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid staff ID")
+	}
+
+	type EnabledRequest struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	var req EnabledRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	if err := h.staffService.SetEnabled(c.Context(), uint(id), req.Enabled); err != nil {
+		return staffErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"enabled": req.Enabled,
+	})
+}
+
+// Unlock clears a lockout caused by failed logins
+// POST /staff/:id/unlock
+func (h *StaffHandler) Unlock(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid staff ID")
+	}
+
+	if err := h.staffService.UnlockStaff(c.Context(), uint(id)); err != nil {
+		return staffErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"unlocked": true,
+	})
+}
+
+// RevokeSessions signs a staff account out everywhere
+// POST /staff/:id/revokeall
+func (h *StaffHandler) RevokeSessions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid staff ID")
+	}
+
+	if err := h.staffService.RevokeSessions(c.Context(), uint(id)); err != nil {
+		return staffErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"revoked": true,
+	})
+}
+
+// staffErrorResponse maps staff account errors to responses
+func staffErrorResponse(c *fiber.Ctx, err error) error {
+	switch err {
+	case service.ErrStaffNotFound:
+		return utils.NotFoundResponse(c, "Staff account not found")
+	case service.ErrStaffExists:
+		return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+	case service.ErrUserNameRequired, service.ErrWeakPassword, service.ErrInvalidRole:
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	return utils.ErrorResponse(c, err.Error())
+}
@@ -85,19 +85,27 @@ func (h *SystemHandler) HealthCheck(c *fiber.Ctx) error {
 	})
 }
 
-// GetStatus handles status route
+// GetStatus handles status route. It summarizes the last recording
+// reconciliation; the discrepancies themselves are on /status/reconcile.
 // GET /status
 func (h *SystemHandler) GetStatus(c *fiber.Ctx) error {
 	cronStatus := h.crontab.GetStatus()
 
 	return c.JSON(fiber.Map{
-		"status":      "running",
-		"environment": h.cfg.Environment,
-		"port":        h.cfg.Port,
-		"cron":        cronStatus,
+		"status":          "running",
+		"environment":     h.cfg.Environment,
+		"port":            h.cfg.Port,
+		"cron":            cronStatus,
+		"recordReconcile": h.crontab.LastRecordReconcile().Summary(),
 	})
 }
 
+// GetRecordReconcile returns the discrepancies found by the last recording reconciliation
+// GET /status/reconcile
+func (h *SystemHandler) GetRecordReconcile(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, h.crontab.LastRecordReconcile())
+}
+
 // GetServiceInfo handles service info
 // GET /service
 func (h *SystemHandler) GetServiceInfo(c *fiber.Ctx) error {
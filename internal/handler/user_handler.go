@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"api-gateway-go/internal/middleware"
 	"api-gateway-go/internal/service"
 	"api-gateway-go/pkg/utils"
 
@@ -10,12 +11,16 @@ import (
 // UserHandler handles user routes
 type UserHandler struct {
 	userService *service.UserService
+	linkService *service.LinkService
+	waitingRoom *service.WaitingRoomService
 }
 
 // NewUserHandler creates a new UserHandler
-func NewUserHandler(userService *service.UserService) *UserHandler {
+func NewUserHandler(userService *service.UserService, linkService *service.LinkService, waitingRoom *service.WaitingRoomService) *UserHandler {
 	return &UserHandler{
 		userService: userService,
+		linkService: linkService,
+		waitingRoom: waitingRoom,
 	}
 }
 
@@ -80,6 +85,7 @@ func (h *UserHandler) GenerateUser(c *fiber.Ctx) error {
 		UserType  string `json:"userType"`
 		UserAgent string `json:"userAgent"`
 		ServiceID int    `json:"serviceId"`
+		Password  string `json:"password"`
 	}
 
 	var req GenerateRequest
@@ -87,14 +93,27 @@ func (h *UserHandler) GenerateUser(c *fiber.Ctx) error {
 		return utils.BadRequestResponse(c, "Invalid request body")
 	}
 
-	result, err := h.userService.GenerateUser(c.Context(), service.GenerateUserOptions{
+	opts := service.GenerateUserOptions{
 		LinkID:    req.LinkID,
 		Room:      req.Room,
 		UserName:  req.UserName,
 		UserType:  req.UserType,
 		UserAgent: req.UserAgent,
 		ServiceID: req.ServiceID,
-	})
+	}
+
+	// Staff may join a room of their tenant directly, everyone else must redeem a link
+	direct, err := h.joinsDirectly(c, req.LinkID, req.Room)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+	if !direct {
+		if admitted, err := h.admitLink(c, &opts, req.Password); !admitted {
+			return err
+		}
+	}
+
+	result, err := h.userService.GenerateUser(c.Context(), opts)
 	if err != nil {
 		return utils.ErrorResponse(c, err.Error())
 	}
@@ -106,10 +125,12 @@ func (h *UserHandler) GenerateUser(c *fiber.Ctx) error {
 // POST /user/joingenerate
 func (h *UserHandler) JoinGenerate(c *fiber.Ctx) error {
 	type JoinRequest struct {
+		LinkID    string `json:"linkID"`
 		Room      string `json:"room"`
 		UserName  string `json:"userName"`
 		UserType  string `json:"userType"`
 		UserAgent string `json:"userAgent"`
+		Password  string `json:"password"`
 	}
 
 	var req JoinRequest
@@ -117,12 +138,26 @@ func (h *UserHandler) JoinGenerate(c *fiber.Ctx) error {
 		return utils.BadRequestResponse(c, "Invalid request body")
 	}
 
-	result, err := h.userService.GenerateUser(c.Context(), service.GenerateUserOptions{
+	opts := service.GenerateUserOptions{
+		LinkID:    req.LinkID,
 		Room:      req.Room,
 		UserName:  req.UserName,
 		UserType:  req.UserType,
 		UserAgent: req.UserAgent,
-	})
+	}
+
+	// Staff may join a room of their tenant directly, everyone else must redeem a link
+	direct, err := h.joinsDirectly(c, req.LinkID, req.Room)
+	if err != nil {
+		return utils.ErrorResponse(c, err.Error())
+	}
+	if !direct {
+		if admitted, err := h.admitLink(c, &opts, req.Password); !admitted {
+			return err
+		}
+	}
+
+	result, err := h.userService.GenerateUser(c.Context(), opts)
 	if err != nil {
 		return utils.ErrorResponse(c, err.Error())
 	}
@@ -273,3 +308,76 @@ func (h *UserHandler) HandleTrack(c *fiber.Ctx) error {
 		"updated": true,
 	})
 }
+
+// joinsDirectly reports whether the caller may join a room without a link: only
+// callers allowed to manage participants, and only in a room of their own tenant
+func (h *UserHandler) joinsDirectly(c *fiber.Ctx, linkID, room string) (bool, error) {
+	if linkID != "" || !service.ClaimsHavePermission(middleware.GetUserFromContext(c), service.PermParticipantManage) {
+		return false, nil
+	}
+	return h.userService.RoomInTenant(c.Context(), room)
+}
+
+// admitLink checks the link in opts and fixes the room and user type from it.
+// Guests of links that require join permission are put in the waiting room instead;
+// their link is only used up once an agent lets them in.
+// Returns false when a response has already been written.
+func (h *UserHandler) admitLink(c *fiber.Ctx, opts *service.GenerateUserOptions, password string) (bool, error) {
+	link, err := h.linkService.CheckLink(c.Context(), service.RedeemLinkOptions{
+		LinkID:   opts.LinkID,
+		Password: password,
+		UserName: opts.UserName,
+	})
+	if err != nil {
+		return false, linkRedeemErrorResponse(c, err)
+	}
+
+	opts.Room = utils.NullStringValue(link.Room)
+	opts.UserType = service.JoinUserType(link, opts.UserType)
+
+	if !service.RequiresPermission(link) {
+		if _, err := h.linkService.ClaimLink(c.Context(), opts.LinkID, opts.UserName); err != nil {
+			return false, linkRedeemErrorResponse(c, err)
+		}
+		return true, nil
+	}
+
+	req, err := h.waitingRoom.CreateRequest(c.Context(), service.CreateJoinRequestOptions{
+		Link:      link,
+		UserName:  opts.UserName,
+		UserType:  opts.UserType,
+		UserAgent: opts.UserAgent,
+	})
+	if err != nil {
+		return false, waitingRoomErrorResponse(c, err)
+	}
+
+	return false, c.Status(fiber.StatusAccepted).JSON(utils.Response{
+		Status:  "OK",
+		Code:    "JOIN_PERMISSION_REQUIRED",
+		Message: "Waiting for an agent to approve the join request",
+		Data:    req,
+	})
+}
+
+// linkRedeemErrorResponse maps link redemption errors to responses with an error code
+func linkRedeemErrorResponse(c *fiber.Ctx, err error) error {
+	code := service.LinkErrorCode(err)
+	if code == "" {
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	status := fiber.StatusForbidden
+	switch code {
+	case service.LinkCodeNotFound:
+		status = fiber.StatusNotFound
+	case service.LinkCodeExpired, service.LinkCodeUsed:
+		status = fiber.StatusGone
+	case service.LinkCodePasswordRequired, service.LinkCodePasswordInvalid:
+		status = fiber.StatusUnauthorized
+	case service.LinkCodeUserNameRequired:
+		status = fiber.StatusBadRequest
+	}
+
+	return utils.ErrorResponseWithCode(c, status, code, err.Error())
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"api-gateway-go/internal/service"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WaitingRoomHandler handles waiting room routes
+type WaitingRoomHandler struct {
+	waitingRoom *service.WaitingRoomService
+	userService *service.UserService
+}
+
+// NewWaitingRoomHandler creates a new WaitingRoomHandler
+func NewWaitingRoomHandler(waitingRoom *service.WaitingRoomService, userService *service.UserService) *WaitingRoomHandler {
+	return &WaitingRoomHandler{
+		waitingRoom: waitingRoom,
+		userService: userService,
+	}
+}
+
+// ListPending lists guests waiting to join a room
+// GET /waitingroom
+func (h *WaitingRoomHandler) ListPending(c *fiber.Ctx) error {
+	room := c.Query("room")
+	if room == "" {
+		return utils.BadRequestResponse(c, "Room required")
+	}
+
+	requests, err := h.waitingRoom.ListPending(c.Context(), room)
+	if err != nil {
+		return waitingRoomErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, requests)
+}
+
+// GetStatus returns the status of a join request to the guest holding its
+// poll token, sent in the X-Poll-Token header or the pollToken query parameter.
+// Once approved, the first call releases the LiveKit token for the guest.
+// GET /waitingroom/:id
+func (h *WaitingRoomHandler) GetStatus(c *fiber.Ctx) error {
+	pollToken := c.Get("X-Poll-Token")
+	if pollToken == "" {
+		pollToken = c.Query("pollToken")
+	}
+
+	req, err := h.waitingRoom.GetGuestRequest(c.Context(), c.Params("id"), pollToken)
+	if err != nil {
+		return waitingRoomErrorResponse(c, err)
+	}
+
+	if req.Status != service.JoinStatusApproved {
+		return utils.SuccessResponse(c, req)
+	}
+
+	var result *service.GenerateUserResult
+	req, err = h.waitingRoom.Claim(c.Context(), req.ID, func(req *service.JoinRequest) error {
+		var err error
+		result, err = h.userService.GenerateUser(c.Context(), service.GenerateUserOptions{
+			LinkID:    req.LinkID,
+			Room:      req.Room,
+			UserName:  req.UserName,
+			UserType:  req.UserType,
+			UserAgent: req.UserAgent,
+		})
+		return err
+	})
+	if err != nil {
+		return waitingRoomErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"request": req,
+		"user":    result,
+	})
+}
+
+// Approve lets a waiting guest into the room
+// POST /waitingroom/:id/approve
+func (h *WaitingRoomHandler) Approve(c *fiber.Ctx) error {
+	req, err := h.waitingRoom.Approve(c.Context(), c.Params("id"), createdBy(c))
+	if err != nil {
+		return waitingRoomErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, req)
+}
+
+// Deny refuses a waiting guest
+// POST /waitingroom/:id/deny
+func (h *WaitingRoomHandler) Deny(c *fiber.Ctx) error {
+	type DenyRequest struct {
+		Reason string `json:"reason"`
+	}
+
+	var body DenyRequest
+	if err := c.BodyParser(&body); err != nil && len(c.Body()) > 0 {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	req, err := h.waitingRoom.Deny(c.Context(), c.Params("id"), createdBy(c), body.Reason)
+	if err != nil {
+		return waitingRoomErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, req)
+}
+
+// waitingRoomErrorResponse maps waiting room errors to responses
+func waitingRoomErrorResponse(c *fiber.Ctx, err error) error {
+	switch err {
+	case service.ErrJoinRequestNotFound:
+		return utils.NotFoundResponse(c, "Join request not found")
+	case service.ErrJoinRequestDecided, service.ErrJoinRequestNotApproved:
+		return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+	case service.ErrJoinRequestAlreadyTaken:
+		return utils.ErrorResponseWithStatus(c, fiber.StatusGone, err.Error())
+	case service.ErrWaitingRoomUnavailable:
+		return utils.ErrorResponseWithStatus(c, fiber.StatusServiceUnavailable, err.Error())
+	}
+	// The link of an approved guest may have been used, disabled or expired meanwhile
+	if service.LinkErrorCode(err) != "" {
+		return linkRedeemErrorResponse(c, err)
+	}
+	return utils.ErrorResponse(c, err.Error())
+}
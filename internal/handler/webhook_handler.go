@@ -1,7 +1,13 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,39 +16,35 @@ import (
 	"api-gateway-go/internal/service"
 	"api-gateway-go/internal/socket"
 	"api-gateway-go/pkg/logger"
+	"api-gateway-go/pkg/metrics"
 	"api-gateway-go/pkg/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// TrackData stores track information for auto-recording
-type TrackData struct {
-	Room     string
-	Video    *livekit.TrackInfo
-	Audio    *livekit.TrackInfo
-	Record   int
-	Identity string
-}
-
 // WebhookHandler handles LiveKit webhook events
 type WebhookHandler struct {
 	roomService   *service.RoomService
 	userService   *service.UserService
 	recordService *service.RecordService
+	recordQueue   *service.RecordQueueService
+	autoRecord    *service.AutoRecordService
+	encode        *service.EncodeService
+	smsOutbox     *service.SmsOutboxService
+	webhookEvents *service.WebhookEventService
 	recordRepo    *repository.RecordRepository
 	livekitMgr    *config.LiveKitManager
 	cfg           *config.Config
 	socketHub     *socket.Hub
 
-	// Track data management for auto-recording
-	trackPublishedData map[string]*TrackData
-	trackDataMu        sync.RWMutex
-	participantTimers  map[string]*time.Timer
-	timersMu           sync.RWMutex
-	inactivityTimeout  time.Duration
-	maxTrackDataSize   int
+	participantTimers map[string]*time.Timer
+	timersMu          sync.RWMutex
+	inactivityTimeout time.Duration
 }
 
 // NewWebhookHandler creates a new WebhookHandler
@@ -50,22 +52,32 @@ func NewWebhookHandler(
 	roomService *service.RoomService,
 	userService *service.UserService,
 	recordService *service.RecordService,
+	recordQueue *service.RecordQueueService,
+	autoRecord *service.AutoRecordService,
+	encode *service.EncodeService,
+	smsOutbox *service.SmsOutboxService,
+	webhookEvents *service.WebhookEventService,
 	recordRepo *repository.RecordRepository,
 	livekitMgr *config.LiveKitManager,
 	cfg *config.Config,
 ) *WebhookHandler {
-	return &WebhookHandler{
-		roomService:        roomService,
-		userService:        userService,
-		recordService:      recordService,
-		recordRepo:         recordRepo,
-		livekitMgr:         livekitMgr,
-		cfg:                cfg,
-		trackPublishedData: make(map[string]*TrackData),
-		participantTimers:  make(map[string]*time.Timer),
-		inactivityTimeout:  30 * time.Minute,
-		maxTrackDataSize:   1000,
+	h := &WebhookHandler{
+		roomService:       roomService,
+		userService:       userService,
+		recordService:     recordService,
+		recordQueue:       recordQueue,
+		autoRecord:        autoRecord,
+		encode:            encode,
+		smsOutbox:         smsOutbox,
+		webhookEvents:     webhookEvents,
+		recordRepo:        recordRepo,
+		livekitMgr:        livekitMgr,
+		cfg:               cfg,
+		participantTimers: make(map[string]*time.Timer),
+		inactivityTimeout: 30 * time.Minute,
 	}
+	webhookEvents.SetProcessor(h.applyEvent)
+	return h
 }
 
 // SetSocketHub sets the socket hub for real-time updates
@@ -73,46 +85,135 @@ func (h *WebhookHandler) SetSocketHub(hub *socket.Hub) {
 	h.socketHub = hub
 }
 
-// HandleLiveKitWebhook handles LiveKit webhook events
+// HandleLiveKitWebhook handles LiveKit webhook events. Calls must carry a JWT
+// signed with the LiveKit API key/secret whose sha256 claim matches the body.
+// Events are stored before they are acknowledged; a repeated event ID is
+// acknowledged without being processed again.
 // POST /webhook/livekit
 func (h *WebhookHandler) HandleLiveKitWebhook(c *fiber.Ctx) error {
-	// Get authorization header for validation
-	authHeader := c.Get("Authorization")
-	body := c.Body()
+	event, err := h.receiveLiveKitWebhook(c)
+	if err != nil {
+		reason := webhookRejectReason(err)
+		metrics.WebhookRejected.WithLabelValues(reason).Inc()
+		log.Warn().Err(err).Str("reason", reason).Str("ip", c.IP()).Msg("Rejected LiveKit webhook")
 
-	// Log webhook received
+		if reason == "malformed" {
+			return utils.BadRequestResponse(c, "Invalid event data")
+		}
+		return utils.ErrorResponseWithStatus(c, fiber.StatusUnauthorized, "Invalid webhook signature")
+	}
+
+	created, err := h.webhookEvents.Store(c.Context(), event, c.Body())
+	if err != nil {
+		// Not acknowledged, so LiveKit delivers the event again
+		log.Error().Err(err).Str("eventId", event.GetId()).Msg("Failed to store webhook event")
+		return utils.ErrorResponse(c, "Failed to store webhook event")
+	}
+	if !created {
+		metrics.WebhookDuplicates.WithLabelValues(event.GetEvent()).Inc()
+		log.Debug().Str("eventId", event.GetId()).Msg("Duplicate webhook event ignored")
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	metrics.WebhookEvents.WithLabelValues(event.GetEvent()).Inc()
 	log.Info().
-		Str("authorization", authHeader).
-		Int("bodyLength", len(body)).
-		Msg("LiveKit webhook received")
+		Str("event", event.GetEvent()).
+		Str("eventId", event.GetId()).
+		Msg("Queued webhook event")
+
+	return c.SendStatus(fiber.StatusOK)
+}
 
-	// Parse webhook event
-	var event struct {
-		Event       string                   `json:"event"`
-		Room        *livekit.Room            `json:"room"`
-		Participant *livekit.ParticipantInfo `json:"participant"`
-		Track       *livekit.TrackInfo       `json:"track"`
-		EgressInfo  *livekit.EgressInfo      `json:"egressInfo"`
+// ListWebhookEvents lists the stored webhook events of a room
+// GET /webhook/events/:room
+func (h *WebhookHandler) ListWebhookEvents(c *fiber.Ctx) error {
+	events, err := h.webhookEvents.ListEvents(c.Context(), c.Params("room"))
+	if err != nil {
+		return webhookEventErrorResponse(c, err)
 	}
 
-	if err := c.BodyParser(&event); err != nil {
-		log.Error().Err(err).Msg("Failed to parse webhook event")
-		return utils.BadRequestResponse(c, "Invalid event data")
+	return utils.SuccessResponse(c, events)
+}
+
+// ReplayWebhookEvents retries the failed and pending webhook events of a room,
+// in order. With all=true every stored event of the room is processed again.
+// POST /webhook/replay/:room
+func (h *WebhookHandler) ReplayWebhookEvents(c *fiber.Ctx) error {
+	all := c.Query("all") == "true"
+	count, err := h.webhookEvents.Replay(c.Context(), c.Params("room"), all)
+	if err != nil {
+		return webhookEventErrorResponse(c, err)
 	}
 
-	log.Info().
-		Str("event", event.Event).
-		Msg("Processing webhook event")
+	return utils.SuccessResponse(c, fiber.Map{
+		"room":     c.Params("room"),
+		"all":      all,
+		"replayed": count,
+	})
+}
 
-	// Process the event asynchronously
-	go h.processEvent(event.Event, event.Room, event.Participant, event.Track, event.EgressInfo)
+// webhookEventErrorResponse maps webhook event errors to responses
+func webhookEventErrorResponse(c *fiber.Ctx, err error) error {
+	if err == service.ErrWebhookRoomRequired {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	return utils.ErrorResponse(c, err.Error())
+}
 
-	return c.SendStatus(fiber.StatusOK)
+// applyEvent processes a stored webhook event; it is called by the webhook event workers,
+// which retry the event or mark it failed on the returned error
+func (h *WebhookHandler) applyEvent(ctx context.Context, event *livekit.WebhookEvent) error {
+	return h.processEvent(ctx, event.GetEvent(), event.GetRoom(), event.GetParticipant(), event.GetTrack(), event.GetEgressInfo())
+}
+
+// errMalformedWebhook wraps webhook bodies that are signed correctly but do not decode
+var errMalformedWebhook = errors.New("malformed webhook event")
+
+// receiveLiveKitWebhook verifies a webhook call with LiveKit's receiver and
+// decodes it with protobuf JSON semantics, so enums and oneofs decode correctly
+func (h *WebhookHandler) receiveLiveKitWebhook(c *fiber.Ctx) (*livekit.WebhookEvent, error) {
+	if h.cfg.LiveKitAPIKey == "" || h.cfg.LiveKitAPISecret == "" {
+		return nil, webhook.ErrSecretNotFound
+	}
+
+	req := &http.Request{
+		Header: http.Header{},
+		Body:   io.NopCloser(bytes.NewReader(c.Body())),
+	}
+	req.Header.Set("Authorization", c.Get(fiber.HeaderAuthorization))
+
+	data, err := webhook.Receive(req, auth.NewSimpleKeyProvider(h.cfg.LiveKitAPIKey, h.cfg.LiveKitAPISecret))
+	if err != nil {
+		return nil, err
+	}
+
+	var event livekit.WebhookEvent
+	unmarshalOpts := protojson.UnmarshalOptions{DiscardUnknown: true, AllowPartial: true}
+	if err := unmarshalOpts.Unmarshal(data, &event); err != nil {
+		return nil, errors.Join(errMalformedWebhook, err)
+	}
+
+	return &event, nil
 }
 
-// processEvent processes webhook events
-func (h *WebhookHandler) processEvent(eventType string, room *livekit.Room, participant *livekit.ParticipantInfo, track *livekit.TrackInfo, egressInfo *livekit.EgressInfo) {
-	ctx := context.Background()
+// webhookRejectReason returns the metrics label for a rejected webhook
+func webhookRejectReason(err error) string {
+	switch {
+	case errors.Is(err, errMalformedWebhook):
+		return "malformed"
+	case errors.Is(err, webhook.ErrNoAuthHeader):
+		return "unsigned"
+	case errors.Is(err, webhook.ErrSecretNotFound):
+		return "unknown_key"
+	case errors.Is(err, webhook.ErrInvalidChecksum):
+		return "checksum_mismatch"
+	}
+	return "invalid_signature"
+}
+
+// processEvent processes webhook events. It returns the first state update that
+// failed; side effects such as socket broadcasts and auto-recording only log.
+func (h *WebhookHandler) processEvent(ctx context.Context, eventType string, room *livekit.Room, participant *livekit.ParticipantInfo, track *livekit.TrackInfo, egressInfo *livekit.EgressInfo) error {
 
 	// Handle participant activity for inactivity timers
 	if participant != nil {
@@ -131,20 +232,27 @@ func (h *WebhookHandler) processEvent(eventType string, room *livekit.Room, part
 		if room != nil {
 			log.Info().Str("room", room.Name).Msg("Room started")
 			// Update room start time
-			_ = h.roomService.UpdateStartStopRecord(ctx, room.Name, true)
+			if err := h.roomService.UpdateStartStopRecord(ctx, room.Name, true); err != nil {
+				return fmt.Errorf("failed to update room start time: %w", err)
+			}
 		}
 
 	case "room_finished":
 		if room != nil {
 			log.Info().Str("room", room.Name).Msg("Room finished")
 			// Update room finish time
-			_ = h.roomService.UpdateStartStopRecord(ctx, room.Name, false)
-			// Stop any active recording
+			if err := h.roomService.UpdateStartStopRecord(ctx, room.Name, false); err != nil {
+				return fmt.Errorf("failed to update room finish time: %w", err)
+			}
+			// Stop any active recording and drop recordings still waiting for a slot
 			if _, err := h.recordService.StopRecord(ctx, room.Name); err != nil {
 				log.Debug().Err(err).Msg("No active recording to stop")
 			}
+			h.recordQueue.CancelRoom(ctx, room.Name, "")
 			// Update room status to closed
-			_ = h.roomService.UpdateRoomStatus(ctx, room.Name, "close")
+			if err := h.roomService.UpdateRoomStatus(ctx, room.Name, "close"); err != nil {
+				return fmt.Errorf("failed to close room: %w", err)
+			}
 		}
 
 	case "participant_joined":
@@ -155,7 +263,9 @@ func (h *WebhookHandler) processEvent(eventType string, room *livekit.Room, part
 				Str("name", participant.Name).
 				Msg("Participant joined")
 			// Update user status
-			_ = h.userService.UpdateUserStatus(ctx, room.Name, participant.Identity, "connect")
+			if err := h.userService.UpdateUserStatus(ctx, room.Name, participant.Identity, "connect"); err != nil {
+				return fmt.Errorf("failed to update user status: %w", err)
+			}
 
 			// Check for auto-recording
 			h.checkAutoRecord(ctx, room.Name, participant)
@@ -168,12 +278,15 @@ func (h *WebhookHandler) processEvent(eventType string, room *livekit.Room, part
 				Str("identity", participant.Identity).
 				Msg("Participant left")
 			// Update user status
-			_ = h.userService.UpdateUserStatus(ctx, room.Name, participant.Identity, "disconnect")
+			if err := h.userService.UpdateUserStatus(ctx, room.Name, participant.Identity, "disconnect"); err != nil {
+				return fmt.Errorf("failed to update user status: %w", err)
+			}
 
-			// Clean up track data
-			h.trackDataMu.Lock()
-			delete(h.trackPublishedData, participant.Identity)
-			h.trackDataMu.Unlock()
+			// Clean up auto-record state and recordings still waiting for a slot
+			if err := h.autoRecord.Forget(ctx, room.Name, participant.Identity); err != nil {
+				log.Warn().Err(err).Str("room", room.Name).Str("identity", participant.Identity).Msg("Failed to clear auto-record state")
+			}
+			h.recordQueue.CancelRoom(ctx, room.Name, participant.Identity)
 		}
 
 	case "track_published":
@@ -194,10 +307,14 @@ func (h *WebhookHandler) processEvent(eventType string, room *livekit.Room, part
 				Str("roomName", egressInfo.RoomName).
 				Msg("Egress started")
 
-			roomName := getRoomNameFromEgress(egressInfo)
+			roomName := service.EgressRoomName(egressInfo)
 			if roomName != "" {
-				_ = h.roomService.UpdateRecordID(ctx, roomName, egressInfo.EgressId)
-				_ = h.roomService.UpdateRecordStatus(ctx, roomName, 1)
+				if err := h.roomService.UpdateRecordID(ctx, roomName, egressInfo.EgressId); err != nil {
+					return fmt.Errorf("failed to update room record ID: %w", err)
+				}
+				if err := h.roomService.UpdateRecordStatus(ctx, roomName, 1); err != nil {
+					return fmt.Errorf("failed to update room record status: %w", err)
+				}
 
 				// Broadcast to socket
 				if h.socketHub != nil {
@@ -225,43 +342,32 @@ func (h *WebhookHandler) processEvent(eventType string, room *livekit.Room, part
 				Str("status", egressInfo.Status.String()).
 				Msg("Egress ended")
 
-			roomName := getRoomNameFromEgress(egressInfo)
-			if roomName != "" {
-				_ = h.roomService.UpdateRecordID(ctx, roomName, "")
-				_ = h.roomService.UpdateRecordStatus(ctx, roomName, 2)
-
-				// Update record info
-				if egressInfo.Status == livekit.EgressStatus_EGRESS_COMPLETE {
-					var fileName, filePath string
-					var fileSize, duration int
-
-					if len(egressInfo.GetFileResults()) > 0 {
-						result := egressInfo.GetFileResults()[0]
-						fileName = result.Filename
-						filePath = result.Filename
-						fileSize = int(result.Size)
-						duration = int(result.Duration / 1000000000) // nanoseconds to seconds
-					}
-
-					_ = h.recordService.UpdateRecordByEgressID(ctx, egressInfo.EgressId, fileName, filePath, "complete", fileSize, duration)
-				} else if egressInfo.Status == livekit.EgressStatus_EGRESS_FAILED {
-					_ = h.recordService.UpdateRecordByEgressID(ctx, egressInfo.EgressId, "", "", "failed", 0, 0)
-				}
+			// Update record info and free the egress slot for queued recordings
+			endErr := h.recordService.EndRecord(ctx, egressInfo)
+			h.recordQueue.Wake()
+			if egressInfo.Status == livekit.EgressStatus_EGRESS_COMPLETE {
+				h.encode.Wake()
+			}
 
-				// Broadcast to socket
-				if h.socketHub != nil {
-					h.socketHub.BroadcastToRoom("/"+roomName, roomName, "room-record", map[string]interface{}{
-						"egressId":      egressInfo.EgressId,
-						"status":        "stopRecord",
-						"dtmStopRecord": time.Now().Format("2006-01-02 15:04:05"),
-					})
-				}
+			// Broadcast to socket
+			roomName := service.EgressRoomName(egressInfo)
+			if roomName != "" && h.socketHub != nil {
+				h.socketHub.BroadcastToRoom("/"+roomName, roomName, "room-record", map[string]interface{}{
+					"egressId":      egressInfo.EgressId,
+					"status":        "stopRecord",
+					"dtmStopRecord": time.Now().Format("2006-01-02 15:04:05"),
+				})
+			}
+			if endErr != nil {
+				return fmt.Errorf("failed to update ended recording: %w", endErr)
 			}
 		}
 
 	default:
 		log.Warn().Str("event", eventType).Msg("Unknown webhook event")
 	}
+
+	return nil
 }
 
 // checkAutoRecord checks if auto-recording should start
@@ -281,84 +387,128 @@ func (h *WebhookHandler) checkAutoRecord(ctx context.Context, roomName string, p
 		return
 	}
 
-	// Check if already recording
-	if roomDetail.RecordStatus.Valid && roomDetail.RecordStatus.Int32 == 1 {
-		return
-	}
-
 	recordType := ""
 	if roomDetail.RecordType.Valid {
 		recordType = roomDetail.RecordType.String
 	}
 
-	// Initialize track data for TrackComposite recording
-	if recordType == "TrackComposite" && !isEgressParticipant(participant.Identity) {
-		h.trackDataMu.Lock()
-		h.trackPublishedData[participant.Identity] = &TrackData{
-			Room:     roomName,
-			Identity: participant.Identity,
-			Record:   0,
+	// Wait for the tracks of participants to record. Each participant gets
+	// its own egress once its tracks are published, see handleTrackPublished.
+	if recordType == service.RecordTypeTrackComposite || recordType == service.RecordTypeTrack {
+		if !isEgressParticipant(participant.Identity) {
+			if err := h.autoRecord.Watch(ctx, roomName, participant.Identity, recordType); err != nil {
+				log.Error().Err(err).Str("room", roomName).Str("identity", participant.Identity).Msg("Failed to save auto-record state")
+			}
 		}
-		h.trackDataMu.Unlock()
+		return
+	}
+
+	// Check if already recording
+	if roomDetail.RecordStatus.Valid && roomDetail.RecordStatus.Int32 == 1 {
+		return
 	}
 
 	// Start room composite recording
-	if recordType == "RoomCompositeVideoAudio" || recordType == "RoomCompositeAudio" {
+	if recordType == service.RecordTypeRoomCompositeVideoAudio || recordType == service.RecordTypeRoomCompositeAudio {
 		log.Info().Str("room", roomName).Str("recordType", recordType).Msg("Starting auto-record")
 
+		// File path, encoding, layout and file type come from the room settings
 		opts := service.StartRecordOptions{
 			Room:       roomName,
 			RecordType: recordType,
-			FilePath:   h.cfg.RecordPath,
 		}
 
-		result, err := h.recordService.StartRecord(ctx, opts)
+		result, err := h.recordQueue.Request(ctx, service.RecordRequestOptions{StartRecordOptions: opts})
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to start auto-record")
 			return
 		}
 
 		// Broadcast to socket
-		if h.socketHub != nil && result != nil {
+		if h.socketHub != nil && result.Egress != nil {
 			h.socketHub.BroadcastToRoom("/"+roomName, roomName, "room-record", map[string]interface{}{
-				"egressId": result.EgressId,
+				"egressId": result.Egress.EgressId,
 				"status":   "startRecord",
 			})
 		}
 	}
 }
 
-// handleTrackPublished handles track published events for TrackComposite recording
+// handleTrackPublished handles track published events for participant recording.
+// Track recordings start an egress per camera and microphone track; TrackComposite
+// recordings start one egress per participant once both tracks are published.
 func (h *WebhookHandler) handleTrackPublished(ctx context.Context, roomName string, participant *livekit.ParticipantInfo, track *livekit.TrackInfo) {
-	h.trackDataMu.Lock()
-	trackData, exists := h.trackPublishedData[participant.Identity]
-	if !exists {
-		h.trackDataMu.Unlock()
+	if track.Source != livekit.TrackSource_CAMERA && track.Source != livekit.TrackSource_MICROPHONE {
 		return
 	}
 
-	// Update track data based on source
-	switch track.Source {
-	case livekit.TrackSource_CAMERA:
-		trackData.Video = track
-	case livekit.TrackSource_MICROPHONE:
-		trackData.Audio = track
+	state, err := h.autoRecord.PublishTrack(ctx, roomName, participant.Identity, track.Source, track.Sid)
+	if err != nil {
+		log.Error().Err(err).Str("room", roomName).Str("identity", participant.Identity).Msg("Failed to update auto-record state")
+		return
+	}
+	if state == nil {
+		return
 	}
 
-	// Check if we can start TrackComposite recording
-	if trackData.Record == 0 && trackData.Video != nil && trackData.Audio != nil {
-		trackData.Record = 1
-		h.trackDataMu.Unlock()
+	if state.RecordType == service.RecordTypeTrack {
+		log.Info().Str("room", roomName).Str("identity", participant.Identity).Str("trackSid", track.Sid).Msg("Starting Track auto-record")
+		h.startParticipantRecord(ctx, service.StartRecordOptions{
+			Room:       roomName,
+			RecordType: service.RecordTypeTrack,
+			Identity:   participant.Identity,
+			TrackID:    track.Sid,
+		})
+		return
+	}
 
-		log.Info().Str("room", roomName).Str("identity", participant.Identity).Msg("Starting TrackComposite auto-record")
+	// Start the TrackComposite recording once both tracks are published
+	if state.VideoTrackID == "" || state.AudioTrackID == "" {
+		return
+	}
+	claimed, err := h.autoRecord.ClaimStart(ctx, roomName, participant.Identity)
+	if err != nil || !claimed {
+		return
+	}
 
-		// Start track composite record would be implemented here
-		// For now, this is a placeholder
-	} else {
-		h.trackDataMu.Unlock()
+	log.Info().Str("room", roomName).Str("identity", participant.Identity).Msg("Starting TrackComposite auto-record")
+
+	if !h.startParticipantRecord(ctx, service.StartRecordOptions{
+		Room:         roomName,
+		RecordType:   service.RecordTypeTrackComposite,
+		Identity:     participant.Identity,
+		AudioTrackID: state.AudioTrackID,
+		VideoTrackID: state.VideoTrackID,
+	}) {
+		// Let a later track_published retry
+		if err := h.autoRecord.ReleaseStart(ctx, roomName, participant.Identity); err != nil {
+			log.Warn().Err(err).Str("room", roomName).Str("identity", participant.Identity).Msg("Failed to reset auto-record state")
+		}
 	}
 }
 
+// startParticipantRecord starts or queues a recording of one participant and announces it to the room
+func (h *WebhookHandler) startParticipantRecord(ctx context.Context, opts service.StartRecordOptions) bool {
+	result, err := h.recordQueue.Request(ctx, service.RecordRequestOptions{StartRecordOptions: opts})
+	if err != nil {
+		log.Error().Err(err).
+			Str("room", opts.Room).
+			Str("identity", opts.Identity).
+			Str("recordType", opts.RecordType).
+			Msg("Failed to start participant auto-record")
+		return false
+	}
+
+	if h.socketHub != nil && result.Egress != nil {
+		h.socketHub.BroadcastToRoom("/"+opts.Room, opts.Room, "room-record", map[string]interface{}{
+			"egressId": result.Egress.EgressId,
+			"identity": opts.Identity,
+			"status":   "startRecord",
+		})
+	}
+	return true
+}
+
 // Timer management functions
 func (h *WebhookHandler) startInactivityTimer(participantSid string) {
 	h.timersMu.Lock()
@@ -402,11 +552,6 @@ func (h *WebhookHandler) Cleanup() {
 	h.participantTimers = make(map[string]*time.Timer)
 	h.timersMu.Unlock()
 
-	// Clear track data
-	h.trackDataMu.Lock()
-	h.trackPublishedData = make(map[string]*TrackData)
-	h.trackDataMu.Unlock()
-
 	logger.Info("WebhookHandler cleaned up")
 }
 
@@ -416,15 +561,8 @@ func (h *WebhookHandler) GetStatus() map[string]interface{} {
 	timerCount := len(h.participantTimers)
 	h.timersMu.RUnlock()
 
-	h.trackDataMu.RLock()
-	trackDataCount := len(h.trackPublishedData)
-	h.trackDataMu.RUnlock()
-
 	return map[string]interface{}{
-		"activeTimers":     timerCount,
-		"trackDataEntries": trackDataCount,
-		"maxTrackDataSize": h.maxTrackDataSize,
-		"healthy":          trackDataCount < h.maxTrackDataSize,
+		"activeTimers": timerCount,
 	}
 }
 
@@ -442,16 +580,155 @@ func (h *WebhookHandler) HandleGenericWebhook(c *fiber.Ctx) error {
 	})
 }
 
-// Helper functions
-func getRoomNameFromEgress(info *livekit.EgressInfo) string {
-	if info.RoomName != "" {
-		return info.RoomName
+// HandleSMSDeliveryReport handles carrier SMS delivery receipts. Calls must
+// carry an X-DLR-Signature HMAC of the body, or the SMS_DLR_SECRET in the
+// X-DLR-Token header or the token query parameter. The delivery status goes
+// to the link's room and to /notification as sms-status.
+// POST /webhook/sms/dlr
+func (h *WebhookHandler) HandleSMSDeliveryReport(c *fiber.Ctx) error {
+	token := firstNonEmpty(c.Get("X-DLR-Token"), c.Query("token"))
+	if err := h.smsOutbox.VerifyDeliveryReport(c.Get("X-DLR-Signature"), token, c.Body()); err != nil {
+		log.Warn().Err(err).Str("ip", c.IP()).Msg("Rejected SMS delivery report")
+		return utils.UnauthorizedResponse(c, "Invalid delivery report signature")
+	}
+
+	type DeliveryReportRequest struct {
+		MessageID string `json:"messageId" form:"messageId" query:"messageId"`
+		MsgID     string `json:"msgid" form:"msgid" query:"msgid"`
+		Status    string `json:"status" form:"status" query:"status"`
+		Stat      string `json:"stat" form:"stat" query:"stat"`
+		ErrorCode string `json:"errorCode" form:"errorCode" query:"errorCode"`
+		Err       string `json:"err" form:"err" query:"err"`
+	}
+
+	var req DeliveryReportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.BadRequestResponse(c, "Invalid request body")
+		}
+	} else if err := c.QueryParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid query parameters")
+	}
+
+	report := service.DeliveryReport{
+		ProviderMessageID: firstNonEmpty(req.MessageID, req.MsgID),
+		Status:            firstNonEmpty(req.Status, req.Stat),
+		ErrorCode:         firstNonEmpty(req.ErrorCode, req.Err),
+	}
+
+	msg, err := h.smsOutbox.HandleDeliveryReport(c.Context(), report)
+	if err != nil {
+		if err == service.ErrInvalidDeliveryReport {
+			return utils.BadRequestResponse(c, "Message ID and a known status required")
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	// Acknowledge unknown message IDs so the carrier does not keep retrying
+	if msg == nil {
+		log.Warn().Str("messageId", report.ProviderMessageID).Msg("SMS delivery report did not match any message")
+		return utils.SuccessResponse(c, fiber.Map{
+			"matched": false,
+		})
+	}
+
+	log.Info().
+		Uint("id", msg.ID).
+		Str("messageId", report.ProviderMessageID).
+		Str("deliveryStatus", msg.DeliveryStatus.String).
+		Msg("SMS delivery report received")
+
+	// The mobile number and message are never sent to sockets
+	if h.socketHub != nil {
+		if msg.Room.String != "" {
+			h.socketHub.BroadcastToRoom("/"+msg.Room.String, msg.Room.String, "sms-status", map[string]interface{}{
+				"id":             msg.ID,
+				"linkID":         msg.LinkID.String,
+				"messageType":    msg.MessageType.String,
+				"deliveryStatus": msg.DeliveryStatus.String,
+				"deliveryError":  msg.DeliveryError.String,
+			})
+		}
+		h.socketHub.BroadcastToRoom("/notification", "notification", "sms-status", map[string]interface{}{
+			"service":        msg.Service.Int32,
+			"room":           msg.Room.String,
+			"linkID":         msg.LinkID.String,
+			"deliveryStatus": msg.DeliveryStatus.String,
+		})
 	}
-	if rc := info.GetRoomComposite(); rc != nil {
-		return rc.RoomName
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"matched":        true,
+		"id":             msg.ID,
+		"deliveryStatus": msg.DeliveryStatus.String,
+	})
+}
+
+// HandleEncodeCallback handles the result the encode service reports for a
+// job. Calls must carry the callback token sent with the job, in the
+// X-Encode-Token header or the token query parameter.
+// POST /webhook/encode/:id
+func (h *WebhookHandler) HandleEncodeCallback(c *fiber.Ctx) error {
+	type EncodeCallbackRequest struct {
+		Status    string `json:"status"`
+		HLS       string `json:"hls"`
+		Thumbnail string `json:"thumbnail"`
+		Error     string `json:"error"`
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return utils.BadRequestResponse(c, "Invalid encode job ID")
 	}
-	if tc := info.GetTrackComposite(); tc != nil {
-		return tc.RoomName
+
+	var req EncodeCallbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.BadRequestResponse(c, "Invalid request body")
+	}
+
+	token := firstNonEmpty(c.Get("X-Encode-Token"), c.Query("token"))
+	job, err := h.encode.HandleCallback(c.Context(), uint(id), token, service.EncodeCallback{
+		Status:    req.Status,
+		HLS:       req.HLS,
+		Thumbnail: req.Thumbnail,
+		Error:     req.Error,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrEncodeJobNotFound:
+			return utils.NotFoundResponse(c, "Encode job not found")
+		case service.ErrEncodeCallbackToken:
+			log.Warn().Uint64("id", id).Str("ip", c.IP()).Msg("Rejected encode callback")
+			return utils.UnauthorizedResponse(c, "Invalid callback token")
+		case service.ErrInvalidEncodeCallback:
+			return utils.BadRequestResponse(c, "Status done with hls, or failed, required")
+		case service.ErrEncodeJobNotEncoding:
+			return utils.ErrorResponseWithStatus(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.ErrorResponse(c, err.Error())
+	}
+
+	if job.Status == service.EncodeStatusDone && h.socketHub != nil && job.Room.String != "" {
+		h.socketHub.BroadcastToRoom("/"+job.Room.String, job.Room.String, "room-record", map[string]interface{}{
+			"recordId":  job.RecordID,
+			"status":    "encoded",
+			"hls":       job.HLS.String,
+			"thumbnail": job.Thumbnail.String,
+		})
+	}
+
+	return utils.SuccessResponse(c, fiber.Map{
+		"id":     job.ID,
+		"status": job.Status,
+	})
+}
+
+// Helper functions
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
 	}
 	return ""
 }
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
+	"api-gateway-go/internal/repository"
 	"api-gateway-go/internal/service"
 	"api-gateway-go/pkg/utils"
 
@@ -12,41 +14,9 @@ import (
 // AuthMiddleware creates an authentication middleware
 func AuthMiddleware(authService *service.AuthService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get token from Authorization header
-		authHeader := c.Get("Authorization")
-		token := ""
-
-		if authHeader != "" {
-			// Check for Bearer token
-			if strings.HasPrefix(authHeader, "Bearer ") {
-				token = strings.TrimPrefix(authHeader, "Bearer ")
-			} else {
-				token = authHeader
-			}
-		}
-
-		// Try query parameter
-		if token == "" {
-			token = c.Query("token")
-		}
-
-		if token == "" {
-			return utils.UnauthorizedResponse(c, "Invalid token")
-		}
-
-		// Verify token
-		claims, err := authService.VerifyToken(c.Context(), token)
-		if err != nil {
+		if _, err := authenticate(c, authService); err != nil {
 			return utils.ErrorResponseWithStatus(c, fiber.StatusUnauthorized, err.Error())
 		}
-
-		// Store claims in context
-		c.Locals("user", claims)
-		c.Locals("room", claims.Room)
-		c.Locals("identity", claims.Identity)
-		c.Locals("userName", claims.UserName)
-		c.Locals("userType", claims.UserType)
-
 		return c.Next()
 	}
 }
@@ -55,36 +25,130 @@ func AuthMiddleware(authService *service.AuthService) fiber.Handler {
 // It doesn't fail if no token is provided, but parses it if present
 func OptionalAuthMiddleware(authService *service.AuthService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
-		token := ""
-
-		if authHeader != "" {
-			if strings.HasPrefix(authHeader, "Bearer ") {
-				token = strings.TrimPrefix(authHeader, "Bearer ")
-			} else {
-				token = authHeader
-			}
+		if tokenFromRequest(c) != "" {
+			authenticate(c, authService)
 		}
+		return c.Next()
+	}
+}
 
-		if token == "" {
-			token = c.Query("token")
+// tokenFromRequest reads the token from the Authorization header or the token query parameter
+func tokenFromRequest(c *fiber.Ctx) string {
+	// Get token from Authorization header
+	authHeader := c.Get("Authorization")
+	token := ""
+
+	if authHeader != "" {
+		// Check for Bearer token
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			token = strings.TrimPrefix(authHeader, "Bearer ")
+		} else {
+			token = authHeader
 		}
+	}
+
+	// Try query parameter
+	if token == "" {
+		token = c.Query("token")
+	}
 
-		if token != "" {
-			claims, err := authService.VerifyToken(c.Context(), token)
-			if err == nil {
-				c.Locals("user", claims)
-				c.Locals("room", claims.Room)
-				c.Locals("identity", claims.Identity)
-				c.Locals("userName", claims.UserName)
-				c.Locals("userType", claims.UserType)
+	return token
+}
+
+// APIKeyMiddleware creates a middleware that authenticates requests carrying an
+// X-API-Key header, or an API key as bearer token for clients such as Prometheus
+// that cannot set custom headers. Route permissions are then checked against the
+// key's scopes.
+func APIKeyMiddleware(apiKeyService *service.APIKeyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("X-API-Key")
+		if key == "" {
+			if bearer := strings.TrimPrefix(c.Get("Authorization"), "Bearer "); service.IsAPIKey(bearer) {
+				key = bearer
 			}
 		}
+		if key == "" {
+			return c.Next()
+		}
 
+		claims, err := apiKeyService.Authenticate(c.Context(), key, c.IP())
+		if err != nil {
+			status := fiber.StatusUnauthorized
+			if errors.Is(err, service.ErrAPIKeyRateLimited) {
+				status = fiber.StatusTooManyRequests
+			}
+			if code := service.APIKeyErrorCode(err); code != "" {
+				return utils.ErrorResponseWithCode(c, status, code, err.Error())
+			}
+			// A key that cannot be looked up is not authenticated either
+			return utils.UnauthorizedResponse(c, "API key could not be verified")
+		}
+
+		setClaims(c, claims)
 		return c.Next()
 	}
 }
 
+// authenticate verifies the request token and stores its claims in context.
+// Requests already authenticated by APIKeyMiddleware keep their API key claims.
+func authenticate(c *fiber.Ctx, authService *service.AuthService) (*service.Claims, error) {
+	if claims := GetUserFromContext(c); claims != nil && claims.APIKeyID != 0 {
+		return claims, nil
+	}
+
+	token := tokenFromRequest(c)
+	if token == "" {
+		return nil, service.ErrInvalidToken
+	}
+
+	// Verify token
+	claims, err := authService.VerifyToken(c.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject tokens revoked by logout or an admin
+	if err := authService.CheckRevoked(c.Context(), claims); err != nil {
+		return nil, err
+	}
+
+	setClaims(c, claims)
+
+	return claims, nil
+}
+
+// setClaims stores claims in context and scopes repository queries to their tenant
+func setClaims(c *fiber.Ctx, claims *service.Claims) {
+	c.Locals("user", claims)
+	c.Locals("room", claims.Room)
+	c.Locals("identity", claims.Identity)
+	c.Locals("userName", claims.UserName)
+	c.Locals("userType", claims.UserType)
+	c.Locals("role", service.RoleFromClaims(claims))
+	c.Locals("staffId", claims.StaffID)
+	c.Locals("apiKeyId", claims.APIKeyID)
+	c.Locals("service", claims.Service)
+	c.Locals("organization", claims.Organization)
+
+	// Scope every caller to its own tenant except admins and global API keys,
+	// which an admin created without a service. Callers bound to no service,
+	// such as room tokens, get an empty tenant and see no scoped rows at all.
+	if !unscopedClaims(claims) {
+		c.Locals(repository.TenantKey, repository.Tenant{
+			Service:      claims.Service,
+			Organization: claims.Organization,
+		})
+	}
+}
+
+// unscopedClaims reports whether claims may query every tenant
+func unscopedClaims(claims *service.Claims) bool {
+	if claims.APIKeyID != 0 {
+		return claims.Service == 0
+	}
+	return service.RoleFromClaims(claims) == service.RoleAdmin
+}
+
 // GetUserFromContext gets user claims from context
 func GetUserFromContext(c *fiber.Ctx) *service.Claims {
 	user := c.Locals("user")
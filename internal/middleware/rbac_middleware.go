@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"api-gateway-go/internal/service"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission creates a middleware that authenticates the request and
+// requires its role to hold every listed permission
+func RequirePermission(authService *service.AuthService, perms ...service.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return requirePermission(c, authService, perms...)
+	}
+}
+
+func requirePermission(c *fiber.Ctx, authService *service.AuthService, perms ...service.Permission) error {
+	claims, err := authenticate(c, authService)
+	if err != nil {
+		return utils.ErrorResponseWithStatus(c, fiber.StatusUnauthorized, err.Error())
+	}
+
+	for _, perm := range perms {
+		if !service.ClaimsHavePermission(claims, perm) {
+			return utils.ErrorResponseWithStatus(c, fiber.StatusForbidden, "Permission denied")
+		}
+	}
+
+	return c.Next()
+}
+
+// RequireRole creates a middleware that authenticates the request and
+// requires one of the listed roles
+func RequireRole(authService *service.AuthService, roles ...service.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := authenticate(c, authService)
+		if err != nil {
+			return utils.ErrorResponseWithStatus(c, fiber.StatusUnauthorized, err.Error())
+		}
+
+		role := service.RoleFromClaims(claims)
+		for _, allowed := range roles {
+			if role == allowed {
+				return c.Next()
+			}
+		}
+
+		return utils.ErrorResponseWithStatus(c, fiber.StatusForbidden, "Permission denied")
+	}
+}
+
+// Authorize creates a middleware that looks up the permission of the matched
+// route in a route-permission matrix keyed by "METHOD /path". Routes missing
+// from the matrix are denied.
+func Authorize(authService *service.AuthService, matrix map[string]service.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		route := c.Route()
+		perm, ok := matrix[route.Method+" "+route.Path]
+		if !ok {
+			return utils.ErrorResponseWithStatus(c, fiber.StatusForbidden, "Permission denied")
+		}
+		if perm == service.PermPublic {
+			return c.Next()
+		}
+
+		return requirePermission(c, authService, perm)
+	}
+}
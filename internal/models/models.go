@@ -5,6 +5,27 @@ import (
 	"time"
 )
 
+// APIKey represents the api_key table
+type APIKey struct {
+	ID                 uint           `db:"id" json:"id"`
+	Name               string         `db:"name" json:"name"`
+	KeyPrefix          string         `db:"keyPrefix" json:"keyPrefix"`
+	KeyHash            string         `db:"keyHash" json:"-"`
+	PreviousKeyHash    sql.NullString `db:"previousKeyHash" json:"-"`
+	DtmPreviousExpired sql.NullTime   `db:"dtmPreviousExpired" json:"dtmPreviousExpired,omitempty"`
+	Scopes             string         `db:"scopes" json:"scopes"`
+	RateLimit          int            `db:"rateLimit" json:"rateLimit"`
+	Service            int            `db:"service" json:"service"`
+	Organization       sql.NullString `db:"organization" json:"organization,omitempty"`
+	Enabled            sql.NullInt32  `db:"enabled" json:"enabled,omitempty"`
+	LastUsedIP         sql.NullString `db:"lastUsedIp" json:"lastUsedIp,omitempty"`
+	DtmLastUsed        sql.NullTime   `db:"dtmLastUsed" json:"dtmLastUsed,omitempty"`
+	DtmRotated         sql.NullTime   `db:"dtmRotated" json:"dtmRotated,omitempty"`
+	CreatedBy          sql.NullString `db:"createdBy" json:"createdBy,omitempty"`
+	DtmCreated         sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmUpdated         sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
+}
+
 // CarTrack represents the car_track table
 type CarTrack struct {
 	ID               int              `db:"id" json:"id"`
@@ -43,6 +64,10 @@ type CaseData struct {
 	UserName        sql.NullString `db:"userName" json:"userName,omitempty"`
 	DtmCreated      sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
 	Organization    sql.NullString `db:"organization" json:"organization,omitempty"`
+	LegalHold       int            `db:"legalHold" json:"legalHold"`
+	LegalHoldReason sql.NullString `db:"legalHoldReason" json:"legalHoldReason,omitempty"`
+	LegalHoldBy     sql.NullString `db:"legalHoldBy" json:"legalHoldBy,omitempty"`
+	DtmLegalHold    sql.NullTime   `db:"dtmLegalHold" json:"dtmLegalHold,omitempty"`
 }
 
 // ChatMessage represents the chat_message table
@@ -75,6 +100,27 @@ type DataLog struct {
 	DtmCreated sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
 }
 
+// EncodeJob represents the encode_job table
+type EncodeJob struct {
+	ID             uint           `db:"id" json:"id"`
+	RecordID       int            `db:"recordId" json:"recordId"`
+	Room           sql.NullString `db:"room" json:"room,omitempty"`
+	Source         string         `db:"source" json:"source"`
+	Status         string         `db:"status" json:"status"`
+	Attempts       int            `db:"attempts" json:"attempts"`
+	MaxAttempts    int            `db:"maxAttempts" json:"maxAttempts"`
+	CallbackToken  string         `db:"callbackToken" json:"-"`
+	EncoderJobID   sql.NullString `db:"encoderJobId" json:"encoderJobId,omitempty"`
+	HLS            sql.NullString `db:"hls" json:"hls,omitempty"`
+	Thumbnail      sql.NullString `db:"thumbnail" json:"thumbnail,omitempty"`
+	LastError      sql.NullString `db:"lastError" json:"lastError,omitempty"`
+	DtmNextAttempt sql.NullTime   `db:"dtmNextAttempt" json:"dtmNextAttempt,omitempty"`
+	DtmSubmitted   sql.NullTime   `db:"dtmSubmitted" json:"dtmSubmitted,omitempty"`
+	DtmCompleted   sql.NullTime   `db:"dtmCompleted" json:"dtmCompleted,omitempty"`
+	DtmCreated     sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmUpdated     sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
+}
+
 // Files represents the files table
 type Files struct {
 	ID        int            `db:"id" json:"id"`
@@ -97,6 +143,7 @@ type LinkConnect struct {
 	ID                    uint            `db:"id" json:"id"`
 	RoomUserID            sql.NullInt32   `db:"roomUserId" json:"roomUserId,omitempty"`
 	SMS                   sql.NullInt32   `db:"sms" json:"sms,omitempty"`
+	SMSDeliveryStatus     sql.NullString  `db:"smsDeliveryStatus" json:"smsDeliveryStatus,omitempty"`
 	RecordID              sql.NullInt32   `db:"recordId" json:"recordId,omitempty"`
 	Mobile                string          `db:"mobile" json:"mobile"`
 	LinkID                sql.NullString  `db:"linkID" json:"linkID,omitempty"`
@@ -123,12 +170,13 @@ type LinkConnect struct {
 	RequireUserName       sql.NullInt32   `db:"requireUserName" json:"requireUserName,omitempty"`
 	RequirePassword       sql.NullInt32   `db:"requirePassword" json:"requirePassword,omitempty"`
 	OneTimeLink           sql.NullInt32   `db:"oneTimeLink" json:"oneTimeLink,omitempty"`
-	Password              sql.NullString  `db:"password" json:"password,omitempty"`
+	Password              sql.NullString  `db:"password" json:"-"`
 	IsAdmin               sql.NullString  `db:"isAdmin" json:"isAdmin,omitempty"`
 	DtmConnection         sql.NullTime    `db:"dtmConnection" json:"dtmConnection,omitempty"`
 	DtmDisconnect         sql.NullTime    `db:"dtmDisconnect" json:"dtmDisconnect,omitempty"`
 	DtmCreated            sql.NullTime    `db:"dtmCreated" json:"dtmCreated,omitempty"`
 	DtmExpired            sql.NullTime    `db:"dtmExpired" json:"dtmExpired,omitempty"`
+	DtmRedeemed           sql.NullTime    `db:"dtmRedeemed" json:"dtmRedeemed,omitempty"`
 	SyncAt                sql.NullTime    `db:"sync_at" json:"syncAt,omitempty"`
 }
 
@@ -189,11 +237,26 @@ type RadioLocations struct {
 	DtmUpdated   sql.NullTime    `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
 }
 
+// RecordAudit represents the record_audit table
+type RecordAudit struct {
+	ID         uint           `db:"id" json:"id"`
+	Action     string         `db:"action" json:"action"`
+	RecordID   sql.NullInt32  `db:"recordId" json:"recordId,omitempty"`
+	CaseID     sql.NullInt32  `db:"caseId" json:"caseId,omitempty"`
+	Room       sql.NullString `db:"room" json:"room,omitempty"`
+	Service    sql.NullInt32  `db:"service" json:"service,omitempty"`
+	Files      sql.NullString `db:"files" json:"files,omitempty"`
+	Reason     sql.NullString `db:"reason" json:"reason,omitempty"`
+	Actor      sql.NullString `db:"actor" json:"actor,omitempty"`
+	DtmCreated sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+}
+
 // RecordMedia represents the record_media table
 type RecordMedia struct {
 	ID           int            `db:"id" json:"id"`
 	EgressID     sql.NullString `db:"egressId" json:"egressId,omitempty"`
 	Room         sql.NullString `db:"room" json:"room,omitempty"`
+	Identity     sql.NullString `db:"identity" json:"identity,omitempty"`
 	FileName     sql.NullString `db:"fileName" json:"fileName,omitempty"`
 	FilePath     sql.NullString `db:"filePath" json:"filePath,omitempty"`
 	FileSize     sql.NullInt32  `db:"fileSize" json:"fileSize,omitempty"`
@@ -202,7 +265,10 @@ type RecordMedia struct {
 	Status       sql.NullString `db:"status" json:"status,omitempty"`
 	DtmCreated   sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
 	DtmCompleted sql.NullTime   `db:"dtmCompleted" json:"dtmCompleted,omitempty"`
+	DtmArchived  sql.NullTime   `db:"dtmArchived" json:"dtmArchived,omitempty"`
 	HLS          sql.NullString `db:"hls" json:"hls,omitempty"`
+	Thumbnail    sql.NullString `db:"thumbnail" json:"thumbnail,omitempty"`
+	ArchivePath  sql.NullString `db:"archivePath" json:"archivePath,omitempty"`
 	Encode       sql.NullInt32  `db:"encode" json:"encode,omitempty"`
 	Uploader     sql.NullString `db:"uploader" json:"uploader,omitempty"`
 	StartRecord  sql.NullTime   `db:"startRecord" json:"startRecord,omitempty"`
@@ -210,6 +276,35 @@ type RecordMedia struct {
 	DtmUpdated   sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
 }
 
+// RecordQueue represents the record_queue table
+type RecordQueue struct {
+	ID         uint           `db:"id" json:"id"`
+	Room       string         `db:"room" json:"room"`
+	Identity   sql.NullString `db:"identity" json:"identity,omitempty"`
+	RecordType sql.NullString `db:"recordType" json:"recordType,omitempty"`
+	Options    string         `db:"options" json:"-"`
+	Priority   int            `db:"priority" json:"priority"`
+	Status     string         `db:"status" json:"status"`
+	EgressID   sql.NullString `db:"egressId" json:"egressId,omitempty"`
+	LastError  sql.NullString `db:"lastError" json:"lastError,omitempty"`
+	CreatedBy  sql.NullString `db:"createdBy" json:"createdBy,omitempty"`
+	DtmCreated sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmStarted sql.NullTime   `db:"dtmStarted" json:"dtmStarted,omitempty"`
+	DtmUpdated sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
+}
+
+// RetentionPolicy represents the retention_policy table
+type RetentionPolicy struct {
+	ID          uint           `db:"id" json:"id"`
+	Service     int            `db:"service" json:"service"`
+	RetainDays  int            `db:"retainDays" json:"retainDays"`
+	ArchiveDays int            `db:"archiveDays" json:"archiveDays"`
+	Enabled     int            `db:"enabled" json:"enabled"`
+	UpdatedBy   sql.NullString `db:"updatedBy" json:"updatedBy,omitempty"`
+	DtmCreated  sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmUpdated  sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
+}
+
 // RoomConference represents the room_conference table
 type RoomConference struct {
 	ID                    uint           `db:"id" json:"id"`
@@ -223,6 +318,8 @@ type RoomConference struct {
 	AutoRecord            sql.NullInt32  `db:"autoRecord" json:"autoRecord,omitempty"`
 	RecordType            sql.NullString `db:"recordType" json:"recordType,omitempty"`
 	EncodingOptionsPreset sql.NullString `db:"encodingOptionsPreset" json:"encodingOptionsPreset,omitempty"`
+	RecordLayout          sql.NullString `db:"recordLayout" json:"recordLayout,omitempty"`
+	RecordFileType        sql.NullString `db:"recordFileType" json:"recordFileType,omitempty"`
 	ChatEnabled           sql.NullInt32  `db:"chatEnabled" json:"chatEnabled,omitempty"`
 	MessageUnread         sql.NullInt32  `db:"messageUnread" json:"messageUnread,omitempty"`
 	AgentSeen             sql.NullTime   `db:"agentSeen" json:"agentSeen,omitempty"`
@@ -272,12 +369,96 @@ type Services struct {
 	DomainsVideo            sql.NullString  `db:"domainsVideo" json:"domainsVideo,omitempty"`
 	DomainsLocation         sql.NullString  `db:"domainsLocation" json:"domainsLocation,omitempty"`
 	SmsSenderName           sql.NullString  `db:"smsSenderName" json:"smsSenderName,omitempty"`
+	SmsProvider             sql.NullString  `db:"smsProvider" json:"smsProvider,omitempty"`
 	Logo                    sql.NullString  `db:"logo" json:"logo,omitempty"`
 	TitleColor              sql.NullString  `db:"titleColor" json:"titleColor,omitempty"`
 	Latitude                sql.NullFloat64 `db:"latitude" json:"latitude,omitempty"`
 	Longitude               sql.NullFloat64 `db:"longitude" json:"longitude,omitempty"`
 }
 
+// SmsBlocklist represents the sms_blocklist table
+type SmsBlocklist struct {
+	ID         uint           `db:"id" json:"id"`
+	Mobile     string         `db:"mobile" json:"mobile"`
+	Service    int            `db:"service" json:"service"`
+	Source     sql.NullString `db:"source" json:"source,omitempty"`
+	Reason     sql.NullString `db:"reason" json:"reason,omitempty"`
+	CreatedBy  sql.NullString `db:"createdBy" json:"createdBy,omitempty"`
+	DtmCreated sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+}
+
+// SmsOutbox represents the sms_outbox table
+type SmsOutbox struct {
+	ID                uint           `db:"id" json:"id"`
+	Service           sql.NullInt32  `db:"service" json:"service,omitempty"`
+	LinkID            sql.NullString `db:"linkID" json:"linkID,omitempty"`
+	Room              sql.NullString `db:"room" json:"room,omitempty"`
+	Mobile            string         `db:"mobile" json:"mobile"`
+	Sender            sql.NullString `db:"sender" json:"sender,omitempty"`
+	Provider          sql.NullString `db:"provider" json:"provider,omitempty"`
+	MessageType       sql.NullString `db:"messageType" json:"messageType,omitempty"`
+	Message           sql.NullString `db:"message" json:"message,omitempty"`
+	Status            string         `db:"status" json:"status"`
+	Attempts          int            `db:"attempts" json:"attempts"`
+	MaxAttempts       int            `db:"maxAttempts" json:"maxAttempts"`
+	ProviderMessageID sql.NullString `db:"providerMessageId" json:"providerMessageId,omitempty"`
+	LastError         sql.NullString `db:"lastError" json:"lastError,omitempty"`
+	DeliveryStatus    sql.NullString `db:"deliveryStatus" json:"deliveryStatus,omitempty"`
+	DeliveryError     sql.NullString `db:"deliveryError" json:"deliveryError,omitempty"`
+	CreatedBy         sql.NullString `db:"createdBy" json:"createdBy,omitempty"`
+	DtmNextAttempt    sql.NullTime   `db:"dtmNextAttempt" json:"dtmNextAttempt,omitempty"`
+	DtmSent           sql.NullTime   `db:"dtmSent" json:"dtmSent,omitempty"`
+	DtmDelivered      sql.NullTime   `db:"dtmDelivered" json:"dtmDelivered,omitempty"`
+	DtmCreated        sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmUpdated        sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
+}
+
+// SmsTemplate represents the sms_template table
+type SmsTemplate struct {
+	ID          uint           `db:"id" json:"id"`
+	Service     int            `db:"service" json:"service"`
+	MessageType string         `db:"messageType" json:"messageType"`
+	Locale      string         `db:"locale" json:"locale"`
+	Body        string         `db:"body" json:"body"`
+	Enabled     sql.NullInt32  `db:"enabled" json:"enabled,omitempty"`
+	UpdatedBy   sql.NullString `db:"updatedBy" json:"updatedBy,omitempty"`
+	DtmCreated  sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmUpdated  sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
+}
+
+// StaffAccount represents the staff_account table
+type StaffAccount struct {
+	ID                 uint           `db:"id" json:"id"`
+	UserName           string         `db:"userName" json:"userName"`
+	PasswordHash       string         `db:"passwordHash" json:"-"`
+	DisplayName        sql.NullString `db:"displayName" json:"displayName,omitempty"`
+	Role               string         `db:"role" json:"role"`
+	Service            int            `db:"service" json:"service"`
+	Organization       sql.NullString `db:"organization" json:"organization,omitempty"`
+	Enabled            sql.NullInt32  `db:"enabled" json:"enabled,omitempty"`
+	FailedAttempts     int            `db:"failedAttempts" json:"failedAttempts"`
+	DtmLocked          sql.NullTime   `db:"dtmLocked" json:"dtmLocked,omitempty"`
+	DtmLastLogin       sql.NullTime   `db:"dtmLastLogin" json:"dtmLastLogin,omitempty"`
+	DtmPasswordChanged sql.NullTime   `db:"dtmPasswordChanged" json:"dtmPasswordChanged,omitempty"`
+	CreatedBy          sql.NullString `db:"createdBy" json:"createdBy,omitempty"`
+	DtmCreated         sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmUpdated         sql.NullTime   `db:"dtmUpdated" json:"dtmUpdated,omitempty"`
+}
+
+// StaffRefreshToken represents the staff_refresh_token table
+type StaffRefreshToken struct {
+	ID         uint           `db:"id" json:"id"`
+	StaffID    uint           `db:"staffId" json:"staffId"`
+	TokenHash  string         `db:"tokenHash" json:"-"`
+	FamilyID   sql.NullString `db:"familyId" json:"familyId,omitempty"`
+	UserAgent  sql.NullString `db:"userAgent" json:"userAgent,omitempty"`
+	IP         sql.NullString `db:"ip" json:"ip,omitempty"`
+	DtmCreated sql.NullTime   `db:"dtmCreated" json:"dtmCreated,omitempty"`
+	DtmExpired sql.NullTime   `db:"dtmExpired" json:"dtmExpired,omitempty"`
+	DtmRevoked sql.NullTime   `db:"dtmRevoked" json:"dtmRevoked,omitempty"`
+	ReplacedBy sql.NullInt64  `db:"replacedBy" json:"replacedBy,omitempty"`
+}
+
 // UsageStatusLog represents the usage_status_log table
 type UsageStatusLog struct {
 	ID         uint            `db:"id" json:"id"`
@@ -296,6 +477,24 @@ type UsageStatusLog struct {
 	DtmCreated sql.NullTime    `db:"dtmCreated" json:"dtmCreated,omitempty"`
 }
 
+// WebhookEvent represents the webhook_event table
+type WebhookEvent struct {
+	ID             uint           `db:"id" json:"id"`
+	EventID        string         `db:"eventId" json:"eventId"`
+	Event          string         `db:"event" json:"event"`
+	Room           string         `db:"room" json:"room"`
+	CreatedAt      int64          `db:"createdAt" json:"createdAt"`
+	Payload        string         `db:"payload" json:"-"`
+	Status         string         `db:"status" json:"status"`
+	Attempts       int            `db:"attempts" json:"attempts"`
+	LastError      sql.NullString `db:"lastError" json:"lastError,omitempty"`
+	Owner          sql.NullString `db:"owner" json:"owner,omitempty"`
+	DtmReceived    sql.NullTime   `db:"dtmReceived" json:"dtmReceived,omitempty"`
+	DtmClaimed     sql.NullTime   `db:"dtmClaimed" json:"dtmClaimed,omitempty"`
+	DtmNextAttempt sql.NullTime   `db:"dtmNextAttempt" json:"dtmNextAttempt,omitempty"`
+	DtmProcessed   sql.NullTime   `db:"dtmProcessed" json:"dtmProcessed,omitempty"`
+}
+
 // RoomDetailResponse is the response for room detail
 type RoomDetailResponse struct {
 	ID                    uint      `json:"id"`
@@ -306,6 +505,8 @@ type RoomDetailResponse struct {
 	AutoRecord            int       `json:"autoRecord"`
 	RecordType            string    `json:"recordType"`
 	EncodingOptionsPreset string    `json:"encodingOptionsPreset"`
+	RecordLayout          string    `json:"recordLayout"`
+	RecordFileType        string    `json:"recordFileType"`
 	ChatEnabled           int       `json:"chatEnabled"`
 	MessageUnread         int       `json:"messageUnread"`
 	UserAgent             string    `json:"userAgent"`
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// APIKeyRepository handles api_key database operations
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// CreateAPIKeyParams holds parameters for creating an API key
+type CreateAPIKeyParams struct {
+	Name         string
+	KeyPrefix    string
+	KeyHash      string
+	Scopes       string
+	RateLimit    int
+	Service      int
+	Organization string
+	CreatedBy    string
+}
+
+// Create creates an API key
+func (r *APIKeyRepository) Create(ctx context.Context, params CreateAPIKeyParams) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO api_key
+		(name, keyPrefix, keyHash, scopes, rateLimit, service, organization, enabled, createdBy, dtmCreated, dtmUpdated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		params.Name,
+		params.KeyPrefix,
+		params.KeyHash,
+		params.Scopes,
+		params.RateLimit,
+		params.Service,
+		params.Organization,
+		params.CreatedBy,
+		now,
+		now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetByID gets an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	query := `SELECT * FROM api_key WHERE id = ?`
+
+	err := r.db.GetContext(ctx, &key, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// GetByHash gets the API key matching a key hash, including the previous key
+// of a rotated API key until its grace period ends
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash, now string) (*models.APIKey, error) {
+	var key models.APIKey
+	query := `SELECT * FROM api_key
+		WHERE keyHash = ? OR (previousKeyHash = ? AND dtmPreviousExpired > ?)
+		LIMIT 1`
+
+	err := r.db.GetContext(ctx, &key, query, keyHash, keyHash, now)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get api key by hash: %w", err)
+	}
+
+	return &key, nil
+}
+
+// List gets every API key
+func (r *APIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	query := `SELECT * FROM api_key ORDER BY name`
+
+	err := r.db.SelectContext(ctx, &keys, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Rotate replaces the key hash of an API key. The old hash stays valid until previousExpired.
+func (r *APIKeyRepository) Rotate(ctx context.Context, id uint, keyPrefix, keyHash, previousExpired string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE api_key SET previousKeyHash = keyHash, dtmPreviousExpired = ?,
+		keyPrefix = ?, keyHash = ?, dtmRotated = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, previousExpired, keyPrefix, keyHash, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to rotate api key: %w", err)
+	}
+	return nil
+}
+
+// Update updates the scopes and rate limit of an API key
+func (r *APIKeyRepository) Update(ctx context.Context, id uint, scopes string, rateLimit int) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE api_key SET scopes = ?, rateLimit = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, scopes, rateLimit, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+	return nil
+}
+
+// SetEnabled enables or disables an API key
+func (r *APIKeyRepository) SetEnabled(ctx context.Context, id uint, enabled int) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE api_key SET enabled = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, enabled, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key enabled: %w", err)
+	}
+	return nil
+}
+
+// TouchLastUsed records when and from where an API key was last used.
+// Writes are skipped while the stored time is less than a minute old.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uint, ip string) error {
+	now := time.Now()
+	query := `UPDATE api_key SET dtmLastUsed = ?, lastUsedIp = ?
+		WHERE id = ? AND (dtmLastUsed IS NULL OR dtmLastUsed < ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		now.Format("2006-01-02 15:04:05"),
+		ip,
+		id,
+		now.Add(-time.Minute).Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes an API key
+func (r *APIKeyRepository) Delete(ctx context.Context, id uint) error {
+	query := `DELETE FROM api_key WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete api key: %w", err)
+	}
+	return nil
+}
@@ -43,9 +43,14 @@ func (r *CaseRepository) Create(ctx context.Context, params CreateCaseParams) (i
 		(caseId, service, roomId, operationNumber, status, hn, patientMobile, mobileCreated, caseType, userName, organization, dtmCreated)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
+	organization := params.Organization
+	if tenant, ok := TenantFromContext(ctx); ok && tenant.Organization != "" {
+		organization = tenant.Organization
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
 		params.CaseID,
-		params.Service,
+		tenantService(ctx, params.Service),
 		params.RoomID,
 		params.OperationNumber,
 		params.Status,
@@ -54,7 +59,7 @@ func (r *CaseRepository) Create(ctx context.Context, params CreateCaseParams) (i
 		params.MobileCreated,
 		params.CaseType,
 		params.UserName,
-		params.Organization,
+		organization,
 		dtmCreated,
 	)
 	if err != nil {
@@ -67,9 +72,10 @@ func (r *CaseRepository) Create(ctx context.Context, params CreateCaseParams) (i
 // GetByCaseID gets case by caseId
 func (r *CaseRepository) GetByCaseID(ctx context.Context, caseID int) (*models.CaseData, error) {
 	var caseData models.CaseData
-	query := `SELECT * FROM case_data WHERE caseId = ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `SELECT * FROM case_data WHERE caseId = ?` + scope
 
-	err := r.db.GetContext(ctx, &caseData, query, caseID)
+	err := r.db.GetContext(ctx, &caseData, query, append([]interface{}{caseID}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -83,9 +89,10 @@ func (r *CaseRepository) GetByCaseID(ctx context.Context, caseID int) (*models.C
 // GetByID gets case by ID
 func (r *CaseRepository) GetByID(ctx context.Context, id uint) (*models.CaseData, error) {
 	var caseData models.CaseData
-	query := `SELECT * FROM case_data WHERE id = ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `SELECT * FROM case_data WHERE id = ?` + scope
 
-	err := r.db.GetContext(ctx, &caseData, query, id)
+	err := r.db.GetContext(ctx, &caseData, query, append([]interface{}{id}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -98,9 +105,11 @@ func (r *CaseRepository) GetByID(ctx context.Context, id uint) (*models.CaseData
 
 // Update updates a case
 func (r *CaseRepository) Update(ctx context.Context, id uint, status, hn, patientMobile, caseType, userName string) error {
-	query := `UPDATE case_data SET status = ?, hn = ?, patientMobile = ?, caseType = ?, userName = ? WHERE id = ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `UPDATE case_data SET status = ?, hn = ?, patientMobile = ?, caseType = ?, userName = ? WHERE id = ?` + scope
 
-	_, err := r.db.ExecContext(ctx, query, status, hn, patientMobile, caseType, userName, id)
+	args := []interface{}{status, hn, patientMobile, caseType, userName, id}
+	_, err := r.db.ExecContext(ctx, query, append(args, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update case: %w", err)
 	}
@@ -110,9 +119,10 @@ func (r *CaseRepository) Update(ctx context.Context, id uint, status, hn, patien
 
 // UpdateStatus updates case status
 func (r *CaseRepository) UpdateStatus(ctx context.Context, caseID int, status string) error {
-	query := `UPDATE case_data SET status = ? WHERE caseId = ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `UPDATE case_data SET status = ? WHERE caseId = ?` + scope
 
-	_, err := r.db.ExecContext(ctx, query, status, caseID)
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{status, caseID}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update case status: %w", err)
 	}
@@ -123,9 +133,11 @@ func (r *CaseRepository) UpdateStatus(ctx context.Context, caseID int, status st
 // GetHistory gets case history with pagination
 func (r *CaseRepository) GetHistory(ctx context.Context, service, limit, offset int) ([]models.CaseData, error) {
 	var cases []models.CaseData
-	query := `SELECT * FROM case_data WHERE service = ? ORDER BY dtmCreated DESC LIMIT ? OFFSET ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `SELECT * FROM case_data WHERE service = ?` + scope + ` ORDER BY dtmCreated DESC LIMIT ? OFFSET ?`
 
-	err := r.db.SelectContext(ctx, &cases, query, service, limit, offset)
+	args := append([]interface{}{service}, scopeArgs...)
+	err := r.db.SelectContext(ctx, &cases, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get case history: %w", err)
 	}
@@ -136,9 +148,10 @@ func (r *CaseRepository) GetHistory(ctx context.Context, service, limit, offset
 // GetHistoryCount gets case history count
 func (r *CaseRepository) GetHistoryCount(ctx context.Context, service int) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM case_data WHERE service = ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `SELECT COUNT(*) FROM case_data WHERE service = ?` + scope
 
-	err := r.db.GetContext(ctx, &count, query, service)
+	err := r.db.GetContext(ctx, &count, query, append([]interface{}{service}, scopeArgs...)...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get case count: %w", err)
 	}
@@ -149,11 +162,12 @@ func (r *CaseRepository) GetHistoryCount(ctx context.Context, service int) (int,
 // GetRoomName gets room name by caseId and service
 func (r *CaseRepository) GetRoomName(ctx context.Context, caseID, service int) (string, error) {
 	var room string
+	scope, scopeArgs := andCaseTenant(ctx)
 	query := `SELECT room_conference.room FROM case_data
 		LEFT JOIN room_conference ON case_data.roomId = room_conference.id
-		WHERE case_data.caseId = ? AND case_data.service = ?`
+		WHERE case_data.caseId = ? AND case_data.service = ?` + scope
 
-	err := r.db.GetContext(ctx, &room, query, caseID, service)
+	err := r.db.GetContext(ctx, &room, query, append([]interface{}{caseID, service}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return "", nil
@@ -167,9 +181,10 @@ func (r *CaseRepository) GetRoomName(ctx context.Context, caseID, service int) (
 // GetByRoomID gets case by roomId
 func (r *CaseRepository) GetByRoomID(ctx context.Context, roomID int) (*models.CaseData, error) {
 	var caseData models.CaseData
-	query := `SELECT * FROM case_data WHERE roomId = ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `SELECT * FROM case_data WHERE roomId = ?` + scope
 
-	err := r.db.GetContext(ctx, &caseData, query, roomID)
+	err := r.db.GetContext(ctx, &caseData, query, append([]interface{}{roomID}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -183,9 +198,10 @@ func (r *CaseRepository) GetByRoomID(ctx context.Context, roomID int) (*models.C
 // GetByService gets cases by service
 func (r *CaseRepository) GetByService(ctx context.Context, service int) ([]models.CaseData, error) {
 	var cases []models.CaseData
-	query := `SELECT * FROM case_data WHERE service = ? ORDER BY dtmCreated DESC`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `SELECT * FROM case_data WHERE service = ?` + scope + ` ORDER BY dtmCreated DESC`
 
-	err := r.db.SelectContext(ctx, &cases, query, service)
+	err := r.db.SelectContext(ctx, &cases, query, append([]interface{}{service}, scopeArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cases by service: %w", err)
 	}
@@ -193,11 +209,31 @@ func (r *CaseRepository) GetByService(ctx context.Context, service int) ([]model
 	return cases, nil
 }
 
+// SetLegalHold places a case under legal hold or releases it
+func (r *CaseRepository) SetLegalHold(ctx context.Context, id uint, hold bool, reason, actor string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `UPDATE case_data SET legalHold = 1, legalHoldReason = ?, legalHoldBy = ?, dtmLegalHold = ? WHERE id = ?` + scope
+	args := []interface{}{reason, actor, now, id}
+	if !hold {
+		query = `UPDATE case_data SET legalHold = 0, legalHoldReason = NULL, legalHoldBy = NULL, dtmLegalHold = NULL WHERE id = ?` + scope
+		args = []interface{}{id}
+	}
+
+	_, err := r.db.ExecContext(ctx, query, append(args, scopeArgs...)...)
+	if err != nil {
+		return fmt.Errorf("failed to set case legal hold: %w", err)
+	}
+
+	return nil
+}
+
 // Delete deletes a case
 func (r *CaseRepository) Delete(ctx context.Context, id uint) error {
-	query := `DELETE FROM case_data WHERE id = ?`
+	scope, scopeArgs := andCaseTenant(ctx)
+	query := `DELETE FROM case_data WHERE id = ?` + scope
 
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{id}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete case: %w", err)
 	}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// EncodeJobRepository handles encode_job database operations
+type EncodeJobRepository struct {
+	db *sqlx.DB
+}
+
+// NewEncodeJobRepository creates a new EncodeJobRepository
+func NewEncodeJobRepository(db *sqlx.DB) *EncodeJobRepository {
+	return &EncodeJobRepository{db: db}
+}
+
+// CreateEncodeJobParams holds parameters for queueing an encode job
+type CreateEncodeJobParams struct {
+	RecordID      int
+	Room          string
+	Source        string
+	MaxAttempts   int
+	CallbackToken string
+}
+
+// EncodeJobFilter holds filters for listing encode jobs
+type EncodeJobFilter struct {
+	Room   string
+	Status string
+}
+
+// Create queues an encode job. Returns false when the record already has one.
+func (r *EncodeJobRepository) Create(ctx context.Context, params CreateEncodeJobParams) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT IGNORE INTO encode_job
+		(recordId, room, source, status, attempts, maxAttempts, callbackToken, dtmNextAttempt, dtmCreated, dtmUpdated)
+		VALUES (?, ?, ?, 'queued', 0, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		params.RecordID,
+		params.Room,
+		params.Source,
+		params.MaxAttempts,
+		params.CallbackToken,
+		now,
+		now,
+		now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create encode job: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to create encode job: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetByID gets an encode job by ID
+func (r *EncodeJobRepository) GetByID(ctx context.Context, id uint) (*models.EncodeJob, error) {
+	var job models.EncodeJob
+	scope, scopeArgs := andTenant(ctx, encodeJobTenantCondition)
+	query := `SELECT * FROM encode_job WHERE id = ?` + scope
+
+	err := r.db.GetContext(ctx, &job, query, append([]interface{}{id}, scopeArgs...)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get encode job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// List gets encode jobs matching the filter, newest first
+func (r *EncodeJobRepository) List(ctx context.Context, filter EncodeJobFilter, limit, offset int) ([]models.EncodeJob, error) {
+	var jobs []models.EncodeJob
+	query := `SELECT * FROM encode_job WHERE 1 = 1`
+	args := []interface{}{}
+
+	if filter.Room != "" {
+		query += ` AND room = ?`
+		args = append(args, filter.Room)
+	}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	scope, scopeArgs := andTenant(ctx, encodeJobTenantCondition)
+	query += scope + ` ORDER BY id DESC LIMIT ? OFFSET ?`
+	args = append(append(args, scopeArgs...), limit, offset)
+
+	err := r.db.SelectContext(ctx, &jobs, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list encode jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// GetUnqueuedRecords gets completed records since the given time that have no encode job yet
+func (r *EncodeJobRepository) GetUnqueuedRecords(ctx context.Context, since time.Time, limit int) ([]models.RecordMedia, error) {
+	var records []models.RecordMedia
+	query := `SELECT record_media.* FROM record_media
+		LEFT JOIN encode_job ON encode_job.recordId = record_media.id
+		WHERE record_media.status = 'complete' AND record_media.dtmCompleted >= ?
+			AND IFNULL(record_media.filePath, '') <> '' AND encode_job.id IS NULL
+		ORDER BY record_media.id ASC LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &records, query, since.Format("2006-01-02 15:04:05"), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records without encode job: %w", err)
+	}
+
+	return records, nil
+}
+
+// GetDueIDs gets IDs of queued encode jobs whose next attempt is due
+func (r *EncodeJobRepository) GetDueIDs(ctx context.Context, limit int) ([]uint, error) {
+	var ids []uint
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `SELECT id FROM encode_job WHERE status = 'queued' AND dtmNextAttempt <= ? ORDER BY dtmNextAttempt ASC LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &ids, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due encode jobs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Claim marks a queued job as encoding and counts the attempt.
+// Returns false when another worker already claimed it.
+func (r *EncodeJobRepository) Claim(ctx context.Context, id uint) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE encode_job SET status = 'encoding', attempts = attempts + 1, dtmSubmitted = ?, dtmUpdated = ?
+		WHERE id = ? AND status = 'queued'`
+
+	result, err := r.db.ExecContext(ctx, query, now, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim encode job: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// MarkSubmitted records the job ID the encode service assigned
+func (r *EncodeJobRepository) MarkSubmitted(ctx context.Context, id uint, encoderJobID string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE encode_job SET encoderJobId = NULLIF(?, ''), lastError = NULL, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, encoderJobID, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark encode job submitted: %w", err)
+	}
+
+	return nil
+}
+
+// MarkDone records the encoded output of an encoding job. Returns false when the job is not encoding.
+func (r *EncodeJobRepository) MarkDone(ctx context.Context, id uint, hls, thumbnail string) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE encode_job SET status = 'done', hls = ?, thumbnail = NULLIF(?, ''), lastError = NULL, dtmCompleted = ?, dtmUpdated = ?
+		WHERE id = ? AND status = 'encoding'`
+
+	result, err := r.db.ExecContext(ctx, query, hls, thumbnail, now, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark encode job done: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// MarkFailed records a failed attempt of an encoding job and sets the next status and attempt time.
+// Returns false when the job is not encoding.
+func (r *EncodeJobRepository) MarkFailed(ctx context.Context, id uint, status, lastError string, nextAttempt time.Time) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE encode_job SET status = ?, lastError = ?, dtmNextAttempt = ?, dtmUpdated = ?
+		WHERE id = ? AND status = 'encoding'`
+
+	result, err := r.db.ExecContext(ctx, query, status, lastError, nextAttempt.Format("2006-01-02 15:04:05"), now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark encode job failed: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Requeue puts a failed job back in the queue with a fresh attempt count
+func (r *EncodeJobRepository) Requeue(ctx context.Context, id uint) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE encode_job SET status = 'queued', attempts = 0, dtmNextAttempt = ?, dtmUpdated = ?
+		WHERE id = ? AND status = 'failed'`
+
+	result, err := r.db.ExecContext(ctx, query, now, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to requeue encode job: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetTimedOutIDs gets IDs of jobs encoding since before the given time without a callback
+func (r *EncodeJobRepository) GetTimedOutIDs(ctx context.Context, before time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	query := `SELECT id FROM encode_job WHERE status = 'encoding' AND dtmSubmitted < ? ORDER BY dtmSubmitted ASC LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &ids, query, before.Format("2006-01-02 15:04:05"), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timed out encode jobs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// DeleteByRecordID removes the encode job of a record
+func (r *EncodeJobRepository) DeleteByRecordID(ctx context.Context, recordID int) error {
+	query := `DELETE FROM encode_job WHERE recordId = ?`
+
+	_, err := r.db.ExecContext(ctx, query, recordID)
+	if err != nil {
+		return fmt.Errorf("failed to delete encode job: %w", err)
+	}
+
+	return nil
+}
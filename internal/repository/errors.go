@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// maxUniqueIDAttempts is how many random IDs an insert tries before giving up
+const maxUniqueIDAttempts = 5
+
+// mysqlErrDuplicateEntry is the MySQL error number for a unique key violation
+const mysqlErrDuplicateEntry = 1062
+
+// isDuplicateKeyError reports whether err is a unique key violation on the named key.
+// An empty key matches any unique key.
+func isDuplicateKeyError(err error, key string) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlErrDuplicateEntry {
+		return false
+	}
+	return key == "" || strings.Contains(mysqlErr.Message, key)
+}
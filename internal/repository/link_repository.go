@@ -23,6 +23,8 @@ func NewLinkRepository(db *sqlx.DB) *LinkRepository {
 
 // CreateLinkParams holds parameters for creating a link
 type CreateLinkParams struct {
+	Service               int
+	SMS                   int
 	Share                 int
 	Mobile                string
 	LinkID                string
@@ -41,51 +43,62 @@ type CreateLinkParams struct {
 	DtmCreated            string
 	DtmExpired            string
 	UserAgent             string
+	NewLinkID             func() string
 }
 
-// Create creates a new link
-func (r *LinkRepository) Create(ctx context.Context, params CreateLinkParams) (int64, error) {
+// Create creates a new link. When the link ID collides with an existing one and
+// params.NewLinkID is set, a new ID is generated and written back to params.LinkID.
+func (r *LinkRepository) Create(ctx context.Context, params *CreateLinkParams) (int64, error) {
 	query := `INSERT INTO link_connect(
-		share, mobile, linkID, room, recordId, crmSender, userType, linkType, userName,
+		service, sms, share, mobile, linkID, room, recordId, crmSender, userType, linkType, userName,
 		isAdmin, requireJoinPermission, requireUserName, requirePassword,
 		oneTimeLink, password, dtmCreated, dtmExpired, userAgent
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	result, err := r.db.ExecContext(ctx, query,
-		params.Share,
-		params.Mobile,
-		params.LinkID,
-		params.Room,
-		params.RecordID,
-		params.CrmSender,
-		params.UserType,
-		params.LinkType,
-		params.UserName,
-		params.IsAdmin,
-		params.RequireJoinPermission,
-		params.RequireUserName,
-		params.RequirePassword,
-		params.OneTimeLink,
-		params.Password,
-		params.DtmCreated,
-		params.DtmExpired,
-		params.UserAgent,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to create link: %w", err)
-	}
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for attempt := 1; ; attempt++ {
+		result, err := r.db.ExecContext(ctx, query,
+			tenantService(ctx, params.Service),
+			params.SMS,
+			params.Share,
+			params.Mobile,
+			params.LinkID,
+			params.Room,
+			params.RecordID,
+			params.CrmSender,
+			params.UserType,
+			params.LinkType,
+			params.UserName,
+			params.IsAdmin,
+			params.RequireJoinPermission,
+			params.RequireUserName,
+			params.RequirePassword,
+			params.OneTimeLink,
+			params.Password,
+			params.DtmCreated,
+			params.DtmExpired,
+			params.UserAgent,
+		)
+		if err == nil {
+			return result.LastInsertId()
+		}
 
-	return result.LastInsertId()
+		if params.NewLinkID == nil || attempt >= maxUniqueIDAttempts || !isDuplicateKeyError(err, "uq_link_connect_linkID") {
+			return 0, fmt.Errorf("failed to create link: %w", err)
+		}
+		params.LinkID = params.NewLinkID()
+	}
 }
 
 // GetByLinkID gets link detail by linkID
 func (r *LinkRepository) GetByLinkID(ctx context.Context, linkID string) (*models.LinkConnect, error) {
 	var link models.LinkConnect
-	query := `SELECT linkID, room, enabled, mobile, isAdmin, userName, userType, linkType, mobile,
-		requireJoinPermission, crmSender, requireUserName, requirePassword, oneTimeLink, dtmCreated, dtmExpired
+	query := `SELECT linkID, room, enabled, mobile, isAdmin, userName, userType, linkType, mobile, service, sms, smsDeliveryStatus,
+		requireJoinPermission, crmSender, requireUserName, requirePassword, oneTimeLink, password,
+		dtmCreated, dtmExpired, dtmRedeemed
 		FROM link_connect WHERE linkID = ?`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
 
-	err := r.db.GetContext(ctx, &link, query, linkID)
+	err := r.db.GetContext(ctx, &link, query+scope, append([]interface{}{linkID}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -109,7 +122,9 @@ func (r *LinkRepository) GetByRoomAndUserType(ctx context.Context, room, userTyp
 		query += ` AND userType = ?`
 		args = append(args, userType)
 	}
-	query += ` ORDER BY id DESC LIMIT 1`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query += scope + ` ORDER BY id DESC LIMIT 1`
+	args = append(args, scopeArgs...)
 
 	err := r.db.GetContext(ctx, &link, query, args...)
 	if err != nil {
@@ -159,28 +174,29 @@ func (r *LinkRepository) UpdatePatientLocation(ctx context.Context, linkID strin
 	return nil
 }
 
-// GetOneTimeLinkStatus gets one time link status
-func (r *LinkRepository) GetOneTimeLinkStatus(ctx context.Context, linkID string) (int, error) {
-	var status int
-	query := `SELECT oneTimeLink FROM link_connect WHERE linkID = ?`
+// Redeem marks a one-time link as used. Returns false when it was already redeemed.
+func (r *LinkRepository) Redeem(ctx context.Context, linkID, now string) (bool, error) {
+	query := `UPDATE link_connect SET dtmRedeemed = ? WHERE linkID = ? AND dtmRedeemed IS NULL`
 
-	err := r.db.GetContext(ctx, &status, query, linkID)
+	result, err := r.db.ExecContext(ctx, query, now, linkID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, nil
-		}
-		return 0, fmt.Errorf("failed to get one time link status: %w", err)
+		return false, fmt.Errorf("failed to redeem link: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to redeem link: %w", err)
 	}
 
-	return status, nil
+	return affected > 0, nil
 }
 
-// UpdateOneTimeLink updates one time link status
-func (r *LinkRepository) UpdateOneTimeLink(ctx context.Context, linkID string, status int) error {
-	query := `UPDATE link_connect SET oneTimeLink = ? WHERE linkID = ?`
-	_, err := r.db.ExecContext(ctx, query, status, linkID)
+// UpdatePassword updates the stored password hash of a link
+func (r *LinkRepository) UpdatePassword(ctx context.Context, linkID, password string) error {
+	query := `UPDATE link_connect SET password = ? WHERE linkID = ?`
+	_, err := r.db.ExecContext(ctx, query, password, linkID)
 	if err != nil {
-		return fmt.Errorf("failed to update one time link: %w", err)
+		return fmt.Errorf("failed to update link password: %w", err)
 	}
 	return nil
 }
@@ -250,9 +266,10 @@ func (r *LinkRepository) UpdateErrorVideo(ctx context.Context, linkID, errorMsg
 // GetLinkIDList gets links by room and mobile
 func (r *LinkRepository) GetLinkIDList(ctx context.Context, room, mobile string) ([]models.LinkConnect, error) {
 	var links []models.LinkConnect
-	query := `SELECT * FROM link_connect WHERE room = ? AND mobile = ?`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT * FROM link_connect WHERE room = ? AND mobile = ?` + scope
 
-	err := r.db.SelectContext(ctx, &links, query, room, mobile)
+	err := r.db.SelectContext(ctx, &links, query, append([]interface{}{room, mobile}, scopeArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get link list: %w", err)
 	}
@@ -291,8 +308,9 @@ func (r *LinkRepository) GetLastDomainIndex(ctx context.Context, excludeLinkID s
 
 // UpdateLinkEnabled updates link enabled status
 func (r *LinkRepository) UpdateLinkEnabled(ctx context.Context, room, linkType string, enabled int) error {
-	query := `UPDATE link_connect SET enabled = ? WHERE room = ? AND linkType = ?`
-	_, err := r.db.ExecContext(ctx, query, enabled, room, linkType)
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE link_connect SET enabled = ? WHERE room = ? AND linkType = ?` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{enabled, room, linkType}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update link enabled: %w", err)
 	}
@@ -335,9 +353,10 @@ func (r *LinkRepository) GetUserAgentByID(ctx context.Context, id int) (string,
 func (r *LinkRepository) GetLatLngGroup(ctx context.Context, room, userType string) ([]models.LinkConnect, error) {
 	var links []models.LinkConnect
 	query := `SELECT id, mobile, share, userName, accuracy, latitude, longitude, errorLocation
-		FROM link_connect WHERE room = ? AND userType = ? ORDER BY id DESC`
+		FROM link_connect WHERE room = ? AND userType = ?`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
 
-	err := r.db.SelectContext(ctx, &links, query, room, userType)
+	err := r.db.SelectContext(ctx, &links, query+scope+` ORDER BY id DESC`, append([]interface{}{room, userType}, scopeArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get lat/lng group: %w", err)
 	}
@@ -354,3 +373,78 @@ func (r *LinkRepository) UpdateLinkUserName(ctx context.Context, linkID, userNam
 	}
 	return nil
 }
+
+// UpdateSMS updates the SMS status of a link
+func (r *LinkRepository) UpdateSMS(ctx context.Context, linkID string, sms int) error {
+	query := `UPDATE link_connect SET sms = ? WHERE linkID = ?`
+	_, err := r.db.ExecContext(ctx, query, sms, linkID)
+	if err != nil {
+		return fmt.Errorf("failed to update link sms: %w", err)
+	}
+	return nil
+}
+
+// UpdateSMSDeliveryStatus updates the carrier delivery status of the link's SMS
+func (r *LinkRepository) UpdateSMSDeliveryStatus(ctx context.Context, linkID, status string) error {
+	query := `UPDATE link_connect SET smsDeliveryStatus = ? WHERE linkID = ?`
+	_, err := r.db.ExecContext(ctx, query, status, linkID)
+	if err != nil {
+		return fmt.Errorf("failed to update link sms delivery status: %w", err)
+	}
+	return nil
+}
+
+// GetExpiredEnabled gets enabled links whose expiry has passed, oldest first
+func (r *LinkRepository) GetExpiredEnabled(ctx context.Context, now string, limit int) ([]models.LinkConnect, error) {
+	var links []models.LinkConnect
+	query := `SELECT linkID, room, mobile, userType, linkType, dtmExpired
+		FROM link_connect WHERE dtmExpired <= ? AND enabled = 1
+		ORDER BY dtmExpired ASC LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &links, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired links: %w", err)
+	}
+
+	return links, nil
+}
+
+// DisableExpired disables a link if it is still enabled and has expired.
+// Returns false when another caller already disabled it.
+func (r *LinkRepository) DisableExpired(ctx context.Context, linkID, now string) (bool, error) {
+	query := `UPDATE link_connect SET enabled = 0 WHERE linkID = ? AND enabled = 1 AND dtmExpired <= ?`
+
+	result, err := r.db.ExecContext(ctx, query, linkID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to disable expired link: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to disable expired link: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetEnabled updates the enabled flag of a single link
+func (r *LinkRepository) SetEnabled(ctx context.Context, linkID string, enabled int) error {
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE link_connect SET enabled = ? WHERE linkID = ?` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{enabled, linkID}, scopeArgs...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update link enabled: %w", err)
+	}
+	return nil
+}
+
+// UpdateExpired updates the expiry of a link
+func (r *LinkRepository) UpdateExpired(ctx context.Context, linkID, dtmExpired string) error {
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE link_connect SET dtmExpired = ? WHERE linkID = ?` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{dtmExpired, linkID}, scopeArgs...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update link expired: %w", err)
+	}
+	return nil
+}
@@ -57,9 +57,10 @@ func (r *NotificationRepository) Create(ctx context.Context, params CreateNotifi
 // GetByID gets notification by ID
 func (r *NotificationRepository) GetByID(ctx context.Context, notificationID int) (*models.Notification, error) {
 	var notification models.Notification
-	query := `SELECT * FROM notification WHERE notificationId = ?`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `SELECT * FROM notification WHERE notificationId = ?` + scope
 
-	err := r.db.GetContext(ctx, &notification, query, notificationID)
+	err := r.db.GetContext(ctx, &notification, query, append([]interface{}{notificationID}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -73,9 +74,10 @@ func (r *NotificationRepository) GetByID(ctx context.Context, notificationID int
 // GetAll gets all notifications
 func (r *NotificationRepository) GetAll(ctx context.Context) ([]models.Notification, error) {
 	var notifications []models.Notification
-	query := `SELECT * FROM notification ORDER BY dtmCreated DESC`
+	scope, scopeArgs := whereTenant(ctx, notificationTenantCondition)
+	query := `SELECT * FROM notification` + scope + ` ORDER BY dtmCreated DESC`
 
-	err := r.db.SelectContext(ctx, &notifications, query)
+	err := r.db.SelectContext(ctx, &notifications, query, scopeArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all notifications: %w", err)
 	}
@@ -86,9 +88,10 @@ func (r *NotificationRepository) GetAll(ctx context.Context) ([]models.Notificat
 // GetUnread gets all unread notifications
 func (r *NotificationRepository) GetUnread(ctx context.Context) ([]models.Notification, error) {
 	var notifications []models.Notification
-	query := `SELECT * FROM notification WHERE ` + "`read`" + ` = 0 ORDER BY dtmCreated DESC`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `SELECT * FROM notification WHERE ` + "`read`" + ` = 0` + scope + ` ORDER BY dtmCreated DESC`
 
-	err := r.db.SelectContext(ctx, &notifications, query)
+	err := r.db.SelectContext(ctx, &notifications, query, scopeArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get unread notifications: %w", err)
 	}
@@ -99,9 +102,10 @@ func (r *NotificationRepository) GetUnread(ctx context.Context) ([]models.Notifi
 // UpdateReadStatus updates notification read status
 func (r *NotificationRepository) UpdateReadStatus(ctx context.Context, notificationID int, read int) error {
 	dtmRead := time.Now().Format("2006-01-02 15:04:05")
-	query := `UPDATE notification SET ` + "`read`" + ` = ?, dtmRead = ? WHERE notificationId = ?`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `UPDATE notification SET ` + "`read`" + ` = ?, dtmRead = ? WHERE notificationId = ?` + scope
 
-	_, err := r.db.ExecContext(ctx, query, read, dtmRead, notificationID)
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{read, dtmRead, notificationID}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update read status: %w", err)
 	}
@@ -111,9 +115,10 @@ func (r *NotificationRepository) UpdateReadStatus(ctx context.Context, notificat
 
 // Delete deletes a notification
 func (r *NotificationRepository) Delete(ctx context.Context, notificationID int) error {
-	query := `DELETE FROM notification WHERE notificationId = ?`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `DELETE FROM notification WHERE notificationId = ?` + scope
 
-	_, err := r.db.ExecContext(ctx, query, notificationID)
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{notificationID}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete notification: %w", err)
 	}
@@ -124,9 +129,10 @@ func (r *NotificationRepository) Delete(ctx context.Context, notificationID int)
 // GetByUserName gets notifications by username
 func (r *NotificationRepository) GetByUserName(ctx context.Context, userName string) ([]models.Notification, error) {
 	var notifications []models.Notification
-	query := `SELECT * FROM notification WHERE userName = ? ORDER BY dtmCreated DESC`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `SELECT * FROM notification WHERE userName = ?` + scope + ` ORDER BY dtmCreated DESC`
 
-	err := r.db.SelectContext(ctx, &notifications, query, userName)
+	err := r.db.SelectContext(ctx, &notifications, query, append([]interface{}{userName}, scopeArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get notifications by username: %w", err)
 	}
@@ -137,9 +143,10 @@ func (r *NotificationRepository) GetByUserName(ctx context.Context, userName str
 // GetByCaseID gets notifications by case ID
 func (r *NotificationRepository) GetByCaseID(ctx context.Context, caseID int) ([]models.Notification, error) {
 	var notifications []models.Notification
-	query := `SELECT * FROM notification WHERE caseId = ? ORDER BY dtmCreated DESC`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `SELECT * FROM notification WHERE caseId = ?` + scope + ` ORDER BY dtmCreated DESC`
 
-	err := r.db.SelectContext(ctx, &notifications, query, caseID)
+	err := r.db.SelectContext(ctx, &notifications, query, append([]interface{}{caseID}, scopeArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get notifications by case ID: %w", err)
 	}
@@ -150,9 +157,10 @@ func (r *NotificationRepository) GetByCaseID(ctx context.Context, caseID int) ([
 // GetWithPagination gets notifications with pagination
 func (r *NotificationRepository) GetWithPagination(ctx context.Context, limit, offset int) ([]models.Notification, error) {
 	var notifications []models.Notification
-	query := `SELECT * FROM notification ORDER BY dtmCreated DESC LIMIT ? OFFSET ?`
+	scope, scopeArgs := whereTenant(ctx, notificationTenantCondition)
+	query := `SELECT * FROM notification` + scope + ` ORDER BY dtmCreated DESC LIMIT ? OFFSET ?`
 
-	err := r.db.SelectContext(ctx, &notifications, query, limit, offset)
+	err := r.db.SelectContext(ctx, &notifications, query, append(scopeArgs, limit, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get notifications: %w", err)
 	}
@@ -163,9 +171,10 @@ func (r *NotificationRepository) GetWithPagination(ctx context.Context, limit, o
 // GetUnreadCount gets count of unread notifications
 func (r *NotificationRepository) GetUnreadCount(ctx context.Context) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM notification WHERE ` + "`read`" + ` = 0`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `SELECT COUNT(*) FROM notification WHERE ` + "`read`" + ` = 0` + scope
 
-	err := r.db.GetContext(ctx, &count, query)
+	err := r.db.GetContext(ctx, &count, query, scopeArgs...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get unread count: %w", err)
 	}
@@ -176,9 +185,10 @@ func (r *NotificationRepository) GetUnreadCount(ctx context.Context) (int, error
 // MarkAllAsRead marks all notifications as read
 func (r *NotificationRepository) MarkAllAsRead(ctx context.Context) error {
 	dtmRead := time.Now().Format("2006-01-02 15:04:05")
-	query := `UPDATE notification SET ` + "`read`" + ` = 1, dtmRead = ? WHERE ` + "`read`" + ` = 0`
+	scope, scopeArgs := andTenant(ctx, notificationTenantCondition)
+	query := `UPDATE notification SET ` + "`read`" + ` = 1, dtmRead = ? WHERE ` + "`read`" + ` = 0` + scope
 
-	_, err := r.db.ExecContext(ctx, query, dtmRead)
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{dtmRead}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to mark all as read: %w", err)
 	}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RecordAuditRepository handles record_audit database operations
+type RecordAuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewRecordAuditRepository creates a new RecordAuditRepository
+func NewRecordAuditRepository(db *sqlx.DB) *RecordAuditRepository {
+	return &RecordAuditRepository{db: db}
+}
+
+// CreateRecordAuditParams holds parameters for auditing a recording action
+type CreateRecordAuditParams struct {
+	Action   string
+	RecordID int
+	CaseID   int
+	Room     string
+	Service  int
+	Files    string
+	Reason   string
+	Actor    string
+}
+
+// RecordAuditFilter holds filters for listing audit entries
+type RecordAuditFilter struct {
+	Action   string
+	Room     string
+	RecordID int
+}
+
+// Create stores an audit entry
+func (r *RecordAuditRepository) Create(ctx context.Context, params CreateRecordAuditParams) error {
+	dtmCreated := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO record_audit
+		(action, recordId, caseId, room, service, files, reason, actor, dtmCreated)
+		VALUES (?, NULLIF(?, 0), NULLIF(?, 0), NULLIF(?, ''), NULLIF(?, 0), NULLIF(?, ''), NULLIF(?, ''), ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		params.Action,
+		params.RecordID,
+		params.CaseID,
+		params.Room,
+		params.Service,
+		params.Files,
+		params.Reason,
+		params.Actor,
+		dtmCreated,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create record audit: %w", err)
+	}
+
+	return nil
+}
+
+// List gets audit entries matching the filter, newest first
+func (r *RecordAuditRepository) List(ctx context.Context, filter RecordAuditFilter, limit, offset int) ([]models.RecordAudit, error) {
+	var entries []models.RecordAudit
+	query := `SELECT * FROM record_audit WHERE 1 = 1`
+	args := []interface{}{}
+
+	if filter.Action != "" {
+		query += ` AND action = ?`
+		args = append(args, filter.Action)
+	}
+	if filter.Room != "" {
+		query += ` AND room = ?`
+		args = append(args, filter.Room)
+	}
+	if filter.RecordID != 0 {
+		query += ` AND recordId = ?`
+		args = append(args, filter.RecordID)
+	}
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query += scope + ` ORDER BY id DESC LIMIT ? OFFSET ?`
+	args = append(append(args, scopeArgs...), limit, offset)
+
+	err := r.db.SelectContext(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list record audit: %w", err)
+	}
+
+	return entries, nil
+}
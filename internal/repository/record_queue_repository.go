@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RecordQueueRepository handles record_queue database operations
+type RecordQueueRepository struct {
+	db *sqlx.DB
+}
+
+// NewRecordQueueRepository creates a new RecordQueueRepository
+func NewRecordQueueRepository(db *sqlx.DB) *RecordQueueRepository {
+	return &RecordQueueRepository{db: db}
+}
+
+// CreateRecordQueueParams holds parameters for queueing a recording request
+type CreateRecordQueueParams struct {
+	Room       string
+	Identity   string
+	RecordType string
+	Options    string
+	Priority   int
+	CreatedBy  string
+}
+
+// Create queues a recording request
+func (r *RecordQueueRepository) Create(ctx context.Context, params CreateRecordQueueParams) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO record_queue
+		(room, identity, recordType, options, priority, status, createdBy, dtmCreated, dtmUpdated)
+		VALUES (?, NULLIF(?, ''), ?, ?, ?, 'queued', ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		params.Room,
+		params.Identity,
+		params.RecordType,
+		params.Options,
+		params.Priority,
+		params.CreatedBy,
+		now,
+		now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create record queue: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetByID gets a queued recording request by ID
+func (r *RecordQueueRepository) GetByID(ctx context.Context, id uint) (*models.RecordQueue, error) {
+	var entry models.RecordQueue
+	scope, scopeArgs := andTenant(ctx, recordQueueTenantCondition)
+	query := `SELECT * FROM record_queue WHERE id = ?` + scope
+
+	err := r.db.GetContext(ctx, &entry, query, append([]interface{}{id}, scopeArgs...)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get record queue: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// GetQueued gets the queued request for the same room, participant and record type, if any
+func (r *RecordQueueRepository) GetQueued(ctx context.Context, room, identity, recordType string) (*models.RecordQueue, error) {
+	var entry models.RecordQueue
+	query := `SELECT * FROM record_queue
+		WHERE room = ? AND IFNULL(identity, '') = ? AND recordType = ? AND status = 'queued'
+		ORDER BY id LIMIT 1`
+
+	err := r.db.GetContext(ctx, &entry, query, room, identity, recordType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get queued record: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// ListQueued lists queued requests in the order they will start
+func (r *RecordQueueRepository) ListQueued(ctx context.Context) ([]models.RecordQueue, error) {
+	var entries []models.RecordQueue
+	scope, scopeArgs := andTenant(ctx, recordQueueTenantCondition)
+	query := `SELECT * FROM record_queue WHERE status = 'queued'` + scope + ` ORDER BY priority DESC, id ASC`
+
+	err := r.db.SelectContext(ctx, &entries, query, scopeArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list record queue: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountQueued counts queued requests
+func (r *RecordQueueRepository) CountQueued(ctx context.Context) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM record_queue WHERE status = 'queued'`
+
+	err := r.db.GetContext(ctx, &count, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count record queue: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetNextQueued gets the queued request that starts next: highest priority, then oldest
+func (r *RecordQueueRepository) GetNextQueued(ctx context.Context) (*models.RecordQueue, error) {
+	var entry models.RecordQueue
+	query := `SELECT * FROM record_queue WHERE status = 'queued' ORDER BY priority DESC, id ASC LIMIT 1`
+
+	err := r.db.GetContext(ctx, &entry, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get next record queue: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// Claim marks a queued request as starting. Returns false when another worker claimed it first.
+func (r *RecordQueueRepository) Claim(ctx context.Context, id uint) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_queue SET status = 'starting', dtmUpdated = ? WHERE id = ? AND status = 'queued'`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim record queue: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Requeue returns a claimed request to the queue
+func (r *RecordQueueRepository) Requeue(ctx context.Context, id uint) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_queue SET status = 'queued', dtmUpdated = ? WHERE id = ? AND status = 'starting'`
+
+	_, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to requeue record queue: %w", err)
+	}
+	return nil
+}
+
+// MarkStarted records the egress started for a request
+func (r *RecordQueueRepository) MarkStarted(ctx context.Context, id uint, egressID string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_queue SET status = 'started', egressId = ?, lastError = NULL, dtmStarted = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, egressID, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark record queue started: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records why a request could not start
+func (r *RecordQueueRepository) MarkFailed(ctx context.Context, id uint, lastError string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_queue SET status = 'failed', lastError = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, lastError, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark record queue failed: %w", err)
+	}
+	return nil
+}
+
+// Cancel cancels a queued request. Returns false when it is no longer queued.
+func (r *RecordQueueRepository) Cancel(ctx context.Context, id uint) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	scope, scopeArgs := andTenant(ctx, recordQueueTenantCondition)
+	query := `UPDATE record_queue SET status = 'cancelled', dtmUpdated = ? WHERE id = ? AND status = 'queued'` + scope
+
+	result, err := r.db.ExecContext(ctx, query, append([]interface{}{now, id}, scopeArgs...)...)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel record queue: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// CancelByRoom cancels the queued requests of a room, or of one participant when identity is set
+func (r *RecordQueueRepository) CancelByRoom(ctx context.Context, room, identity string) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_queue SET status = 'cancelled', dtmUpdated = ? WHERE room = ? AND status = 'queued'`
+	args := []interface{}{now, room}
+
+	if identity != "" {
+		query += ` AND identity = ?`
+		args = append(args, identity)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel record queue by room: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ReleaseStale returns requests stuck in starting since before the given time to the queue
+func (r *RecordQueueRepository) ReleaseStale(ctx context.Context, before time.Time) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_queue SET status = 'queued', dtmUpdated = ? WHERE status = 'starting' AND dtmUpdated < ?`
+
+	result, err := r.db.ExecContext(ctx, query, now, before.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale record queue: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -25,6 +25,7 @@ func NewRecordRepository(db *sqlx.DB) *RecordRepository {
 type CreateRecordParams struct {
 	EgressID   string
 	Room       string
+	Identity   string
 	FileName   string
 	FilePath   string
 	FileSize   int
@@ -40,12 +41,13 @@ type CreateRecordParams struct {
 func (r *RecordRepository) Create(ctx context.Context, params CreateRecordParams) (int64, error) {
 	dtmCreated := time.Now().Format("2006-01-02 15:04:05")
 	query := `INSERT INTO record_media
-		(egressId, room, fileName, filePath, fileSize, duration, recordType, status, hls, encode, uploader, dtmCreated, startRecord)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		(egressId, room, identity, fileName, filePath, fileSize, duration, recordType, status, hls, encode, uploader, dtmCreated, startRecord)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	result, err := r.db.ExecContext(ctx, query,
 		params.EgressID,
 		params.Room,
+		params.Identity,
 		params.FileName,
 		params.FilePath,
 		params.FileSize,
@@ -68,9 +70,10 @@ func (r *RecordRepository) Create(ctx context.Context, params CreateRecordParams
 // GetByID gets record by ID
 func (r *RecordRepository) GetByID(ctx context.Context, id int) (*models.RecordMedia, error) {
 	var record models.RecordMedia
-	query := `SELECT * FROM record_media WHERE id = ?`
+	scope, scopeArgs := andTenant(ctx, recordTenantCondition)
+	query := `SELECT * FROM record_media WHERE id = ?` + scope
 
-	err := r.db.GetContext(ctx, &record, query, id)
+	err := r.db.GetContext(ctx, &record, query, append([]interface{}{id}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -100,9 +103,10 @@ func (r *RecordRepository) GetByEgressID(ctx context.Context, egressID string) (
 // GetByRoom gets records by room
 func (r *RecordRepository) GetByRoom(ctx context.Context, room string) ([]models.RecordMedia, error) {
 	var records []models.RecordMedia
-	query := `SELECT * FROM record_media WHERE room = ? ORDER BY dtmCreated DESC`
+	scope, scopeArgs := andTenant(ctx, recordTenantCondition)
+	query := `SELECT * FROM record_media WHERE room = ?` + scope + ` ORDER BY dtmCreated DESC`
 
-	err := r.db.SelectContext(ctx, &records, query, room)
+	err := r.db.SelectContext(ctx, &records, query, append([]interface{}{room}, scopeArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get records by room: %w", err)
 	}
@@ -110,6 +114,20 @@ func (r *RecordRepository) GetByRoom(ctx context.Context, room string) ([]models
 	return records, nil
 }
 
+// GetEncodedByRoom gets the records of a room that have an encoded HLS playlist
+func (r *RecordRepository) GetEncodedByRoom(ctx context.Context, room string) ([]models.RecordMedia, error) {
+	var records []models.RecordMedia
+	query := `SELECT * FROM record_media WHERE room = ? AND status = 'complete' AND encode = 1 AND IFNULL(hls, '') <> ''
+		ORDER BY dtmCreated DESC`
+
+	err := r.db.SelectContext(ctx, &records, query, room)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encoded records by room: %w", err)
+	}
+
+	return records, nil
+}
+
 // Update updates a record
 func (r *RecordRepository) Update(ctx context.Context, id int, fileName, filePath, status string, fileSize, duration int) error {
 	dtmUpdated := time.Now().Format("2006-01-02 15:04:05")
@@ -153,14 +171,16 @@ func (r *RecordRepository) UpdateByEgressID(ctx context.Context, egressID, fileN
 // GetFileHistory gets file history
 func (r *RecordRepository) GetFileHistory(ctx context.Context, room string) ([]models.RecordMedia, error) {
 	var records []models.RecordMedia
-	query := `SELECT * FROM record_media`
+	query := `SELECT * FROM record_media WHERE 1=1`
 	args := []interface{}{}
 
 	if room != "" {
-		query += ` WHERE room = ?`
+		query += ` AND room = ?`
 		args = append(args, room)
 	}
-	query += ` ORDER BY dtmCreated DESC`
+	scope, scopeArgs := andTenant(ctx, recordTenantCondition)
+	query += scope + ` ORDER BY dtmCreated DESC`
+	args = append(args, scopeArgs...)
 
 	err := r.db.SelectContext(ctx, &records, query, args...)
 	if err != nil {
@@ -170,14 +190,15 @@ func (r *RecordRepository) GetFileHistory(ctx context.Context, room string) ([]m
 	return records, nil
 }
 
-// GetRecordQueue gets record queue (records with status 'recording')
-func (r *RecordRepository) GetRecordQueue(ctx context.Context) ([]models.RecordMedia, error) {
+// GetRecording gets records with status 'recording'
+func (r *RecordRepository) GetRecording(ctx context.Context) ([]models.RecordMedia, error) {
 	var records []models.RecordMedia
-	query := `SELECT * FROM record_media WHERE status = 'recording' ORDER BY dtmCreated DESC`
+	scope, scopeArgs := andTenant(ctx, recordTenantCondition)
+	query := `SELECT * FROM record_media WHERE status = 'recording'` + scope + ` ORDER BY dtmCreated DESC`
 
-	err := r.db.SelectContext(ctx, &records, query)
+	err := r.db.SelectContext(ctx, &records, query, scopeArgs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get record queue: %w", err)
+		return nil, fmt.Errorf("failed to get recording records: %w", err)
 	}
 
 	return records, nil
@@ -209,11 +230,25 @@ func (r *RecordRepository) GetActiveRecordCount(ctx context.Context) (int, error
 	return count, nil
 }
 
+// GetActiveCountByRoom gets count of active recordings in a room
+func (r *RecordRepository) GetActiveCountByRoom(ctx context.Context, room string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM record_media WHERE room = ? AND status = 'recording'`
+
+	err := r.db.GetContext(ctx, &count, query, room)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active record count by room: %w", err)
+	}
+
+	return count, nil
+}
+
 // Delete deletes a record
 func (r *RecordRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM record_media WHERE id = ?`
+	scope, scopeArgs := andTenant(ctx, recordTenantCondition)
+	query := `DELETE FROM record_media WHERE id = ?` + scope
 
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{id}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete record: %w", err)
 	}
@@ -221,6 +256,74 @@ func (r *RecordRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// recordNotOnLegalHold excludes records whose room belongs to a case under legal hold
+const recordNotOnLegalHold = `NOT EXISTS (SELECT 1 FROM case_data
+	JOIN room_conference ON case_data.roomId = room_conference.id
+	WHERE room_conference.room = record_media.room AND case_data.legalHold = 1)`
+
+// GetArchiveCandidates gets finished, unarchived records of a service created before the
+// given time whose case is not under legal hold
+func (r *RecordRepository) GetArchiveCandidates(ctx context.Context, service int, before time.Time, limit int) ([]models.RecordMedia, error) {
+	var records []models.RecordMedia
+	query := `SELECT * FROM record_media
+		WHERE room IN (SELECT room FROM room_conference WHERE service = ?)
+			AND status IN ('complete', 'failed') AND dtmArchived IS NULL AND dtmCreated < ?
+			AND ` + recordNotOnLegalHold + `
+		ORDER BY dtmCreated ASC LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &records, query, service, before.Format("2006-01-02 15:04:05"), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records to archive: %w", err)
+	}
+
+	return records, nil
+}
+
+// GetExpiredRecords gets finished records of a service created before the given time
+// whose case is not under legal hold
+func (r *RecordRepository) GetExpiredRecords(ctx context.Context, service int, before time.Time, limit int) ([]models.RecordMedia, error) {
+	var records []models.RecordMedia
+	query := `SELECT * FROM record_media
+		WHERE room IN (SELECT room FROM room_conference WHERE service = ?)
+			AND status IN ('complete', 'failed') AND dtmCreated < ?
+			AND ` + recordNotOnLegalHold + `
+		ORDER BY dtmCreated ASC LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &records, query, service, before.Format("2006-01-02 15:04:05"), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired records: %w", err)
+	}
+
+	return records, nil
+}
+
+// IsOnLegalHold reports whether a record belongs to a case under legal hold
+func (r *RecordRepository) IsOnLegalHold(ctx context.Context, id int) (bool, error) {
+	var held bool
+	query := `SELECT EXISTS (SELECT 1 FROM record_media WHERE id = ? AND NOT ` + recordNotOnLegalHold + `)`
+
+	err := r.db.GetContext(ctx, &held, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to check record legal hold: %w", err)
+	}
+
+	return held, nil
+}
+
+// MarkArchived records that the files of a record moved to the archive. Encode 3 marks the
+// HLS output as parked, so the recording is no longer offered for playback.
+func (r *RecordRepository) MarkArchived(ctx context.Context, id int, archivePath string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_media SET archivePath = ?, encode = 3, dtmArchived = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, archivePath, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark record archived: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateEncodeStatus updates encode status
 func (r *RecordRepository) UpdateEncodeStatus(ctx context.Context, id, encode int) error {
 	query := `UPDATE record_media SET encode = ? WHERE id = ?`
@@ -232,3 +335,16 @@ func (r *RecordRepository) UpdateEncodeStatus(ctx context.Context, id, encode in
 
 	return nil
 }
+
+// UpdateEncodeResult records the encoded HLS playlist and thumbnail of a record
+func (r *RecordRepository) UpdateEncodeResult(ctx context.Context, id int, hls, thumbnail string) error {
+	dtmUpdated := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE record_media SET encode = 1, hls = ?, thumbnail = NULLIF(?, ''), dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, hls, thumbnail, dtmUpdated, id)
+	if err != nil {
+		return fmt.Errorf("failed to update encode result: %w", err)
+	}
+
+	return nil
+}
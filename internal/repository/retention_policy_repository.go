@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RetentionPolicyRepository handles retention_policy database operations
+type RetentionPolicyRepository struct {
+	db *sqlx.DB
+}
+
+// NewRetentionPolicyRepository creates a new RetentionPolicyRepository
+func NewRetentionPolicyRepository(db *sqlx.DB) *RetentionPolicyRepository {
+	return &RetentionPolicyRepository{db: db}
+}
+
+// UpsertRetentionPolicyParams holds parameters for setting the retention policy of a service
+type UpsertRetentionPolicyParams struct {
+	Service     int
+	RetainDays  int
+	ArchiveDays int
+	Enabled     bool
+	UpdatedBy   string
+}
+
+// Upsert creates or replaces the retention policy of a service
+func (r *RetentionPolicyRepository) Upsert(ctx context.Context, params UpsertRetentionPolicyParams) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	enabled := 0
+	if params.Enabled {
+		enabled = 1
+	}
+	query := `INSERT INTO retention_policy
+		(service, retainDays, archiveDays, enabled, updatedBy, dtmCreated, dtmUpdated)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE retainDays = VALUES(retainDays), archiveDays = VALUES(archiveDays),
+			enabled = VALUES(enabled), updatedBy = VALUES(updatedBy), dtmUpdated = VALUES(dtmUpdated)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		tenantService(ctx, params.Service),
+		params.RetainDays,
+		params.ArchiveDays,
+		enabled,
+		params.UpdatedBy,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert retention policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetByService gets the retention policy of a service
+func (r *RetentionPolicyRepository) GetByService(ctx context.Context, service int) (*models.RetentionPolicy, error) {
+	var policy models.RetentionPolicy
+	query := `SELECT * FROM retention_policy WHERE service = ?`
+
+	err := r.db.GetContext(ctx, &policy, query, tenantService(ctx, service))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get retention policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// List gets all retention policies
+func (r *RetentionPolicyRepository) List(ctx context.Context) ([]models.RetentionPolicy, error) {
+	var policies []models.RetentionPolicy
+	scope, scopeArgs := whereTenant(ctx, serviceTenantCondition)
+	query := `SELECT * FROM retention_policy` + scope + ` ORDER BY service`
+
+	err := r.db.SelectContext(ctx, &policies, query, scopeArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// GetEnabled gets the enabled retention policies
+func (r *RetentionPolicyRepository) GetEnabled(ctx context.Context) ([]models.RetentionPolicy, error) {
+	var policies []models.RetentionPolicy
+	query := `SELECT * FROM retention_policy WHERE enabled = 1 AND (retainDays > 0 OR archiveDays > 0) ORDER BY service`
+
+	err := r.db.SelectContext(ctx, &policies, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled retention policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// Delete removes the retention policy of a service. Returns false when it had none.
+func (r *RetentionPolicyRepository) Delete(ctx context.Context, service int) (bool, error) {
+	query := `DELETE FROM retention_policy WHERE service = ?`
+
+	result, err := r.db.ExecContext(ctx, query, tenantService(ctx, service))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete retention policy: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
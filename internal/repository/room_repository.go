@@ -30,50 +30,63 @@ type CreateRoomParams struct {
 	AutoRecord            int
 	RecordType            string
 	EncodingOptionsPreset string
+	RecordLayout          string
+	RecordFileType        string
 	ChatEnabled           int
 	WebSocketURL          string
 	UserAgent             string
 	DtmCreated            string
 	DtmUpdated            string
 	DtmExpired            string
+	NewRoom               func() string
 }
 
-// Create creates a new room
-func (r *RoomRepository) Create(ctx context.Context, params CreateRoomParams) (int64, error) {
+// Create creates a new room. When the room name collides with an existing one and
+// params.NewRoom is set, a new name is generated and written back to params.Room.
+func (r *RoomRepository) Create(ctx context.Context, params *CreateRoomParams) (int64, error) {
 	query := `INSERT INTO room_conference
-		(status, roomType, room, service, recordId, autoRecord, recordType, encodingOptionsPreset, chatEnabled, webSocketURL, userAgent, dtmCreated, dtmUpdated, dtmExpired)
-		VALUES (?, ?, ?, ?, '', ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		(status, roomType, room, service, recordId, autoRecord, recordType, encodingOptionsPreset, recordLayout, recordFileType,
+		chatEnabled, webSocketURL, userAgent, dtmCreated, dtmUpdated, dtmExpired)
+		VALUES (?, ?, ?, ?, '', ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, ?, ?, ?)`
+
+	for attempt := 1; ; attempt++ {
+		result, err := r.db.ExecContext(ctx, query,
+			params.Status,
+			params.RoomType,
+			params.Room,
+			tenantService(ctx, params.Service),
+			params.AutoRecord,
+			params.RecordType,
+			params.EncodingOptionsPreset,
+			params.RecordLayout,
+			params.RecordFileType,
+			params.ChatEnabled,
+			params.WebSocketURL,
+			params.UserAgent,
+			params.DtmCreated,
+			params.DtmUpdated,
+			params.DtmExpired,
+		)
+		if err == nil {
+			return result.LastInsertId()
+		}
 
-	result, err := r.db.ExecContext(ctx, query,
-		params.Status,
-		params.RoomType,
-		params.Room,
-		params.Service,
-		params.AutoRecord,
-		params.RecordType,
-		params.EncodingOptionsPreset,
-		params.ChatEnabled,
-		params.WebSocketURL,
-		params.UserAgent,
-		params.DtmCreated,
-		params.DtmUpdated,
-		params.DtmExpired,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to create room: %w", err)
+		if params.NewRoom == nil || attempt >= maxUniqueIDAttempts || !isDuplicateKeyError(err, "uq_room_conference_room") {
+			return 0, fmt.Errorf("failed to create room: %w", err)
+		}
+		params.Room = params.NewRoom()
 	}
-
-	return result.LastInsertId()
 }
 
 // GetByRoom gets room details by room name
 func (r *RoomRepository) GetByRoom(ctx context.Context, room string) (*models.RoomConference, error) {
 	var roomConf models.RoomConference
-	query := `SELECT id, status, room, roomType, recordId, autoRecord, recordType, encodingOptionsPreset,
-		chatEnabled, messageUnread, userAgent, dtmCreated, dtmStartRecord, dtmStopRecord, webSocketURL
-		FROM room_conference WHERE room = ? LIMIT 1`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT id, status, room, service, roomType, recordId, autoRecord, recordType, encodingOptionsPreset,
+		recordLayout, recordFileType, chatEnabled, messageUnread, userAgent, dtmCreated, dtmStartRecord, dtmStopRecord, webSocketURL
+		FROM room_conference WHERE room = ?` + scope + ` LIMIT 1`
 
-	err := r.db.GetContext(ctx, &roomConf, query, room)
+	err := r.db.GetContext(ctx, &roomConf, query, append([]interface{}{room}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -87,9 +100,10 @@ func (r *RoomRepository) GetByRoom(ctx context.Context, room string) (*models.Ro
 // GetByID gets room details by ID
 func (r *RoomRepository) GetByID(ctx context.Context, id uint) (*models.RoomConference, error) {
 	var roomConf models.RoomConference
-	query := `SELECT * FROM room_conference WHERE id = ? LIMIT 1`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT * FROM room_conference WHERE id = ?` + scope + ` LIMIT 1`
 
-	err := r.db.GetContext(ctx, &roomConf, query, id)
+	err := r.db.GetContext(ctx, &roomConf, query, append([]interface{}{id}, scopeArgs...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -103,9 +117,10 @@ func (r *RoomRepository) GetByID(ctx context.Context, id uint) (*models.RoomConf
 // GetByStatus gets all rooms by status
 func (r *RoomRepository) GetByStatus(ctx context.Context, status string) ([]models.RoomConference, error) {
 	var rooms []models.RoomConference
-	query := `SELECT * FROM room_conference WHERE status = ?`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT * FROM room_conference WHERE status = ?` + scope
 
-	err := r.db.SelectContext(ctx, &rooms, query, status)
+	err := r.db.SelectContext(ctx, &rooms, query, append([]interface{}{status}, scopeArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rooms by status: %w", err)
 	}
@@ -115,8 +130,9 @@ func (r *RoomRepository) GetByStatus(ctx context.Context, status string) ([]mode
 
 // UpdateStatus updates room status
 func (r *RoomRepository) UpdateStatus(ctx context.Context, room, status string) error {
-	query := `UPDATE room_conference SET status = ?, messageUnread = 0 WHERE room = ?`
-	_, err := r.db.ExecContext(ctx, query, status, room)
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE room_conference SET status = ?, messageUnread = 0 WHERE room = ?` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{status, room}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update room status: %w", err)
 	}
@@ -125,8 +141,9 @@ func (r *RoomRepository) UpdateStatus(ctx context.Context, room, status string)
 
 // CloseRoom closes a room by setting status to 'close'
 func (r *RoomRepository) CloseRoom(ctx context.Context, room string) (int64, error) {
-	query := `UPDATE room_conference SET status = 'close' WHERE room = ?`
-	result, err := r.db.ExecContext(ctx, query, room)
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE room_conference SET status = 'close' WHERE room = ?` + scope
+	result, err := r.db.ExecContext(ctx, query, append([]interface{}{room}, scopeArgs...)...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to close room: %w", err)
 	}
@@ -145,8 +162,9 @@ func (r *RoomRepository) CloseAllRooms(ctx context.Context) error {
 
 // UpdateExpired updates room expiration time
 func (r *RoomRepository) UpdateExpired(ctx context.Context, room, dtmExpired string) error {
-	query := `UPDATE room_conference SET dtmExpired = ? WHERE room = ?`
-	_, err := r.db.ExecContext(ctx, query, dtmExpired, room)
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE room_conference SET dtmExpired = ? WHERE room = ?` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{dtmExpired, room}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update room expiration: %w", err)
 	}
@@ -174,8 +192,9 @@ func (r *RoomRepository) UpdateRoomType(ctx context.Context, params UpdateRoomTy
 		userAgent = ?,
 		dtmUpdated = ?
 		WHERE room = ?`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
 
-	_, err := r.db.ExecContext(ctx, query,
+	args := []interface{}{
 		params.RoomType,
 		params.AutoRecord,
 		params.ChatEnabled,
@@ -183,7 +202,8 @@ func (r *RoomRepository) UpdateRoomType(ctx context.Context, params UpdateRoomTy
 		params.UserAgent,
 		params.DtmUpdated,
 		params.Room,
-	)
+	}
+	_, err := r.db.ExecContext(ctx, query+scope, append(args, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update room type: %w", err)
 	}
@@ -203,14 +223,31 @@ func (r *RoomRepository) UpdateTime(ctx context.Context, room string) error {
 
 // Delete deletes a room from the database
 func (r *RoomRepository) Delete(ctx context.Context, room string) error {
-	query := `DELETE FROM room_conference WHERE room = ?`
-	_, err := r.db.ExecContext(ctx, query, room)
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `DELETE FROM room_conference WHERE room = ?` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{room}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to delete room: %w", err)
 	}
 	return nil
 }
 
+// IsOnLegalHold checks if any case of a room is under legal hold. The hold of
+// its recordings is found through the room row, so a held room must be kept.
+func (r *RoomRepository) IsOnLegalHold(ctx context.Context, room string) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM case_data
+		JOIN room_conference ON case_data.roomId = room_conference.id
+		WHERE room_conference.room = ? AND case_data.legalHold = 1`
+
+	err := r.db.GetContext(ctx, &count, query, room)
+	if err != nil {
+		return false, fmt.Errorf("failed to check room legal hold: %w", err)
+	}
+
+	return count > 0, nil
+}
+
 // GetExpiredRooms gets rooms that are open but expired
 func (r *RoomRepository) GetExpiredRooms(ctx context.Context) ([]models.RoomConference, error) {
 	var rooms []models.RoomConference
@@ -228,10 +265,11 @@ func (r *RoomRepository) GetExpiredRooms(ctx context.Context) ([]models.RoomConf
 // CheckRoomExpired checks if a room is expired
 func (r *RoomRepository) CheckRoomExpired(ctx context.Context, room string) (bool, error) {
 	dtmCurrent := time.Now().Format("2006-01-02 15:04:05")
-	query := `SELECT COUNT(*) FROM room_conference WHERE dtmExpired < ? AND room = ? LIMIT 1`
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT COUNT(*) FROM room_conference WHERE dtmExpired < ? AND room = ?` + scope + ` LIMIT 1`
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, dtmCurrent, room)
+	err := r.db.GetContext(ctx, &count, query, append([]interface{}{dtmCurrent, room}, scopeArgs...)...)
 	if err != nil {
 		return false, fmt.Errorf("failed to check room expiration: %w", err)
 	}
@@ -243,19 +281,34 @@ func (r *RoomRepository) CheckRoomExpired(ctx context.Context, room string) (boo
 func (r *RoomRepository) UpdateRecordStatus(ctx context.Context, room string, status int) error {
 	var query string
 	if room == "all" {
-		query = `UPDATE room_conference SET recordStatus = 0, recordId = ''`
-		_, err := r.db.ExecContext(ctx, query)
+		scope, scopeArgs := whereTenant(ctx, serviceTenantCondition)
+		query = `UPDATE room_conference SET recordStatus = 0, recordId = ''` + scope
+		_, err := r.db.ExecContext(ctx, query, scopeArgs...)
 		return err
 	}
 
-	query = `UPDATE room_conference SET recordStatus = ? WHERE room = ?`
-	_, err := r.db.ExecContext(ctx, query, status, room)
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query = `UPDATE room_conference SET recordStatus = ? WHERE room = ?` + scope
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{status, room}, scopeArgs...)...)
 	if err != nil {
 		return fmt.Errorf("failed to update record status: %w", err)
 	}
 	return nil
 }
 
+// GetRecordingRooms gets the names of rooms marked as recording
+func (r *RoomRepository) GetRecordingRooms(ctx context.Context) ([]string, error) {
+	var rooms []string
+	query := `SELECT room FROM room_conference WHERE recordStatus = 1`
+
+	err := r.db.SelectContext(ctx, &rooms, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recording rooms: %w", err)
+	}
+
+	return rooms, nil
+}
+
 // UpdateRecordID updates room record ID
 func (r *RoomRepository) UpdateRecordID(ctx context.Context, room, recordID string) error {
 	query := `UPDATE room_conference SET recordId = ? WHERE room = ?`
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ServiceRepository handles services database operations
+type ServiceRepository struct {
+	db *sqlx.DB
+}
+
+// NewServiceRepository creates a new ServiceRepository
+func NewServiceRepository(db *sqlx.DB) *ServiceRepository {
+	return &ServiceRepository{db: db}
+}
+
+// GetByID gets service by ID
+func (r *ServiceRepository) GetByID(ctx context.Context, id int) (*models.Services, error) {
+	var svc models.Services
+	query := `SELECT * FROM services WHERE id = ?`
+
+	err := r.db.GetContext(ctx, &svc, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	return &svc, nil
+}
+
+// GetAll gets all services
+func (r *ServiceRepository) GetAll(ctx context.Context) ([]models.Services, error) {
+	var services []models.Services
+	query := `SELECT * FROM services ORDER BY id ASC`
+
+	err := r.db.SelectContext(ctx, &services, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %w", err)
+	}
+
+	return services, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SmsBlocklistRepository handles sms_blocklist database operations
+type SmsBlocklistRepository struct {
+	db *sqlx.DB
+}
+
+// NewSmsBlocklistRepository creates a new SmsBlocklistRepository
+func NewSmsBlocklistRepository(db *sqlx.DB) *SmsBlocklistRepository {
+	return &SmsBlocklistRepository{db: db}
+}
+
+// CreateSmsBlocklistParams holds parameters for blocking a mobile number
+type CreateSmsBlocklistParams struct {
+	Mobile    string
+	Service   int
+	Source    string
+	Reason    string
+	CreatedBy string
+}
+
+// Create blocks a mobile number, updating the reason if it is already blocked
+func (r *SmsBlocklistRepository) Create(ctx context.Context, params CreateSmsBlocklistParams) (int64, error) {
+	dtmCreated := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO sms_blocklist (mobile, service, source, reason, createdBy, dtmCreated)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), source = VALUES(source), reason = VALUES(reason), createdBy = VALUES(createdBy)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		params.Mobile,
+		tenantService(ctx, params.Service),
+		params.Source,
+		params.Reason,
+		params.CreatedBy,
+		dtmCreated,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sms blocklist: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetByID gets a blocklist entry by ID
+func (r *SmsBlocklistRepository) GetByID(ctx context.Context, id int) (*models.SmsBlocklist, error) {
+	var entry models.SmsBlocklist
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT * FROM sms_blocklist WHERE id = ?` + scope
+
+	err := r.db.GetContext(ctx, &entry, query, append([]interface{}{id}, scopeArgs...)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sms blocklist: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// List gets blocklist entries, optionally filtered by mobile
+func (r *SmsBlocklistRepository) List(ctx context.Context, mobile string, limit, offset int) ([]models.SmsBlocklist, error) {
+	var entries []models.SmsBlocklist
+	query := `SELECT * FROM sms_blocklist WHERE 1 = 1`
+	args := []interface{}{}
+
+	if mobile != "" {
+		query += ` AND mobile = ?`
+		args = append(args, mobile)
+	}
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query += scope
+	args = append(args, scopeArgs...)
+	query += ` ORDER BY id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	err := r.db.SelectContext(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sms blocklist: %w", err)
+	}
+
+	return entries, nil
+}
+
+// IsBlocked checks if a mobile number is blocked for a service or for all services.
+// It guards the sends of every tenant, so it is not tenant-scoped.
+func (r *SmsBlocklistRepository) IsBlocked(ctx context.Context, mobile string, service int) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM sms_blocklist WHERE mobile = ? AND service IN (0, ?)`
+
+	err := r.db.GetContext(ctx, &count, query, mobile, service)
+	if err != nil {
+		return false, fmt.Errorf("failed to check sms blocklist: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// Delete removes a blocklist entry
+func (r *SmsBlocklistRepository) Delete(ctx context.Context, id int) error {
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `DELETE FROM sms_blocklist WHERE id = ?` + scope
+
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{id}, scopeArgs...)...)
+	if err != nil {
+		return fmt.Errorf("failed to delete sms blocklist: %w", err)
+	}
+
+	return nil
+}
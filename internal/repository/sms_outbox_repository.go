@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SmsOutboxRepository handles sms_outbox database operations
+type SmsOutboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewSmsOutboxRepository creates a new SmsOutboxRepository
+func NewSmsOutboxRepository(db *sqlx.DB) *SmsOutboxRepository {
+	return &SmsOutboxRepository{db: db}
+}
+
+// CreateSmsOutboxParams holds parameters for queueing an SMS
+type CreateSmsOutboxParams struct {
+	Service     int
+	LinkID      string
+	Room        string
+	Mobile      string
+	Sender      string
+	Provider    string
+	MessageType string
+	Message     string
+	MaxAttempts int
+	CreatedBy   string
+}
+
+// SmsOutboxFilter holds filters for listing queued SMS
+type SmsOutboxFilter struct {
+	Room   string
+	LinkID string
+	Mobile string
+	Status string
+}
+
+// Create queues a new SMS
+func (r *SmsOutboxRepository) Create(ctx context.Context, params CreateSmsOutboxParams) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO sms_outbox
+		(service, linkID, room, mobile, sender, provider, messageType, message, status, attempts, maxAttempts, createdBy, dtmNextAttempt, dtmCreated, dtmUpdated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 'pending', 0, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		tenantService(ctx, params.Service),
+		params.LinkID,
+		params.Room,
+		params.Mobile,
+		params.Sender,
+		params.Provider,
+		params.MessageType,
+		params.Message,
+		params.MaxAttempts,
+		params.CreatedBy,
+		now,
+		now,
+		now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sms outbox: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetByID gets a queued SMS by ID
+func (r *SmsOutboxRepository) GetByID(ctx context.Context, id int) (*models.SmsOutbox, error) {
+	var msg models.SmsOutbox
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT * FROM sms_outbox WHERE id = ?` + scope
+
+	err := r.db.GetContext(ctx, &msg, query, append([]interface{}{id}, scopeArgs...)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sms outbox: %w", err)
+	}
+
+	return &msg, nil
+}
+
+// List gets queued SMS matching the filter
+func (r *SmsOutboxRepository) List(ctx context.Context, filter SmsOutboxFilter, limit, offset int) ([]models.SmsOutbox, error) {
+	var messages []models.SmsOutbox
+	query := `SELECT * FROM sms_outbox WHERE 1 = 1`
+	args := []interface{}{}
+
+	if filter.Room != "" {
+		query += ` AND room = ?`
+		args = append(args, filter.Room)
+	}
+	if filter.LinkID != "" {
+		query += ` AND linkID = ?`
+		args = append(args, filter.LinkID)
+	}
+	if filter.Mobile != "" {
+		query += ` AND mobile = ?`
+		args = append(args, filter.Mobile)
+	}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query += scope
+	args = append(args, scopeArgs...)
+	query += ` ORDER BY id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	err := r.db.SelectContext(ctx, &messages, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sms outbox: %w", err)
+	}
+
+	return messages, nil
+}
+
+// GetByProviderMessageID gets a sent SMS by the provider message ID
+func (r *SmsOutboxRepository) GetByProviderMessageID(ctx context.Context, providerMessageID string) (*models.SmsOutbox, error) {
+	var msg models.SmsOutbox
+	query := `SELECT * FROM sms_outbox WHERE providerMessageId = ? ORDER BY id DESC LIMIT 1`
+
+	err := r.db.GetContext(ctx, &msg, query, providerMessageID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sms outbox by provider message ID: %w", err)
+	}
+
+	return &msg, nil
+}
+
+// GetDueIDs gets IDs of pending SMS whose next attempt is due
+func (r *SmsOutboxRepository) GetDueIDs(ctx context.Context, limit int) ([]int, error) {
+	var ids []int
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `SELECT id FROM sms_outbox WHERE status = 'pending' AND dtmNextAttempt <= ? ORDER BY dtmNextAttempt ASC LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &ids, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due sms outbox: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Claim marks a pending SMS as sending and counts the attempt.
+// Returns false when another worker already claimed it.
+func (r *SmsOutboxRepository) Claim(ctx context.Context, id int) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE sms_outbox SET status = 'sending', attempts = attempts + 1, dtmUpdated = ? WHERE id = ? AND status = 'pending'`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim sms outbox: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// MarkSent marks an SMS as accepted by the provider
+func (r *SmsOutboxRepository) MarkSent(ctx context.Context, id int, providerMessageID string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE sms_outbox SET status = 'sent', providerMessageId = ?, lastError = NULL, dtmSent = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, providerMessageID, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark sms outbox sent: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed attempt and sets the next status and attempt time
+func (r *SmsOutboxRepository) MarkFailed(ctx context.Context, id int, status, lastError string, nextAttempt time.Time) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE sms_outbox SET status = ?, lastError = ?, dtmNextAttempt = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, status, lastError, nextAttempt.Format("2006-01-02 15:04:05"), now, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark sms outbox failed: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDeliveryStatus records a carrier delivery report
+func (r *SmsOutboxRepository) UpdateDeliveryStatus(ctx context.Context, id int, status, deliveryError string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE sms_outbox SET deliveryStatus = ?, deliveryError = ?, dtmDelivered = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, status, deliveryError, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update sms outbox delivery status: %w", err)
+	}
+
+	return nil
+}
+
+// Requeue puts a pending, dead or cancelled SMS back in the queue with a fresh attempt count
+func (r *SmsOutboxRepository) Requeue(ctx context.Context, id int) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE sms_outbox SET status = 'pending', attempts = 0, dtmNextAttempt = ?, dtmUpdated = ?
+		WHERE id = ? AND status IN ('pending', 'dead', 'cancelled')` + scope
+
+	result, err := r.db.ExecContext(ctx, query, append([]interface{}{now, now, id}, scopeArgs...)...)
+	if err != nil {
+		return false, fmt.Errorf("failed to requeue sms outbox: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Cancel cancels a pending SMS
+func (r *SmsOutboxRepository) Cancel(ctx context.Context, id int) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE sms_outbox SET status = 'cancelled', dtmUpdated = ? WHERE id = ? AND status = 'pending'` + scope
+
+	result, err := r.db.ExecContext(ctx, query, append([]interface{}{now, id}, scopeArgs...)...)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel sms outbox: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// ReleaseStale returns SMS stuck in sending since before the given time to the queue
+func (r *SmsOutboxRepository) ReleaseStale(ctx context.Context, before time.Time) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE sms_outbox SET status = 'pending', dtmNextAttempt = ?, dtmUpdated = ? WHERE status = 'sending' AND dtmUpdated < ?`
+
+	result, err := r.db.ExecContext(ctx, query, now, now, before.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale sms outbox: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SmsTemplateRepository handles sms_template database operations
+type SmsTemplateRepository struct {
+	db *sqlx.DB
+}
+
+// NewSmsTemplateRepository creates a new SmsTemplateRepository
+func NewSmsTemplateRepository(db *sqlx.DB) *SmsTemplateRepository {
+	return &SmsTemplateRepository{db: db}
+}
+
+// SmsTemplateParams holds parameters for creating or updating a template
+type SmsTemplateParams struct {
+	Service     int
+	MessageType string
+	Locale      string
+	Body        string
+	Enabled     int
+	UpdatedBy   string
+}
+
+// Create creates a new template
+func (r *SmsTemplateRepository) Create(ctx context.Context, params SmsTemplateParams) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO sms_template (service, messageType, locale, body, enabled, updatedBy, dtmCreated, dtmUpdated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		tenantService(ctx, params.Service),
+		params.MessageType,
+		params.Locale,
+		params.Body,
+		params.Enabled,
+		params.UpdatedBy,
+		now,
+		now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sms template: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// Update updates a template
+func (r *SmsTemplateRepository) Update(ctx context.Context, id int, params SmsTemplateParams) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `UPDATE sms_template SET service = ?, messageType = ?, locale = ?, body = ?, enabled = ?, updatedBy = ?, dtmUpdated = ? WHERE id = ?` + scope
+
+	args := []interface{}{
+		tenantService(ctx, params.Service),
+		params.MessageType,
+		params.Locale,
+		params.Body,
+		params.Enabled,
+		params.UpdatedBy,
+		now,
+		id,
+	}
+	_, err := r.db.ExecContext(ctx, query, append(args, scopeArgs...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update sms template: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a template
+func (r *SmsTemplateRepository) Delete(ctx context.Context, id int) error {
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `DELETE FROM sms_template WHERE id = ?` + scope
+
+	_, err := r.db.ExecContext(ctx, query, append([]interface{}{id}, scopeArgs...)...)
+	if err != nil {
+		return fmt.Errorf("failed to delete sms template: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID gets template by ID
+func (r *SmsTemplateRepository) GetByID(ctx context.Context, id int) (*models.SmsTemplate, error) {
+	var tpl models.SmsTemplate
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query := `SELECT * FROM sms_template WHERE id = ?` + scope
+
+	err := r.db.GetContext(ctx, &tpl, query, append([]interface{}{id}, scopeArgs...)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sms template: %w", err)
+	}
+
+	return &tpl, nil
+}
+
+// List gets templates, optionally filtered by service and message type
+func (r *SmsTemplateRepository) List(ctx context.Context, service int, messageType string) ([]models.SmsTemplate, error) {
+	var templates []models.SmsTemplate
+	query := `SELECT * FROM sms_template WHERE 1 = 1`
+	args := []interface{}{}
+
+	if service >= 0 {
+		query += ` AND service = ?`
+		args = append(args, service)
+	}
+	if messageType != "" {
+		query += ` AND messageType = ?`
+		args = append(args, messageType)
+	}
+	scope, scopeArgs := andTenant(ctx, serviceTenantCondition)
+	query += scope
+	args = append(args, scopeArgs...)
+	query += ` ORDER BY service ASC, messageType ASC, locale ASC`
+
+	err := r.db.SelectContext(ctx, &templates, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sms templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// Find gets the best enabled template for a service, message type and locale.
+// A service template wins over the default (service 0), and the requested
+// locale wins over the fallback locale. Sends of every tenant use the
+// defaults, so Find is not tenant-scoped.
+func (r *SmsTemplateRepository) Find(ctx context.Context, service int, messageType, locale, fallbackLocale string) (*models.SmsTemplate, error) {
+	var tpl models.SmsTemplate
+	query := `SELECT * FROM sms_template
+		WHERE service IN (?, 0) AND messageType = ? AND locale IN (?, ?) AND enabled = 1
+		ORDER BY service DESC, locale = ? DESC
+		LIMIT 1`
+
+	err := r.db.GetContext(ctx, &tpl, query, service, messageType, locale, fallbackLocale, locale)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find sms template: %w", err)
+	}
+
+	return &tpl, nil
+}
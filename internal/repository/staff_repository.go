@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// StaffRepository handles staff_account and staff_refresh_token database operations
+type StaffRepository struct {
+	db *sqlx.DB
+}
+
+// NewStaffRepository creates a new StaffRepository
+func NewStaffRepository(db *sqlx.DB) *StaffRepository {
+	return &StaffRepository{db: db}
+}
+
+// CreateStaffParams holds parameters for creating a staff account
+type CreateStaffParams struct {
+	UserName     string
+	PasswordHash string
+	DisplayName  string
+	Role         string
+	Service      int
+	Organization string
+	CreatedBy    string
+}
+
+// CreateRefreshTokenParams holds parameters for storing a refresh token
+type CreateRefreshTokenParams struct {
+	StaffID    uint
+	TokenHash  string
+	FamilyID   string
+	UserAgent  string
+	IP         string
+	DtmExpired string
+}
+
+// Create creates a staff account
+func (r *StaffRepository) Create(ctx context.Context, params CreateStaffParams) (int64, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO staff_account
+		(userName, passwordHash, displayName, role, service, organization, enabled, createdBy, dtmPasswordChanged, dtmCreated, dtmUpdated)
+		VALUES (?, ?, ?, ?, ?, ?, 1, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		params.UserName,
+		params.PasswordHash,
+		params.DisplayName,
+		params.Role,
+		params.Service,
+		params.Organization,
+		params.CreatedBy,
+		now,
+		now,
+		now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create staff account: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetByID gets a staff account by ID
+func (r *StaffRepository) GetByID(ctx context.Context, id uint) (*models.StaffAccount, error) {
+	var staff models.StaffAccount
+	query := `SELECT * FROM staff_account WHERE id = ?`
+
+	err := r.db.GetContext(ctx, &staff, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get staff account: %w", err)
+	}
+
+	return &staff, nil
+}
+
+// GetByUserName gets a staff account by user name
+func (r *StaffRepository) GetByUserName(ctx context.Context, userName string) (*models.StaffAccount, error) {
+	var staff models.StaffAccount
+	query := `SELECT * FROM staff_account WHERE userName = ?`
+
+	err := r.db.GetContext(ctx, &staff, query, userName)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get staff account: %w", err)
+	}
+
+	return &staff, nil
+}
+
+// List gets staff accounts, optionally filtered by service. A negative service lists all.
+func (r *StaffRepository) List(ctx context.Context, service int) ([]models.StaffAccount, error) {
+	var staff []models.StaffAccount
+	query := `SELECT * FROM staff_account`
+	args := []interface{}{}
+
+	if service >= 0 {
+		query += ` WHERE service = ?`
+		args = append(args, service)
+	}
+	query += ` ORDER BY userName`
+
+	err := r.db.SelectContext(ctx, &staff, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staff accounts: %w", err)
+	}
+
+	return staff, nil
+}
+
+// UpdatePassword updates the password hash of a staff account and clears any lockout
+func (r *StaffRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE staff_account SET passwordHash = ?, failedAttempts = 0, dtmLocked = NULL,
+		dtmPasswordChanged = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, passwordHash, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update staff password: %w", err)
+	}
+	return nil
+}
+
+// SetEnabled enables or disables a staff account
+func (r *StaffRepository) SetEnabled(ctx context.Context, id uint, enabled int) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE staff_account SET enabled = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, enabled, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update staff enabled: %w", err)
+	}
+	return nil
+}
+
+// SetRole changes the role of a staff account
+func (r *StaffRepository) SetRole(ctx context.Context, id uint, role string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE staff_account SET role = ?, dtmUpdated = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, role, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update staff role: %w", err)
+	}
+	return nil
+}
+
+// RecordFailedLogin counts a failed login and locks the account until lockUntil
+// once maxAttempts consecutive failures are reached
+func (r *StaffRepository) RecordFailedLogin(ctx context.Context, id uint, maxAttempts int, lockUntil string) error {
+	query := `UPDATE staff_account SET
+		dtmLocked = IF(failedAttempts + 1 >= ?, ?, dtmLocked),
+		failedAttempts = IF(failedAttempts + 1 >= ?, 0, failedAttempts + 1)
+		WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, maxAttempts, lockUntil, maxAttempts, id)
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+	return nil
+}
+
+// RecordLogin resets the failure count and stores the login time
+func (r *StaffRepository) RecordLogin(ctx context.Context, id uint) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE staff_account SET failedAttempts = 0, dtmLocked = NULL, dtmLastLogin = ? WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
+// Unlock clears a lockout
+func (r *StaffRepository) Unlock(ctx context.Context, id uint) error {
+	query := `UPDATE staff_account SET failedAttempts = 0, dtmLocked = NULL WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to unlock staff account: %w", err)
+	}
+	return nil
+}
+
+// CreateRefreshToken stores a hashed refresh token
+func (r *StaffRepository) CreateRefreshToken(ctx context.Context, params CreateRefreshTokenParams) (int64, error) {
+	dtmCreated := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO staff_refresh_token (staffId, tokenHash, familyId, userAgent, ip, dtmCreated, dtmExpired)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		params.StaffID,
+		params.TokenHash,
+		params.FamilyID,
+		params.UserAgent,
+		params.IP,
+		dtmCreated,
+		params.DtmExpired,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetRefreshTokenByHash gets a refresh token by the hash of its value
+func (r *StaffRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.StaffRefreshToken, error) {
+	var token models.StaffRefreshToken
+	query := `SELECT * FROM staff_refresh_token WHERE tokenHash = ?`
+
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// ReplaceRefreshToken revokes a refresh token in favour of its successor.
+// Returns false when the token was already revoked, which means it was reused.
+func (r *StaffRepository) ReplaceRefreshToken(ctx context.Context, id uint, replacedBy int64) (bool, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE staff_refresh_token SET dtmRevoked = ?, replacedBy = ? WHERE id = ? AND dtmRevoked IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, now, replacedBy, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to replace refresh token: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// RevokeRefreshTokenFamily revokes every active refresh token descended from the same login
+func (r *StaffRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE staff_refresh_token SET dtmRevoked = ? WHERE familyId = ? AND dtmRevoked IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, now, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshTokens revokes every active refresh token of a staff account
+func (r *StaffRepository) RevokeRefreshTokens(ctx context.Context, staffID uint) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE staff_refresh_token SET dtmRevoked = ? WHERE staffId = ? AND dtmRevoked IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, now, staffID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
@@ -56,6 +56,7 @@ type CaseStats struct {
 // GetSummary gets summary statistics
 func (r *StatsRepository) GetSummary(ctx context.Context, service int) (*StatsSummary, error) {
 	var summary StatsSummary
+	service, filter := serviceFilter(ctx, service)
 
 	// Get room counts
 	roomQuery := `SELECT
@@ -63,11 +64,9 @@ func (r *StatsRepository) GetSummary(ctx context.Context, service int) (*StatsSu
 		SUM(CASE WHEN status = 'open' THEN 1 ELSE 0 END) as openRooms,
 		SUM(CASE WHEN status = 'close' THEN 1 ELSE 0 END) as closedRooms
 		FROM room_conference`
-	if service > 0 {
-		roomQuery += fmt.Sprintf(` WHERE service = %d`, service)
-	}
+	roomWhere, args := statsWhere(serviceTenantCondition, service, filter)
 
-	err := r.db.GetContext(ctx, &summary, roomQuery)
+	err := r.db.GetContext(ctx, &summary, roomQuery+roomWhere, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get room stats: %w", err)
 	}
@@ -77,12 +76,13 @@ func (r *StatsRepository) GetSummary(ctx context.Context, service int) (*StatsSu
 		COUNT(*) as totalUsers,
 		SUM(CASE WHEN status = 'connection' THEN 1 ELSE 0 END) as activeUsers
 		FROM room_user`
+	userWhere, args := statsWhere(roomUserTenantCondition, service, filter)
 
 	var userStats struct {
 		TotalUsers  int `db:"totalUsers"`
 		ActiveUsers int `db:"activeUsers"`
 	}
-	err = r.db.GetContext(ctx, &userStats, userQuery)
+	err = r.db.GetContext(ctx, &userStats, userQuery+userWhere, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
@@ -91,24 +91,24 @@ func (r *StatsRepository) GetSummary(ctx context.Context, service int) (*StatsSu
 
 	// Get link count
 	linkQuery := `SELECT COUNT(*) FROM link_connect`
-	err = r.db.GetContext(ctx, &summary.TotalLinks, linkQuery)
+	linkWhere, args := statsWhere(serviceTenantCondition, service, filter)
+	err = r.db.GetContext(ctx, &summary.TotalLinks, linkQuery+linkWhere, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get link stats: %w", err)
 	}
 
 	// Get record count
 	recordQuery := `SELECT COUNT(*) FROM record_media`
-	err = r.db.GetContext(ctx, &summary.TotalRecords, recordQuery)
+	recordWhere, args := statsWhere(recordTenantCondition, service, filter)
+	err = r.db.GetContext(ctx, &summary.TotalRecords, recordQuery+recordWhere, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get record stats: %w", err)
 	}
 
 	// Get case count
 	caseQuery := `SELECT COUNT(*) FROM case_data`
-	if service > 0 {
-		caseQuery += fmt.Sprintf(` WHERE service = %d`, service)
-	}
-	err = r.db.GetContext(ctx, &summary.TotalCases, caseQuery)
+	caseWhere, args := statsWhere(serviceTenantCondition, service, filter)
+	err = r.db.GetContext(ctx, &summary.TotalCases, caseQuery+caseWhere, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get case stats: %w", err)
 	}
@@ -119,6 +119,8 @@ func (r *StatsRepository) GetSummary(ctx context.Context, service int) (*StatsSu
 // GetDeviceStats gets device statistics
 func (r *StatsRepository) GetDeviceStats(ctx context.Context, service int) ([]DeviceStats, error) {
 	var stats []DeviceStats
+	service, filter := serviceFilter(ctx, service)
+	where, args := statsWhere(serviceTenantCondition, service, filter)
 	query := `SELECT
 		CASE
 			WHEN userAgent LIKE '%iPhone%' OR userAgent LIKE '%iPad%' THEN 'iOS'
@@ -129,11 +131,11 @@ func (r *StatsRepository) GetDeviceStats(ctx context.Context, service int) ([]De
 			ELSE 'Other'
 		END as device,
 		COUNT(*) as count
-		FROM link_connect
+		FROM link_connect` + where + `
 		GROUP BY device
 		ORDER BY count DESC`
 
-	err := r.db.SelectContext(ctx, &stats, query)
+	err := r.db.SelectContext(ctx, &stats, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get device stats: %w", err)
 	}
@@ -146,11 +148,16 @@ func (r *StatsRepository) GetTypeStats(ctx context.Context, service int) ([]Type
 	var stats []TypeStats
 	query := `SELECT linkType as type, COUNT(*) as count
 		FROM link_connect
-		WHERE linkType IS NOT NULL AND linkType != ''
-		GROUP BY linkType
-		ORDER BY count DESC`
+		WHERE linkType IS NOT NULL AND linkType != ''`
+	args := []interface{}{}
 
-	err := r.db.SelectContext(ctx, &stats, query)
+	if service, filter := serviceFilter(ctx, service); filter {
+		query += ` AND ` + serviceTenantCondition
+		args = append(args, service)
+	}
+	query += ` GROUP BY linkType ORDER BY count DESC`
+
+	err := r.db.SelectContext(ctx, &stats, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get type stats: %w", err)
 	}
@@ -163,11 +170,16 @@ func (r *StatsRepository) GetUserStats(ctx context.Context, service int) ([]User
 	var stats []UserStats
 	query := `SELECT userType, COUNT(*) as count
 		FROM room_user
-		WHERE userType IS NOT NULL AND userType != ''
-		GROUP BY userType
-		ORDER BY count DESC`
+		WHERE userType IS NOT NULL AND userType != ''`
+	args := []interface{}{}
 
-	err := r.db.SelectContext(ctx, &stats, query)
+	if service, filter := serviceFilter(ctx, service); filter {
+		query += ` AND ` + roomUserTenantCondition
+		args = append(args, service)
+	}
+	query += ` GROUP BY userType ORDER BY count DESC`
+
+	err := r.db.SelectContext(ctx, &stats, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
@@ -178,14 +190,12 @@ func (r *StatsRepository) GetUserStats(ctx context.Context, service int) ([]User
 // GetCaseStats gets case status statistics
 func (r *StatsRepository) GetCaseStats(ctx context.Context, service int) ([]CaseStats, error) {
 	var stats []CaseStats
+	service, filter := serviceFilter(ctx, service)
+	where, args := statsWhere(serviceTenantCondition, service, filter)
 	query := `SELECT status, COUNT(*) as count
-		FROM case_data`
-	if service > 0 {
-		query += fmt.Sprintf(` WHERE service = %d`, service)
-	}
-	query += ` GROUP BY status ORDER BY count DESC`
+		FROM case_data` + where + ` GROUP BY status ORDER BY count DESC`
 
-	err := r.db.SelectContext(ctx, &stats, query)
+	err := r.db.SelectContext(ctx, &stats, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get case stats: %w", err)
 	}
@@ -202,7 +212,7 @@ func (r *StatsRepository) GetDailyStats(ctx context.Context, startDate, endDate
 		WHERE dtmCreated BETWEEN ? AND ?`
 	args := []interface{}{startDate, endDate}
 
-	if service > 0 {
+	if service, filter := serviceFilter(ctx, service); filter {
 		query += ` AND service = ?`
 		args = append(args, service)
 	}
@@ -236,7 +246,7 @@ func (r *StatsRepository) GetMonthlyStats(ctx context.Context, year int, service
 		WHERE YEAR(dtmCreated) = ?`
 	args := []interface{}{year}
 
-	if service > 0 {
+	if service, filter := serviceFilter(ctx, service); filter {
 		query += ` AND service = ?`
 		args = append(args, service)
 	}
@@ -260,3 +270,11 @@ func (r *StatsRepository) GetMonthlyStats(ctx context.Context, year int, service
 
 	return results, nil
 }
+
+// statsWhere returns " WHERE <condition>" and the service argument when filtering
+func statsWhere(condition string, service int, filter bool) (string, []interface{}) {
+	if !filter {
+		return "", nil
+	}
+	return " WHERE " + condition, []interface{}{service}
+}
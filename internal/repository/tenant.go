@@ -0,0 +1,99 @@
+package repository
+
+import "context"
+
+// Tenant restricts repository queries to one service and, for cases, to one
+// organization when set. Requests without a tenant, such as admin calls and
+// background jobs, are not restricted. A tenant without a service matches no
+// rows, so callers bound to no service see nothing rather than everything.
+type Tenant struct {
+	Service      int
+	Organization string
+}
+
+type tenantContextKey struct{}
+
+// TenantKey is the context key holding the Tenant of a request. Fiber handlers
+// pass c.Context(), so middleware can set it with c.Locals(TenantKey, tenant).
+var TenantKey = tenantContextKey{}
+
+// Tenant conditions for each scoped table. The single placeholder is the service ID.
+const (
+	serviceTenantCondition      = "service = ?"
+	recordTenantCondition       = "record_media.room IN (SELECT room FROM room_conference WHERE service = ?)"
+	recordQueueTenantCondition  = "record_queue.room IN (SELECT room FROM room_conference WHERE service = ?)"
+	encodeJobTenantCondition    = "encode_job.room IN (SELECT room FROM room_conference WHERE service = ?)"
+	roomUserTenantCondition     = "room_user.room IN (SELECT room FROM room_conference WHERE service = ?)"
+	notificationTenantCondition = "notification.caseId IN (SELECT caseId FROM case_data WHERE service = ?)"
+)
+
+// WithTenant returns a copy of ctx scoped to the tenant
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, TenantKey, tenant)
+}
+
+// TenantFromContext returns the tenant of ctx, or false when ctx is not scoped
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(TenantKey).(Tenant)
+	return tenant, ok
+}
+
+// andTenant returns " AND <condition>" and its arguments when ctx is scoped
+func andTenant(ctx context.Context, condition string) (string, []interface{}) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	if tenant.Service == 0 {
+		return " AND 1 = 0", nil
+	}
+	return " AND " + condition, []interface{}{tenant.Service}
+}
+
+// whereTenant returns " WHERE <condition>" and its arguments when ctx is scoped
+func whereTenant(ctx context.Context, condition string) (string, []interface{}) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	if tenant.Service == 0 {
+		return " WHERE 1 = 0", nil
+	}
+	return " WHERE " + condition, []interface{}{tenant.Service}
+}
+
+// andCaseTenant scopes case_data by service and, when the tenant has one, by organization
+func andCaseTenant(ctx context.Context) (string, []interface{}) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	if tenant.Service == 0 {
+		return " AND 1 = 0", nil
+	}
+	if tenant.Organization != "" {
+		return " AND case_data.service = ? AND case_data.organization = ?", []interface{}{tenant.Service, tenant.Organization}
+	}
+	return " AND case_data.service = ?", []interface{}{tenant.Service}
+}
+
+// tenantService returns the service a scoped request may write to, or requested when unscoped
+func tenantService(ctx context.Context, requested int) int {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return tenant.Service
+	}
+	return requested
+}
+
+// serviceFilter returns the service to filter statistics by and whether to filter at all.
+// Unscoped requests filter only when a service was requested.
+func serviceFilter(ctx context.Context, requested int) (int, bool) {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		if tenant.Service == 0 {
+			// No service has ID -1, so a tenant without a service counts nothing
+			return -1, true
+		}
+		return tenant.Service, true
+	}
+	return requested, requested > 0
+}
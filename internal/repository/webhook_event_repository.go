@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"api-gateway-go/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WebhookEventRepository handles webhook_event database operations
+type WebhookEventRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookEventRepository creates a new WebhookEventRepository
+func NewWebhookEventRepository(db *sqlx.DB) *WebhookEventRepository {
+	return &WebhookEventRepository{db: db}
+}
+
+// CreateWebhookEventParams holds parameters for storing a webhook event
+type CreateWebhookEventParams struct {
+	EventID   string
+	Event     string
+	Room      string
+	CreatedAt int64
+	Payload   string
+}
+
+// Create stores a webhook event. Returns false when an event with the same
+// event ID was already stored; any other error fails the insert.
+func (r *WebhookEventRepository) Create(ctx context.Context, params CreateWebhookEventParams) (bool, error) {
+	dtmReceived := time.Now().Format("2006-01-02 15:04:05")
+	query := `INSERT INTO webhook_event
+		(eventId, event, room, createdAt, payload, status, dtmReceived)
+		VALUES (?, ?, ?, ?, ?, 'pending', ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		params.EventID,
+		params.Event,
+		params.Room,
+		params.CreatedAt,
+		params.Payload,
+		dtmReceived,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err, "uq_webhook_event_eventId") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create webhook event: %w", err)
+	}
+
+	return true, nil
+}
+
+// ClaimNext claims the oldest unfinished event of a room for owner. Returns nil
+// when the room has no pending event, or its oldest event is being processed,
+// waiting for a retry or failed, so the events of a room never run concurrently
+// or out of order, even across instances.
+func (r *WebhookEventRepository) ClaimNext(ctx context.Context, room, owner string) (*models.WebhookEvent, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook event: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Locking the head of the room's queue makes concurrent claims wait for each other
+	var event models.WebhookEvent
+	query := `SELECT * FROM webhook_event WHERE room = ? AND status IN ('pending', 'processing', 'failed')
+		ORDER BY createdAt ASC, id ASC LIMIT 1 FOR UPDATE`
+
+	err = tx.GetContext(ctx, &event, query, room)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim webhook event: %w", err)
+	}
+	now := time.Now()
+	if event.Status != "pending" || (event.DtmNextAttempt.Valid && event.DtmNextAttempt.Time.After(now)) {
+		return nil, nil
+	}
+
+	query = `UPDATE webhook_event SET status = 'processing', owner = ?, dtmClaimed = ? WHERE id = ? AND status = 'pending'`
+	if _, err := tx.ExecContext(ctx, query, owner, now.Format("2006-01-02 15:04:05"), event.ID); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook event: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook event: %w", err)
+	}
+
+	event.Status = "processing"
+	event.Owner = sql.NullString{String: owner, Valid: true}
+	event.DtmClaimed = sql.NullTime{Time: now, Valid: true}
+	return &event, nil
+}
+
+// GetPendingRooms gets the rooms that have pending events due to run
+func (r *WebhookEventRepository) GetPendingRooms(ctx context.Context, limit int) ([]string, error) {
+	var rooms []string
+	query := `SELECT DISTINCT room FROM webhook_event WHERE status = 'pending'
+		AND (dtmNextAttempt IS NULL OR dtmNextAttempt <= ?) LIMIT ?`
+
+	err := r.db.SelectContext(ctx, &rooms, query, time.Now().Format("2006-01-02 15:04:05"), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rooms with pending webhook events: %w", err)
+	}
+
+	return rooms, nil
+}
+
+// GetByRoom gets every stored event of a room in the order they happened
+func (r *WebhookEventRepository) GetByRoom(ctx context.Context, room string) ([]models.WebhookEvent, error) {
+	var events []models.WebhookEvent
+	query := `SELECT * FROM webhook_event WHERE room = ? ORDER BY createdAt ASC, id ASC`
+
+	err := r.db.SelectContext(ctx, &events, query, room)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook events by room: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkDone marks an event claimed by owner as processed
+func (r *WebhookEventRepository) MarkDone(ctx context.Context, id uint, owner string) error {
+	dtmProcessed := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE webhook_event SET status = 'done', attempts = attempts + 1, lastError = NULL,
+		dtmProcessed = ? WHERE id = ? AND status = 'processing' AND owner = ?`
+
+	_, err := r.db.ExecContext(ctx, query, dtmProcessed, id, owner)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook event done: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt of an event claimed by owner and sets its
+// next status: pending to be retried at nextAttempt, or failed. Either way the
+// event keeps holding up the later events of its room.
+func (r *WebhookEventRepository) MarkFailed(ctx context.Context, id uint, owner, status, lastError string, nextAttempt time.Time) error {
+	dtmProcessed := time.Now().Format("2006-01-02 15:04:05")
+	query := `UPDATE webhook_event SET status = ?, attempts = attempts + 1, lastError = ?,
+		dtmNextAttempt = ?, dtmProcessed = ? WHERE id = ? AND status = 'processing' AND owner = ?`
+
+	_, err := r.db.ExecContext(ctx, query, status, lastError, nextAttempt.Format("2006-01-02 15:04:05"), dtmProcessed, id, owner)
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook event failed: %w", err)
+	}
+	return nil
+}
+
+// ResetRoom marks the failed and pending events of a room as pending again,
+// with a fresh attempt count and no retry delay, so they are replayed. With all
+// set, events already done are replayed too. Events being processed are left
+// to their owner.
+func (r *WebhookEventRepository) ResetRoom(ctx context.Context, room string, all bool) (int64, error) {
+	query := `UPDATE webhook_event SET status = 'pending', attempts = 0, dtmNextAttempt = NULL
+		WHERE room = ? AND status IN ('failed', 'pending')`
+	if all {
+		query = `UPDATE webhook_event SET status = 'pending', attempts = 0, dtmNextAttempt = NULL
+		WHERE room = ? AND status <> 'processing'`
+	}
+
+	result, err := r.db.ExecContext(ctx, query, room)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset webhook events: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ReleaseStale returns events claimed before the given time to pending, e.g.
+// after the instance processing them stopped
+func (r *WebhookEventRepository) ReleaseStale(ctx context.Context, before time.Time) (int64, error) {
+	query := `UPDATE webhook_event SET status = 'pending', owner = NULL, dtmClaimed = NULL
+		WHERE status = 'processing' AND dtmClaimed < ?`
+
+	result, err := r.db.ExecContext(ctx, query, before.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale webhook events: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,281 @@
+package router
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"api-gateway-go/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoutePermissions is the route-permission matrix, keyed by "METHOD /path" as
+// registered in SetupRoutes. Every route must be listed; routes missing from
+// the matrix are denied at request time and fail CheckRoutePermissions.
+var RoutePermissions = map[string]service.Permission{
+	// System
+	"GET /":                 service.PermPublic,
+	"GET /health":           service.PermPublic,
+	"GET /status":           service.PermPublic,
+	"GET /status/reconcile": service.PermMetricsRead,
+	"GET /service":          service.PermPublic,
+	"GET /namespace":        service.PermPublic,
+	"POST /log":             service.PermPublic,
+	"GET /metrics":          service.PermMetricsRead,
+
+	// Auth
+	"GET /.well-known/jwks.json": service.PermPublic,
+	"GET /auth/create":           service.PermTokenCreate,
+	"GET /auth/verify":           service.PermPublic,
+	"POST /auth/verifyuser":      service.PermPublic,
+	"POST /auth/refresh":         service.PermPublic,
+	"POST /auth/logout":          service.PermAuthenticated,
+
+	// Staff
+	"GET /staff/":               service.PermStaffManage,
+	"POST /staff/":              service.PermStaffManage,
+	"POST /staff/:id/unlock":    service.PermStaffManage,
+	"POST /staff/:id/revokeall": service.PermStaffManage,
+	"PUT /staff/:id/password":   service.PermStaffManage,
+	"PUT /staff/:id/role":       service.PermStaffManage,
+	"PUT /staff/:id/enabled":    service.PermStaffManage,
+
+	// API keys
+	"GET /apikey/":            service.PermAPIKeyManage,
+	"POST /apikey/":           service.PermAPIKeyManage,
+	"POST /apikey/:id/rotate": service.PermAPIKeyManage,
+	"PUT /apikey/:id":         service.PermAPIKeyManage,
+	"PUT /apikey/:id/enabled": service.PermAPIKeyManage,
+	"DELETE /apikey/:id":      service.PermAPIKeyManage,
+
+	// Room
+	"GET /room/detail":       service.PermPublic,
+	"GET /room/listrooms":    service.PermRoomRead,
+	"GET /room/checkexpired": service.PermPublic,
+	"GET /room/verifytoken":  service.PermPublic,
+	"GET /room/picture":      service.PermPublic,
+	"POST /room/create":      service.PermRoomManage,
+	"POST /room/updateuser":  service.PermRoomManage,
+	"POST /room/deleteroom":  service.PermRoomManage,
+	"PUT /room/updatetype":   service.PermRoomManage,
+	"PUT /room/updatestatus": service.PermRoomManage,
+	"PUT /room/close":        service.PermRoomManage,
+	"PUT /room/recordstatus": service.PermRoomManage,
+
+	// User
+	"GET /user/getuseralreadyinroom": service.PermPublic,
+	"GET /user/getuserdetail":        service.PermPublic,
+	"GET /user/listparticipants":     service.PermPublic,
+	"GET /user/log":                  service.PermPublic,
+	"POST /user/generate":            service.PermPublic,
+	"POST /user/joingenerate":        service.PermPublic,
+	"POST /user/generateChatUser":    service.PermPublic,
+	"POST /user/updateparticipants":  service.PermParticipantManage,
+	"POST /user/mutepublishedtrack":  service.PermParticipantManage,
+	"POST /user/removeParticipant":   service.PermParticipantManage,
+	"PUT /user/handle/track":         service.PermParticipantManage,
+
+	// Waiting room
+	"GET /waitingroom/":             service.PermWaitingRoomManage,
+	"GET /waitingroom/:id":          service.PermPublic,
+	"POST /waitingroom/:id/approve": service.PermWaitingRoomManage,
+	"POST /waitingroom/:id/deny":    service.PermWaitingRoomManage,
+
+	// Link
+	"GET /link/getdetail":         service.PermPublic,
+	"GET /link/history":           service.PermLinkRead,
+	"GET /link/share":             service.PermPublic,
+	"GET /link/get/domain":        service.PermPublic,
+	"GET /link/list":              service.PermLinkRead,
+	"POST /link/create":           service.PermLinkManage,
+	"POST /link/create/hls":       service.PermLinkManage,
+	"POST /link/revoke":           service.PermLinkManage,
+	"POST /link/extend":           service.PermLinkManage,
+	"POST /link/enable":           service.PermLinkManage,
+	"POST /link/update/latlng":    service.PermPublic,
+	"POST /link/multilatlng/send": service.PermPublic,
+	"POST /link/cartracking":      service.PermPublic,
+
+	// Chat
+	"GET /chat/history":      service.PermPublic,
+	"GET /chat/notification": service.PermPublic,
+	"GET /chat/count":        service.PermPublic,
+	"POST /chat/message":     service.PermPublic,
+	"DELETE /chat/messages":  service.PermChatManage,
+
+	// Notification
+	"GET /notification/list":        service.PermNotificationRead,
+	"GET /notification/user":        service.PermNotificationRead,
+	"GET /notification/unread":      service.PermNotificationRead,
+	"GET /notification/unreadcount": service.PermNotificationRead,
+	"GET /notification/:id":         service.PermNotificationRead,
+	"POST /notification/create":     service.PermNotificationWrite,
+	"PUT /notification/read/:id":    service.PermNotificationWrite,
+	"PUT /notification/readall":     service.PermNotificationWrite,
+	"DELETE /notification/:id":      service.PermNotificationAdmin,
+
+	// Record
+	"GET /record/listegress":            service.PermRecordRead,
+	"GET /record/available":             service.PermPublic,
+	"GET /record/queue":                 service.PermRecordRead,
+	"DELETE /record/queue/:id":          service.PermRecordManage,
+	"GET /record/activecount":           service.PermPublic,
+	"GET /record/filehistory":           service.PermRecordRead,
+	"GET /record/room":                  service.PermRecordRead,
+	"GET /record/detail/:id":            service.PermRecordRead,
+	"POST /record/start":                service.PermRecordManage,
+	"POST /record/stop":                 service.PermRecordManage,
+	"POST /record/stopall":              service.PermRecordAdmin,
+	"GET /record/encode":                service.PermRecordRead,
+	"POST /record/encode/:id/retry":     service.PermRecordManage,
+	"GET /record/retention":             service.PermRecordAdmin,
+	"POST /record/retention/run":        service.PermRecordAdmin,
+	"PUT /record/retention/:service":    service.PermRecordAdmin,
+	"DELETE /record/retention/:service": service.PermRecordAdmin,
+	"GET /record/audit":                 service.PermRecordAdmin,
+	"DELETE /record/:id":                service.PermRecordAdmin,
+
+	// Recording playback
+	"POST /playback/link":       service.PermPublic,
+	"GET /playback/:recordId/*": service.PermPublic,
+
+	// Car tracking
+	"GET /car/list":           service.PermPublic,
+	"GET /car/task/:id":       service.PermPublic,
+	"GET /car/uid/:uid":       service.PermPublic,
+	"GET /car/room/:room":     service.PermPublic,
+	"GET /car/position/:room": service.PermPublic,
+	"GET /car/latlng/:room":   service.PermPublic,
+	"POST /car/task":          service.PermCarWrite,
+	"POST /car/position":      service.PermCarWrite,
+	"PUT /car/task/:id":       service.PermCarWrite,
+	"DELETE /car/task/:id":    service.PermCarAdmin,
+
+	// Case
+	"GET /case/history":          service.PermCaseRead,
+	"GET /case/historycount":     service.PermCaseRead,
+	"GET /case/roomname":         service.PermCaseRead,
+	"GET /case/service/:service": service.PermCaseRead,
+	"GET /case/caseid/:caseId":   service.PermCaseRead,
+	"GET /case/room/:roomId":     service.PermCaseRead,
+	"GET /case/:id":              service.PermCaseRead,
+	"POST /case/create":          service.PermCaseWrite,
+	"PUT /case/status/:caseId":   service.PermCaseWrite,
+	"PUT /case/legalhold/:id":    service.PermCaseAdmin,
+	"PUT /case/:id":              service.PermCaseWrite,
+	"DELETE /case/:id":           service.PermCaseAdmin,
+
+	// Radio
+	"GET /radio/devices":                   service.PermPublic,
+	"GET /radio/device/:id":                service.PermPublic,
+	"GET /radio/device/deviceid/:deviceId": service.PermPublic,
+	"GET /radio/locations":                 service.PermPublic,
+	"GET /radio/location/:radioNo":         service.PermPublic,
+	"POST /radio/device":                   service.PermRadioManage,
+	"POST /radio/location":                 service.PermRadioLocation,
+	"PUT /radio/device/location":           service.PermRadioLocation,
+	"PUT /radio/device/:id":                service.PermRadioManage,
+	"DELETE /radio/device/:id":             service.PermRadioManage,
+
+	// Stats
+	"GET /stats/summary": service.PermStatsRead,
+	"GET /stats/device":  service.PermStatsRead,
+	"GET /stats/type":    service.PermStatsRead,
+	"GET /stats/user":    service.PermStatsRead,
+	"GET /stats/case":    service.PermStatsRead,
+	"GET /stats/daily":   service.PermStatsRead,
+	"GET /stats/monthly": service.PermStatsRead,
+	"GET /stats/all":     service.PermStatsRead,
+
+	// Upload
+	"GET /upload/exists":    service.PermPublic,
+	"POST /upload/file":     service.PermPublic,
+	"POST /upload/image":    service.PermPublic,
+	"POST /upload/video":    service.PermPublic,
+	"POST /upload/multiple": service.PermPublic,
+	"DELETE /upload/file":   service.PermUploadAdmin,
+
+	// SMS
+	"GET /sms/outbox":             service.PermSMSRead,
+	"GET /sms/outbox/:id":         service.PermSMSRead,
+	"GET /sms/template":           service.PermSMSRead,
+	"GET /sms/template/:id":       service.PermSMSRead,
+	"GET /sms/blocklist":          service.PermSMSManage,
+	"POST /sms/outbox/:id/retry":  service.PermSMSManage,
+	"POST /sms/outbox/:id/cancel": service.PermSMSManage,
+	"POST /sms/template/preview":  service.PermSMSRead,
+	"POST /sms/template":          service.PermSMSManage,
+	"POST /sms/blocklist":         service.PermSMSManage,
+	"PUT /sms/template/:id":       service.PermSMSManage,
+	"DELETE /sms/template/:id":    service.PermSMSManage,
+	"DELETE /sms/blocklist/:id":   service.PermSMSManage,
+
+	// Webhook
+	"GET /webhook/sms/dlr":       service.PermPublic,
+	"GET /webhook/events/:room":  service.PermSystemManage,
+	"POST /webhook/livekit":      service.PermPublic,
+	"POST /webhook/generic":      service.PermPublic,
+	"POST /webhook/sms/dlr":      service.PermPublic,
+	"POST /webhook/encode/:id":   service.PermPublic,
+	"POST /webhook/replay/:room": service.PermSystemManage,
+
+	// Test
+	"GET /test/ping":     service.PermPublic,
+	"GET /test/database": service.PermSystemManage,
+	"GET /test/redis":    service.PermSystemManage,
+	"GET /test/livekit":  service.PermSystemManage,
+	"GET /test/all":      service.PermSystemManage,
+	"GET /test/config":   service.PermSystemManage,
+	"POST /test/echo":    service.PermPublic,
+}
+
+// CheckRoutePermissions verifies that every registered route is listed in
+// RoutePermissions, that every listed route is registered, and that every
+// route needing a permission runs the authorize middleware
+func CheckRoutePermissions(app *fiber.App, authorize fiber.Handler) error {
+	authorizePtr := reflect.ValueOf(authorize).Pointer()
+	registered := make(map[string]bool)
+	var problems []string
+
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		registered[key] = true
+
+		perm, ok := RoutePermissions[key]
+		if !ok {
+			problems = append(problems, key+": missing from RoutePermissions")
+			continue
+		}
+		if perm == service.PermPublic {
+			continue
+		}
+
+		guarded := false
+		for _, h := range route.Handlers {
+			if reflect.ValueOf(h).Pointer() == authorizePtr {
+				guarded = true
+				break
+			}
+		}
+		if !guarded {
+			problems = append(problems, key+": requires "+string(perm)+" but is not authorized")
+		}
+	}
+
+	for key := range RoutePermissions {
+		if !registered[key] {
+			problems = append(problems, key+": listed in RoutePermissions but not registered")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("route permission check failed:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/handler"
+	"api-gateway-go/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupTestRoutes registers all routes on app with empty handlers
+func setupTestRoutes(t *testing.T, app *fiber.App) error {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "0123456789abcdef0123456789abcdef"
+	authService, err := service.NewAuthService(cfg, nil)
+	if err != nil {
+		t.Fatalf("NewAuthService: %v", err)
+	}
+
+	handlers := &Handlers{
+		Auth:         &handler.AuthHandler{},
+		Room:         &handler.RoomHandler{},
+		User:         &handler.UserHandler{},
+		Link:         &handler.LinkHandler{},
+		System:       &handler.SystemHandler{},
+		Chat:         &handler.ChatHandler{},
+		Notification: &handler.NotificationHandler{},
+		Record:       &handler.RecordHandler{},
+		Playback:     &handler.PlaybackHandler{},
+		Car:          &handler.CarHandler{},
+		Case:         &handler.CaseHandler{},
+		Radio:        &handler.RadioHandler{},
+		Stats:        &handler.StatsHandler{},
+		Upload:       &handler.UploadHandler{},
+		SMS:          &handler.SMSHandler{},
+		Staff:        &handler.StaffHandler{},
+		APIKey:       &handler.APIKeyHandler{},
+		WaitingRoom:  &handler.WaitingRoomHandler{},
+		Webhook:      &handler.WebhookHandler{},
+		Test:         &handler.TestHandler{},
+	}
+
+	return SetupRoutes(app, handlers, authService, cfg, nil, nil, nil, nil)
+}
+
+func TestRoutePermissions(t *testing.T) {
+	if err := setupTestRoutes(t, fiber.New()); err != nil {
+		t.Fatalf("CheckRoutePermissions: %v", err)
+	}
+}
+
+func TestRoutePermissionsMissingRoute(t *testing.T) {
+	app := fiber.New()
+	app.Get("/unlisted", func(c *fiber.Ctx) error { return nil })
+
+	err := setupTestRoutes(t, app)
+	if err == nil || !strings.Contains(err.Error(), "GET /unlisted: missing from RoutePermissions") {
+		t.Fatalf("expected unlisted route to fail the check, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"api-gateway-go/internal/middleware"
 	"api-gateway-go/internal/repository"
 	"api-gateway-go/internal/service"
+	"api-gateway-go/pkg/metrics"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,16 +21,22 @@ type Handlers struct {
 	Chat         *handler.ChatHandler
 	Notification *handler.NotificationHandler
 	Record       *handler.RecordHandler
+	Playback     *handler.PlaybackHandler
 	Car          *handler.CarHandler
 	Case         *handler.CaseHandler
 	Radio        *handler.RadioHandler
 	Stats        *handler.StatsHandler
 	Upload       *handler.UploadHandler
+	SMS          *handler.SMSHandler
+	Staff        *handler.StaffHandler
+	APIKey       *handler.APIKeyHandler
+	WaitingRoom  *handler.WaitingRoomHandler
 	Webhook      *handler.WebhookHandler
 	Test         *handler.TestHandler
 }
 
-// SetupRoutes configures all routes for the application
+// SetupRoutes configures all routes for the application and checks them
+// against the route-permission matrix
 func SetupRoutes(
 	app *fiber.App,
 	handlers *Handlers,
@@ -39,35 +46,62 @@ func SetupRoutes(
 	redisMgr *config.RedisManager,
 	livekitMgr *config.LiveKitManager,
 	recordRepo *repository.RecordRepository,
-) {
+) error {
+	// Every protected route checks its permission in RoutePermissions
+	authorize := middleware.Authorize(authService, RoutePermissions)
+
 	// System routes
 	app.Get("/", handlers.System.Root)
 	app.Get("/health", handlers.System.HealthCheck)
 	app.Get("/status", handlers.System.GetStatus)
+	app.Get("/status/reconcile", authorize, handlers.System.GetRecordReconcile)
 	app.Get("/service", handlers.System.GetServiceInfo)
 	app.Get("/namespace", handlers.System.GetNamespaces)
 	app.Post("/log", handlers.System.AddLog)
+	app.Get("/metrics", authorize, metrics.Handler())
 
 	// Auth routes
+	app.Get("/.well-known/jwks.json", handlers.Auth.JWKS)
 	auth := app.Group("/auth")
-	auth.Get("/create", handlers.Auth.CreateToken)
+	auth.Get("/create", authorize, handlers.Auth.CreateToken)
 	auth.Get("/verify", handlers.Auth.VerifyToken)
 	auth.Post("/verifyuser", handlers.Auth.VerifyUser)
+	auth.Post("/refresh", handlers.Auth.Refresh)
+	auth.Post("/logout", authorize, handlers.Auth.Logout)
+
+	// Staff routes
+	staff := app.Group("/staff")
+	staff.Get("/", authorize, handlers.Staff.ListStaff)
+	staff.Post("/", authorize, handlers.Staff.CreateStaff)
+	staff.Put("/:id/password", authorize, handlers.Staff.ChangePassword)
+	staff.Put("/:id/role", authorize, handlers.Staff.SetRole)
+	staff.Put("/:id/enabled", authorize, handlers.Staff.SetEnabled)
+	staff.Post("/:id/unlock", authorize, handlers.Staff.Unlock)
+	staff.Post("/:id/revokeall", authorize, handlers.Staff.RevokeSessions)
+
+	// API key routes
+	apiKey := app.Group("/apikey")
+	apiKey.Get("/", authorize, handlers.APIKey.ListAPIKeys)
+	apiKey.Post("/", authorize, handlers.APIKey.CreateAPIKey)
+	apiKey.Put("/:id", authorize, handlers.APIKey.UpdateAPIKey)
+	apiKey.Put("/:id/enabled", authorize, handlers.APIKey.SetEnabled)
+	apiKey.Post("/:id/rotate", authorize, handlers.APIKey.RotateAPIKey)
+	apiKey.Delete("/:id", authorize, handlers.APIKey.DeleteAPIKey)
 
 	// Room routes
 	room := app.Group("/room")
 	room.Get("/detail", handlers.Room.GetRoomDetail)
-	room.Get("/listrooms", handlers.Room.ListRooms)
+	room.Get("/listrooms", authorize, handlers.Room.ListRooms)
 	room.Get("/checkexpired", handlers.Room.CheckExpired)
 	room.Get("/verifytoken", handlers.Room.VerifyToken)
 	room.Get("/picture", handlers.Room.GetRoomPicture)
-	room.Post("/create", middleware.AuthMiddleware(authService), handlers.Room.CreateRoom)
-	room.Post("/updateuser", middleware.AuthMiddleware(authService), handlers.Room.UpdateUser)
-	room.Post("/deleteroom", middleware.AuthMiddleware(authService), handlers.Room.DeleteRoom)
-	room.Put("/updatetype", middleware.AuthMiddleware(authService), handlers.Room.UpdateType)
-	room.Put("/updatestatus", handlers.Room.UpdateStatus)
-	room.Put("/close", handlers.Room.CloseRoom)
-	room.Put("/recordstatus", handlers.Room.UpdateRecordStatus)
+	room.Post("/create", authorize, handlers.Room.CreateRoom)
+	room.Post("/updateuser", authorize, handlers.Room.UpdateUser)
+	room.Post("/deleteroom", authorize, handlers.Room.DeleteRoom)
+	room.Put("/updatetype", authorize, handlers.Room.UpdateType)
+	room.Put("/updatestatus", authorize, handlers.Room.UpdateStatus)
+	room.Put("/close", authorize, handlers.Room.CloseRoom)
+	room.Put("/recordstatus", authorize, handlers.Room.UpdateRecordStatus)
 
 	// User routes
 	user := app.Group("/user")
@@ -76,22 +110,32 @@ func SetupRoutes(
 	user.Get("/listparticipants", handlers.User.ListParticipants)
 	user.Get("/log", handlers.User.GetUserLog)
 	user.Post("/generate", middleware.OptionalAuthMiddleware(authService), handlers.User.GenerateUser)
-	user.Post("/joingenerate", handlers.User.JoinGenerate)
+	user.Post("/joingenerate", middleware.OptionalAuthMiddleware(authService), handlers.User.JoinGenerate)
 	user.Post("/generateChatUser", handlers.User.GenerateChatUser)
-	user.Post("/updateparticipants", handlers.User.UpdateParticipants)
-	user.Post("/mutepublishedtrack", handlers.User.MutePublishedTrack)
-	user.Post("/removeParticipant", handlers.User.RemoveParticipant)
-	user.Put("/handle/track", handlers.User.HandleTrack)
+	user.Post("/updateparticipants", authorize, handlers.User.UpdateParticipants)
+	user.Post("/mutepublishedtrack", authorize, handlers.User.MutePublishedTrack)
+	user.Post("/removeParticipant", authorize, handlers.User.RemoveParticipant)
+	user.Put("/handle/track", authorize, handlers.User.HandleTrack)
+
+	// Waiting room routes
+	waitingRoom := app.Group("/waitingroom")
+	waitingRoom.Get("/", authorize, handlers.WaitingRoom.ListPending)
+	waitingRoom.Get("/:id", handlers.WaitingRoom.GetStatus)
+	waitingRoom.Post("/:id/approve", authorize, handlers.WaitingRoom.Approve)
+	waitingRoom.Post("/:id/deny", authorize, handlers.WaitingRoom.Deny)
 
 	// Link routes
 	link := app.Group("/link")
 	link.Get("/getdetail", handlers.Link.GetLinkDetail)
-	link.Get("/history", handlers.Link.GetLinkHistory)
+	link.Get("/history", authorize, handlers.Link.GetLinkHistory)
 	link.Get("/share", handlers.Link.GetShareURL)
 	link.Get("/get/domain", handlers.Link.GetDomain)
-	link.Get("/list", handlers.Link.GetLinkList)
-	link.Post("/create", middleware.AuthMiddleware(authService), handlers.Link.CreateLink)
-	link.Post("/create/hls", middleware.AuthMiddleware(authService), handlers.Link.CreateHLSLink)
+	link.Get("/list", authorize, handlers.Link.GetLinkList)
+	link.Post("/create", authorize, handlers.Link.CreateLink)
+	link.Post("/create/hls", authorize, handlers.Link.CreateHLSLink)
+	link.Post("/revoke", authorize, handlers.Link.RevokeLink)
+	link.Post("/extend", authorize, handlers.Link.ExtendLink)
+	link.Post("/enable", authorize, handlers.Link.EnableLink)
 	link.Post("/update/latlng", handlers.Link.UpdateLatLng)
 	link.Post("/multilatlng/send", handlers.Link.MultiLatLng)
 	link.Post("/cartracking", handlers.Link.CarTracking)
@@ -102,32 +146,46 @@ func SetupRoutes(
 	chat.Get("/notification", handlers.Chat.GetNotification)
 	chat.Get("/count", handlers.Chat.GetMessageCount)
 	chat.Post("/message", handlers.Chat.AddMessage)
-	chat.Delete("/messages", middleware.AuthMiddleware(authService), handlers.Chat.DeleteMessages)
+	chat.Delete("/messages", authorize, handlers.Chat.DeleteMessages)
 
 	// Notification routes
 	notification := app.Group("/notification")
-	notification.Get("/list", handlers.Notification.ListNotifications)
-	notification.Get("/user", handlers.Notification.GetByUserName)
-	notification.Get("/unread", handlers.Notification.GetUnread)
-	notification.Get("/unreadcount", handlers.Notification.GetUnreadCount)
-	notification.Get("/:id", handlers.Notification.GetByID)
-	notification.Post("/create", handlers.Notification.Create)
-	notification.Put("/read/:id", handlers.Notification.MarkAsRead)
-	notification.Put("/readall", handlers.Notification.MarkAllAsRead)
-	notification.Delete("/:id", middleware.AuthMiddleware(authService), handlers.Notification.Delete)
+	notification.Get("/list", authorize, handlers.Notification.ListNotifications)
+	notification.Get("/user", authorize, handlers.Notification.GetByUserName)
+	notification.Get("/unread", authorize, handlers.Notification.GetUnread)
+	notification.Get("/unreadcount", authorize, handlers.Notification.GetUnreadCount)
+	notification.Get("/:id", authorize, handlers.Notification.GetByID)
+	notification.Post("/create", authorize, handlers.Notification.Create)
+	notification.Put("/read/:id", authorize, handlers.Notification.MarkAsRead)
+	notification.Put("/readall", authorize, handlers.Notification.MarkAllAsRead)
+	notification.Delete("/:id", authorize, handlers.Notification.Delete)
 
 	// Record routes
 	record := app.Group("/record")
-	record.Get("/listegress", handlers.Record.ListEgress)
+	record.Get("/listegress", authorize, handlers.Record.ListEgress)
 	record.Get("/available", handlers.Record.CheckEgressAvailable)
-	record.Get("/queue", handlers.Record.GetRecordQueue)
+	record.Get("/queue", authorize, handlers.Record.GetRecordQueue)
 	record.Get("/activecount", handlers.Record.GetActiveRecordCount)
-	record.Get("/filehistory", handlers.Record.GetFileHistory)
-	record.Get("/room", handlers.Record.GetRecordByRoom)
-	record.Get("/detail/:id", handlers.Record.GetRecordDetail)
-	record.Post("/start", middleware.AuthMiddleware(authService), handlers.Record.StartRecord)
-	record.Post("/stop", middleware.AuthMiddleware(authService), handlers.Record.StopRecord)
-	record.Post("/stopall", middleware.AuthMiddleware(authService), handlers.Record.StopAllActive)
+	record.Get("/filehistory", authorize, handlers.Record.GetFileHistory)
+	record.Get("/room", authorize, handlers.Record.GetRecordByRoom)
+	record.Get("/detail/:id", authorize, handlers.Record.GetRecordDetail)
+	record.Post("/start", authorize, handlers.Record.StartRecord)
+	record.Post("/stop", authorize, handlers.Record.StopRecord)
+	record.Post("/stopall", authorize, handlers.Record.StopAllActive)
+	record.Delete("/queue/:id", authorize, handlers.Record.CancelQueuedRecord)
+	record.Get("/encode", authorize, handlers.Record.ListEncodeJobs)
+	record.Post("/encode/:id/retry", authorize, handlers.Record.RetryEncodeJob)
+	record.Get("/retention", authorize, handlers.Record.ListRetentionPolicies)
+	record.Post("/retention/run", authorize, handlers.Record.RunRetention)
+	record.Put("/retention/:service", authorize, handlers.Record.SetRetentionPolicy)
+	record.Delete("/retention/:service", authorize, handlers.Record.DeleteRetentionPolicy)
+	record.Get("/audit", authorize, handlers.Record.ListRecordAudit)
+	record.Delete("/:id", authorize, handlers.Record.DeleteRecord)
+
+	// Recording playback routes, authorised by HLS links and signed URLs
+	playback := app.Group("/playback")
+	playback.Post("/link", handlers.Playback.ResolveLink)
+	playback.Get("/:recordId/*", handlers.Playback.ServeFile)
 
 	// Car tracking routes
 	car := app.Group("/car")
@@ -137,24 +195,25 @@ func SetupRoutes(
 	car.Get("/room/:room", handlers.Car.GetTaskByRoom)
 	car.Get("/position/:room", handlers.Car.GetCarPosition)
 	car.Get("/latlng/:room", handlers.Car.GetUserLatLng)
-	car.Post("/task", handlers.Car.CreateTask)
-	car.Post("/position", handlers.Car.UpdatePosition)
-	car.Put("/task/:id", handlers.Car.UpdateTask)
-	car.Delete("/task/:id", middleware.AuthMiddleware(authService), handlers.Car.DeleteTask)
+	car.Post("/task", authorize, handlers.Car.CreateTask)
+	car.Post("/position", authorize, handlers.Car.UpdatePosition)
+	car.Put("/task/:id", authorize, handlers.Car.UpdateTask)
+	car.Delete("/task/:id", authorize, handlers.Car.DeleteTask)
 
 	// Case routes
 	caseRoutes := app.Group("/case")
-	caseRoutes.Get("/history", handlers.Case.GetCaseHistory)
-	caseRoutes.Get("/historycount", handlers.Case.GetCaseHistoryCount)
-	caseRoutes.Get("/roomname", handlers.Case.GetRoomName)
-	caseRoutes.Get("/service/:service", handlers.Case.GetCasesByService)
-	caseRoutes.Get("/caseid/:caseId", handlers.Case.GetCaseByCaseID)
-	caseRoutes.Get("/room/:roomId", handlers.Case.GetCaseByRoomID)
-	caseRoutes.Get("/:id", handlers.Case.GetCaseByID)
-	caseRoutes.Post("/create", middleware.AuthMiddleware(authService), handlers.Case.CreateCase)
-	caseRoutes.Put("/status/:caseId", handlers.Case.UpdateCaseStatus)
-	caseRoutes.Put("/:id", middleware.AuthMiddleware(authService), handlers.Case.UpdateCase)
-	caseRoutes.Delete("/:id", middleware.AuthMiddleware(authService), handlers.Case.DeleteCase)
+	caseRoutes.Get("/history", authorize, handlers.Case.GetCaseHistory)
+	caseRoutes.Get("/historycount", authorize, handlers.Case.GetCaseHistoryCount)
+	caseRoutes.Get("/roomname", authorize, handlers.Case.GetRoomName)
+	caseRoutes.Get("/service/:service", authorize, handlers.Case.GetCasesByService)
+	caseRoutes.Get("/caseid/:caseId", authorize, handlers.Case.GetCaseByCaseID)
+	caseRoutes.Get("/room/:roomId", authorize, handlers.Case.GetCaseByRoomID)
+	caseRoutes.Get("/:id", authorize, handlers.Case.GetCaseByID)
+	caseRoutes.Post("/create", authorize, handlers.Case.CreateCase)
+	caseRoutes.Put("/status/:caseId", authorize, handlers.Case.UpdateCaseStatus)
+	caseRoutes.Put("/legalhold/:id", authorize, handlers.Case.SetLegalHold)
+	caseRoutes.Put("/:id", authorize, handlers.Case.UpdateCase)
+	caseRoutes.Delete("/:id", authorize, handlers.Case.DeleteCase)
 
 	// Radio routes
 	radio := app.Group("/radio")
@@ -163,22 +222,22 @@ func SetupRoutes(
 	radio.Get("/device/deviceid/:deviceId", handlers.Radio.GetDeviceByDeviceID)
 	radio.Get("/locations", handlers.Radio.ListLocations)
 	radio.Get("/location/:radioNo", handlers.Radio.GetLocationByRadioNo)
-	radio.Post("/device", handlers.Radio.CreateDevice)
-	radio.Post("/location", handlers.Radio.CreateLocation)
-	radio.Put("/device/:id", handlers.Radio.UpdateDevice)
-	radio.Put("/device/location", handlers.Radio.UpdateDeviceLocation)
-	radio.Delete("/device/:id", middleware.AuthMiddleware(authService), handlers.Radio.DeleteDevice)
+	radio.Post("/device", authorize, handlers.Radio.CreateDevice)
+	radio.Post("/location", authorize, handlers.Radio.CreateLocation)
+	radio.Put("/device/location", authorize, handlers.Radio.UpdateDeviceLocation)
+	radio.Put("/device/:id", authorize, handlers.Radio.UpdateDevice)
+	radio.Delete("/device/:id", authorize, handlers.Radio.DeleteDevice)
 
 	// Stats routes
 	stats := app.Group("/stats")
-	stats.Get("/summary", handlers.Stats.GetSummary)
-	stats.Get("/device", handlers.Stats.GetDeviceStats)
-	stats.Get("/type", handlers.Stats.GetTypeStats)
-	stats.Get("/user", handlers.Stats.GetUserStats)
-	stats.Get("/case", handlers.Stats.GetCaseStats)
-	stats.Get("/daily", handlers.Stats.GetDailyStats)
-	stats.Get("/monthly", handlers.Stats.GetMonthlyStats)
-	stats.Get("/all", handlers.Stats.GetAll)
+	stats.Get("/summary", authorize, handlers.Stats.GetSummary)
+	stats.Get("/device", authorize, handlers.Stats.GetDeviceStats)
+	stats.Get("/type", authorize, handlers.Stats.GetTypeStats)
+	stats.Get("/user", authorize, handlers.Stats.GetUserStats)
+	stats.Get("/case", authorize, handlers.Stats.GetCaseStats)
+	stats.Get("/daily", authorize, handlers.Stats.GetDailyStats)
+	stats.Get("/monthly", authorize, handlers.Stats.GetMonthlyStats)
+	stats.Get("/all", authorize, handlers.Stats.GetAll)
 
 	// Upload routes
 	upload := app.Group("/upload")
@@ -187,22 +246,43 @@ func SetupRoutes(
 	upload.Post("/video", handlers.Upload.UploadVideo)
 	upload.Post("/multiple", handlers.Upload.UploadMultiple)
 	upload.Get("/exists", handlers.Upload.CheckFileExists)
-	upload.Delete("/file", middleware.AuthMiddleware(authService), handlers.Upload.DeleteFile)
+	upload.Delete("/file", authorize, handlers.Upload.DeleteFile)
+
+	// SMS routes
+	sms := app.Group("/sms")
+	sms.Get("/outbox", authorize, handlers.SMS.ListOutbox)
+	sms.Get("/outbox/:id", authorize, handlers.SMS.GetOutbox)
+	sms.Post("/outbox/:id/retry", authorize, handlers.SMS.RetryOutbox)
+	sms.Post("/outbox/:id/cancel", authorize, handlers.SMS.CancelOutbox)
+	sms.Get("/template", authorize, handlers.SMS.ListTemplates)
+	sms.Get("/template/:id", authorize, handlers.SMS.GetTemplate)
+	sms.Post("/template/preview", authorize, handlers.SMS.PreviewTemplate)
+	sms.Post("/template", authorize, handlers.SMS.CreateTemplate)
+	sms.Put("/template/:id", authorize, handlers.SMS.UpdateTemplate)
+	sms.Delete("/template/:id", authorize, handlers.SMS.DeleteTemplate)
+	sms.Get("/blocklist", authorize, handlers.SMS.ListBlocklist)
+	sms.Post("/blocklist", authorize, handlers.SMS.AddBlocklist)
+	sms.Delete("/blocklist/:id", authorize, handlers.SMS.RemoveBlocklist)
 
 	// Webhook routes
 	webhook := app.Group("/webhook")
 	webhook.Post("/livekit", handlers.Webhook.HandleLiveKitWebhook)
 	webhook.Post("/generic", handlers.Webhook.HandleGenericWebhook)
+	webhook.Post("/sms/dlr", handlers.Webhook.HandleSMSDeliveryReport)
+	webhook.Get("/sms/dlr", handlers.Webhook.HandleSMSDeliveryReport)
+	webhook.Post("/encode/:id", handlers.Webhook.HandleEncodeCallback)
+	webhook.Get("/events/:room", authorize, handlers.Webhook.ListWebhookEvents)
+	webhook.Post("/replay/:room", authorize, handlers.Webhook.ReplayWebhookEvents)
 
 	// Test routes
 	test := app.Group("/test")
 	test.Get("/ping", handlers.Test.Ping)
 	test.Post("/echo", handlers.Test.Echo)
-	test.Get("/database", handlers.Test.TestDatabase)
-	test.Get("/redis", handlers.Test.TestRedis)
-	test.Get("/livekit", handlers.Test.TestLiveKit)
-	test.Get("/all", handlers.Test.TestAll)
-	test.Get("/config", handlers.Test.GetConfig)
+	test.Get("/database", authorize, handlers.Test.TestDatabase)
+	test.Get("/redis", authorize, handlers.Test.TestRedis)
+	test.Get("/livekit", authorize, handlers.Test.TestLiveKit)
+	test.Get("/all", authorize, handlers.Test.TestAll)
+	test.Get("/config", authorize, handlers.Test.GetConfig)
 
 	// Static file serving
 	app.Static("/logo", "./logo")
@@ -210,5 +290,6 @@ func SetupRoutes(
 	app.Static("/images", "./uploads/images")
 	app.Static("/thumbnails", "./uploads/thumbnails")
 	app.Static("/files", "./uploads/files")
-	app.Static("/record", "./record-file")
+
+	return CheckRoutePermissions(app, authorize)
 }
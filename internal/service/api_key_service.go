@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrInvalidAPIKey      = errors.New("invalid API key")
+	ErrAPIKeyRateLimited  = errors.New("API key rate limit exceeded")
+	ErrAPIKeyNotFound     = errors.New("API key not found")
+	ErrAPIKeyNameRequired = errors.New("name is required")
+	ErrInvalidScope       = errors.New("invalid scope")
+	ErrScopeNotAllowed    = errors.New("scope can only be granted to API keys without a service")
+)
+
+// API key rejection error codes returned to API clients
+const (
+	APIKeyCodeInvalid     = "APIKEY_INVALID"
+	APIKeyCodeRateLimited = "APIKEY_RATE_LIMITED"
+)
+
+// UserTypeAPIKey is the userType of requests authenticated with an API key
+const UserTypeAPIKey = "apikey"
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognise
+const apiKeyPrefix = "gk_"
+
+// apiKeyRateKeyPrefix is the Redis key prefix for per-key request counters
+const apiKeyRateKeyPrefix = "apikey:rate:"
+
+// CreateAPIKeyOptions holds options for creating an API key
+type CreateAPIKeyOptions struct {
+	Name         string
+	Scopes       []string
+	RateLimit    int
+	Service      int
+	Organization string
+	CreatedBy    string
+}
+
+// APIKeyResult holds a newly issued API key. Key is only ever returned here.
+type APIKeyResult struct {
+	Key    string         `json:"key"`
+	APIKey *models.APIKey `json:"apiKey"`
+}
+
+// APIKeyService manages integration API keys and authenticates requests made with them
+type APIKeyService struct {
+	apiKeyRepo *repository.APIKeyRepository
+	redis      *config.RedisManager
+	cfg        *config.Config
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(apiKeyRepo *repository.APIKeyRepository, redis *config.RedisManager, cfg *config.Config) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		redis:      redis,
+		cfg:        cfg,
+	}
+}
+
+// IsAPIKey reports whether a credential has the format of an API key rather than a JWT
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, apiKeyPrefix)
+}
+
+// Authenticate resolves an API key to claims carrying its scopes and tenant.
+// Each key is limited to its rateLimit requests per minute.
+func (s *APIKeyService) Authenticate(ctx context.Context, key, ip string) (*Claims, error) {
+	if !IsAPIKey(key) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, hashSecret(key), time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || !apiKey.Enabled.Valid || apiKey.Enabled.Int32 != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err := s.checkRateLimit(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, ip); err != nil {
+		log.Warn().Err(err).Uint("apiKeyId", apiKey.ID).Msg("Failed to record API key usage")
+	}
+
+	return &Claims{
+		UserName:     apiKey.Name,
+		Identity:     apiKeyPrefix + strconv.FormatUint(uint64(apiKey.ID), 10),
+		UserType:     UserTypeAPIKey,
+		APIKeyID:     apiKey.ID,
+		Scopes:       grantedScopes(apiKey),
+		Service:      apiKey.Service,
+		Organization: utils.NullStringValue(apiKey.Organization),
+	}, nil
+}
+
+// checkRateLimit counts the request in a one-minute window. It fails open when Redis is unavailable.
+func (s *APIKeyService) checkRateLimit(ctx context.Context, apiKey *models.APIKey) error {
+	if apiKey.RateLimit <= 0 || s.redis == nil {
+		return nil
+	}
+
+	window := time.Now().Unix() / 60
+	key := apiKeyRateKeyPrefix + strconv.FormatUint(uint64(apiKey.ID), 10) + ":" + strconv.FormatInt(window, 10)
+
+	pipe := s.redis.StateClient().TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*time.Minute)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Error().Err(err).Msg("API key rate limit check failed, allowing request")
+		return nil
+	}
+
+	if count.Val() > int64(apiKey.RateLimit) {
+		return ErrAPIKeyRateLimited
+	}
+	return nil
+}
+
+// ListAPIKeys lists every API key
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.apiKeyRepo.List(ctx)
+}
+
+// GetAPIKey gets an API key by ID
+func (s *APIKeyService) GetAPIKey(ctx context.Context, id uint) (*models.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return apiKey, nil
+}
+
+// CreateAPIKey issues a new API key. Only its SHA-256 hash is stored.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, opts CreateAPIKeyOptions) (*APIKeyResult, error) {
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		return nil, ErrAPIKeyNameRequired
+	}
+	scopes, err := apiKeyScopes(opts.Scopes, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.apiKeyRepo.Create(ctx, repository.CreateAPIKeyParams{
+		Name:         name,
+		KeyPrefix:    apiKeyDisplayPrefix(key),
+		KeyHash:      hashSecret(key),
+		Scopes:       scopes,
+		RateLimit:    opts.RateLimit,
+		Service:      opts.Service,
+		Organization: opts.Organization,
+		CreatedBy:    opts.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIKeyResult{Key: key, APIKey: apiKey}, nil
+}
+
+// RotateAPIKey issues a new key for an integration. The old key keeps working
+// for the configured grace period so the integration can switch over.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, id uint) (*APIKeyResult, error) {
+	if _, err := s.GetAPIKey(ctx, id); err != nil {
+		return nil, err
+	}
+
+	key, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	previousExpired := time.Now().Add(s.cfg.Auth.APIKeyRotationGrace).Format("2006-01-02 15:04:05")
+	if err := s.apiKeyRepo.Rotate(ctx, id, apiKeyDisplayPrefix(key), hashSecret(key), previousExpired); err != nil {
+		return nil, err
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIKeyResult{Key: key, APIKey: apiKey}, nil
+}
+
+// UpdateAPIKey changes the scopes and rate limit of an API key
+func (s *APIKeyService) UpdateAPIKey(ctx context.Context, id uint, scopes []string, rateLimit int) (*models.APIKey, error) {
+	apiKey, err := s.GetAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	scopeList, err := apiKeyScopes(scopes, apiKey.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepo.Update(ctx, id, scopeList, rateLimit); err != nil {
+		return nil, err
+	}
+	return s.apiKeyRepo.GetByID(ctx, id)
+}
+
+// SetAPIKeyEnabled enables or disables an API key
+func (s *APIKeyService) SetAPIKeyEnabled(ctx context.Context, id uint, enabled bool) error {
+	if _, err := s.GetAPIKey(ctx, id); err != nil {
+		return err
+	}
+
+	value := 0
+	if enabled {
+		value = 1
+	}
+	return s.apiKeyRepo.SetEnabled(ctx, id, value)
+}
+
+// DeleteAPIKey deletes an API key
+func (s *APIKeyService) DeleteAPIKey(ctx context.Context, id uint) error {
+	if _, err := s.GetAPIKey(ctx, id); err != nil {
+		return err
+	}
+	return s.apiKeyRepo.Delete(ctx, id)
+}
+
+// newAPIKey returns a random API key
+func newAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// apiKeyDisplayPrefix returns the start of a key, stored so admins can tell keys apart
+func apiKeyDisplayPrefix(key string) string {
+	return key[:len(apiKeyPrefix)+8]
+}
+
+// globalScopes manage every tenant, so only global API keys may hold them.
+// A key bound to a service could otherwise create accounts or keys outside it.
+var globalScopes = map[Permission]bool{
+	PermStaffManage:  true,
+	PermAPIKeyManage: true,
+	PermSystemManage: true,
+}
+
+// apiKeyScopes validates scopes and joins them for storage. Scopes are
+// permission names; the public and authenticated markers are not scopes.
+// Keys bound to a service cannot hold the global scopes.
+func apiKeyScopes(scopes []string, service int) (string, error) {
+	seen := make(map[string]bool)
+	var valid []string
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		perm, ok := ParsePermission(scope)
+		if !ok {
+			return "", ErrInvalidScope
+		}
+		if service != 0 && globalScopes[perm] {
+			return "", ErrScopeNotAllowed
+		}
+		seen[scope] = true
+		valid = append(valid, scope)
+	}
+	return strings.Join(valid, ","), nil
+}
+
+// grantedScopes returns the scopes an API key holds. Global scopes stored on a
+// key bound to a service, e.g. before they were rejected, are not granted.
+func grantedScopes(apiKey *models.APIKey) []string {
+	var granted []string
+	for _, scope := range splitScopes(apiKey.Scopes) {
+		if apiKey.Service != 0 && globalScopes[Permission(scope)] {
+			continue
+		}
+		granted = append(granted, scope)
+	}
+	return granted
+}
+
+// splitScopes splits stored scopes
+func splitScopes(scopes string) []string {
+	if scopes == "" {
+		return nil
+	}
+	return strings.Split(scopes, ",")
+}
+
+// APIKeyErrorCode returns the API error code for an API key rejection, or "" for other errors
+func APIKeyErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidAPIKey):
+		return APIKeyCodeInvalid
+	case errors.Is(err, ErrAPIKeyRateLimited):
+		return APIKeyCodeRateLimited
+	}
+	return ""
+}
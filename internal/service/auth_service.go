@@ -2,71 +2,127 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"strconv"
 	"time"
 
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/pkg/utils"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
 )
 
 var (
-	ErrInvalidToken    = errors.New("invalid token")
-	ErrExpiredToken    = errors.New("token expired")
-	ErrInvalidPassword = errors.New("invalid password")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+	ErrRevokedToken = errors.New("token revoked")
 )
 
+// UserTypeStaff is the userType carried by staff access tokens
+const UserTypeStaff = "staff"
+
+// authDenyKeyPrefix is the Redis key prefix for revoked access tokens
+const authDenyKeyPrefix = "auth:deny:"
+
 // Claims represents the JWT claims
 type Claims struct {
-	UserName string `json:"userName,omitempty"`
-	Room     string `json:"room,omitempty"`
-	Identity string `json:"identity,omitempty"`
-	UserType string `json:"userType,omitempty"`
+	UserName     string   `json:"userName,omitempty"`
+	Room         string   `json:"room,omitempty"`
+	Identity     string   `json:"identity,omitempty"`
+	UserType     string   `json:"userType,omitempty"`
+	Role         string   `json:"role,omitempty"`
+	StaffID      uint     `json:"staffId,omitempty"`
+	APIKeyID     uint     `json:"apiKeyId,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Service      int      `json:"service,omitempty"`
+	Organization string   `json:"organization,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// AuthService handles authentication operations
+// AuthService handles authentication operations. Tokens are signed with the
+// active key of the key set when JWT_KEY_DIR is configured, otherwise with the
+// HS256 secret. The secret still verifies tokens without a kid header.
 type AuthService struct {
-	jwtSecret string
+	jwtSecret []byte
+	keys      *keySet
+	redis     *config.RedisManager
 }
 
-// NewAuthService creates a new AuthService
-func NewAuthService(jwtSecret string) *AuthService {
-	if jwtSecret == "" {
-		jwtSecret = "default-secret-key"
+// NewAuthService creates a new AuthService. In production it refuses to start
+// without JWT_SECRET or JWT_KEY_DIR, or with a default or short secret; elsewhere
+// it only warns, and signs with a random secret when none is set.
+func NewAuthService(cfg *config.Config, redis *config.RedisManager) (*AuthService, error) {
+	s := &AuthService{redis: redis}
+
+	if cfg.Auth.JWTKeyDir != "" {
+		keys, err := loadKeySet(cfg.Auth.JWTKeyDir, cfg.Auth.JWTSigningKeyID)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = keys
+		log.Info().Str("kid", keys.active.kid).Str("alg", keys.active.method.Alg()).Int("keys", len(keys.keys)).Msg("Loaded JWT signing keys")
 	}
-	return &AuthService{
-		jwtSecret: jwtSecret,
+
+	secret := cfg.Auth.JWTSecret
+	if s.keys != nil && secret == "" {
+		return s, nil
+	}
+	production := cfg.Environment == "production"
+	switch {
+	case secret == "" && production:
+		return nil, ErrJWTNotConfigured
+	case secret == "":
+		random, err := newTokenID()
+		if err != nil {
+			return nil, err
+		}
+		secret = random
+		log.Warn().Msg("JWT_SECRET is not set, signing with a random secret; tokens do not survive a restart or work across instances")
+	case secret == defaultJWTSecret || len(secret) < minJWTSecretLength:
+		if production {
+			return nil, ErrInsecureJWTSecret
+		}
+		log.Warn().Msg("JWT_SECRET is the default or shorter than 32 bytes, only use this outside production")
 	}
+	s.jwtSecret = []byte(secret)
+
+	return s, nil
 }
 
-// CreateToken creates a new JWT token
-func (s *AuthService) CreateToken(ctx context.Context, userName, room, identity, userType string, expiresIn time.Duration) (string, error) {
+// CreateToken creates a new JWT token. The service binds the token to a tenant; 0 binds it to none.
+func (s *AuthService) CreateToken(ctx context.Context, userName, room, identity, userType string, service int, expiresIn time.Duration) (string, error) {
 	if expiresIn == 0 {
 		expiresIn = 24 * time.Hour // Default 24 hours
 	}
 
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
 	claims := &Claims{
 		UserName: userName,
 		Room:     room,
 		Identity: identity,
 		UserType: userType,
+		Service:  service,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return s.sign(claims)
 }
 
 // VerifyToken verifies a JWT token and returns the claims
 func (s *AuthService) VerifyToken(ctx context.Context, tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(s.jwtSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.verificationKey)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -83,15 +139,144 @@ func (s *AuthService) VerifyToken(ctx context.Context, tokenString string) (*Cla
 	return claims, nil
 }
 
-// VerifyUser verifies a user by username and password
-func (s *AuthService) VerifyUser(ctx context.Context, userName, password, expectedPassword string) error {
-	if password != expectedPassword {
-		return ErrInvalidPassword
+// CreateStaffToken creates an access token for a logged-in staff account
+func (s *AuthService) CreateStaffToken(ctx context.Context, staff *models.StaffAccount, expiresIn time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
 	}
-	return nil
+
+	now := time.Now()
+	claims := &Claims{
+		UserName:     staff.UserName,
+		Identity:     staff.UserName,
+		UserType:     UserTypeStaff,
+		Role:         staff.Role,
+		StaffID:      staff.ID,
+		Service:      staff.Service,
+		Organization: utils.NullStringValue(staff.Organization),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   strconv.FormatUint(uint64(staff.ID), 10),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return s.sign(claims)
+}
+
+// sign signs claims with the active key, or with the HS256 secret when no key set is configured
+func (s *AuthService) sign(claims *Claims) (string, error) {
+	if s.keys != nil {
+		token := jwt.NewWithClaims(s.keys.active.method, claims)
+		token.Header["kid"] = s.keys.active.kid
+		return token.SignedString(s.keys.active.private)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.jwtSecret)
+}
+
+// verificationKey picks the key for a token by its kid header. Tokens without
+// a kid are verified with the HS256 secret when one is configured.
+func (s *AuthService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if kid, ok := token.Header["kid"].(string); ok {
+		if s.keys == nil {
+			return nil, ErrInvalidToken
+		}
+		key, ok := s.keys.keys[kid]
+		if !ok || token.Method.Alg() != key.method.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return key.public, nil
+	}
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || s.jwtSecret == nil {
+		return nil, ErrInvalidToken
+	}
+	return s.jwtSecret, nil
+}
+
+// JWKS returns the public keys other services verify gateway tokens with.
+// The set is empty when tokens are signed with the HS256 secret.
+func (s *AuthService) JWKS() JWKS {
+	if s.keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return s.keys.jwks()
 }
 
 // CreateRoomToken creates a token for room access
 func (s *AuthService) CreateRoomToken(ctx context.Context, room string, expiresIn time.Duration) (string, error) {
-	return s.CreateToken(ctx, "", room, "", "", expiresIn)
+	return s.CreateToken(ctx, "", room, "", "", 0, expiresIn)
+}
+
+// RevokeToken puts an access token on the deny-list until it expires
+func (s *AuthService) RevokeToken(ctx context.Context, claims *Claims) error {
+	if s.redis == nil || claims.ID == "" {
+		return nil
+	}
+
+	ttl := time.Hour
+	if claims.ExpiresAt != nil {
+		ttl = time.Until(claims.ExpiresAt.Time)
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.redis.StateClient().Set(ctx, authDenyKeyPrefix+"jti:"+claims.ID, 1, ttl).Err()
+}
+
+// RevokeStaffTokens rejects every access token issued to a staff account up to now.
+// The marker only has to outlive the longest access token still in circulation.
+func (s *AuthService) RevokeStaffTokens(ctx context.Context, staffID uint, maxTokenTTL time.Duration) error {
+	if s.redis == nil {
+		return nil
+	}
+
+	key := authDenyKeyPrefix + "staff:" + strconv.FormatUint(uint64(staffID), 10)
+	return s.redis.StateClient().Set(ctx, key, time.Now().Unix(), maxTokenTTL).Err()
+}
+
+// CheckRevoked returns ErrRevokedToken when the token is on the deny-list.
+// Lookups fail open when Redis is unavailable; access tokens are short-lived.
+func (s *AuthService) CheckRevoked(ctx context.Context, claims *Claims) error {
+	if s.redis == nil {
+		return nil
+	}
+
+	keys := []string{authDenyKeyPrefix + "jti:" + claims.ID}
+	if claims.StaffID != 0 {
+		keys = append(keys, authDenyKeyPrefix+"staff:"+strconv.FormatUint(uint64(claims.StaffID), 10))
+	}
+
+	values, err := s.redis.StateClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Token deny-list lookup failed, allowing token")
+		return nil
+	}
+
+	if claims.ID != "" && values[0] != nil {
+		return ErrRevokedToken
+	}
+	if len(values) > 1 && values[1] != nil {
+		cutoffValue, _ := values[1].(string)
+		cutoff, _ := strconv.ParseInt(cutoffValue, 10, 64)
+		if claims.IssuedAt == nil || claims.IssuedAt.Unix() <= cutoff {
+			return ErrRevokedToken
+		}
+	}
+
+	return nil
+}
+
+// newTokenID returns a random JWT ID
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"api-gateway-go/internal/config"
+)
+
+func TestNewAuthServiceSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      string
+		environment string
+		wantErr     error
+	}{
+		{"missing outside production", "", "development", nil},
+		{"missing in production", "", "production", ErrJWTNotConfigured},
+		{"default outside production", defaultJWTSecret, "development", nil},
+		{"default in production", defaultJWTSecret, "production", ErrInsecureJWTSecret},
+		{"short outside production", "short-secret", "development", nil},
+		{"short in production", "short-secret", "production", ErrInsecureJWTSecret},
+		{"strong in production", testJWTSecret, "production", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Environment: tt.environment}
+			cfg.Auth.JWTSecret = tt.secret
+
+			_, err := NewAuthService(cfg, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewAuthService() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"api-gateway-go/internal/config"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrAutoRecordUnavailable = errors.New("participant auto-record state unavailable")
+
+const (
+	// autoRecordKeyPrefix is the Redis key prefix for participant auto-record state
+	autoRecordKeyPrefix = "autorecord:"
+	// autoRecordStateTTL bounds how long the state of a participant who never left is kept
+	autoRecordStateTTL = 24 * time.Hour
+)
+
+// publishTrackScript stores a published track on a watched participant and
+// returns the participant's state, or nothing when the participant is not watched
+var publishTrackScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return {}
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return redis.call('HGETALL', KEYS[1])
+`)
+
+// ParticipantRecordState is the auto-record progress of one participant
+type ParticipantRecordState struct {
+	RecordType   string
+	VideoTrackID string
+	AudioTrackID string
+}
+
+// AutoRecordService keeps the participants of auto-recorded rooms whose
+// Track or TrackComposite recording waits for their tracks. The state lives in
+// Redis, keyed by room and identity, so participant_joined and track_published
+// may be processed by different gateway instances.
+type AutoRecordService struct {
+	redis *config.RedisManager
+}
+
+// NewAutoRecordService creates a new AutoRecordService
+func NewAutoRecordService(redis *config.RedisManager) *AutoRecordService {
+	return &AutoRecordService{redis: redis}
+}
+
+// Watch starts waiting for the tracks of a participant, replacing any earlier state
+func (s *AutoRecordService) Watch(ctx context.Context, room, identity, recordType string) error {
+	if s.redis == nil {
+		return ErrAutoRecordUnavailable
+	}
+
+	key := s.key(room, identity)
+	pipe := s.redis.StateClient().TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "recordType", recordType)
+	pipe.Expire(ctx, key, autoRecordStateTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// PublishTrack records a published camera or microphone track of a watched
+// participant. Returns nil when the participant is not watched.
+func (s *AutoRecordService) PublishTrack(ctx context.Context, room, identity string, source livekit.TrackSource, trackID string) (*ParticipantRecordState, error) {
+	if s.redis == nil {
+		return nil, nil
+	}
+
+	field := "audio"
+	if source == livekit.TrackSource_CAMERA {
+		field = "video"
+	}
+
+	values, err := publishTrackScript.Run(ctx, s.redis.StateClient(), []string{s.key(room, identity)}, field, trackID).StringSlice()
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	state := &ParticipantRecordState{}
+	for i := 0; i+1 < len(values); i += 2 {
+		switch values[i] {
+		case "recordType":
+			state.RecordType = values[i+1]
+		case "video":
+			state.VideoTrackID = values[i+1]
+		case "audio":
+			state.AudioTrackID = values[i+1]
+		}
+	}
+	return state, nil
+}
+
+// ClaimStart marks the recording of a participant started. Returns false when it already was.
+func (s *AutoRecordService) ClaimStart(ctx context.Context, room, identity string) (bool, error) {
+	if s.redis == nil {
+		return false, ErrAutoRecordUnavailable
+	}
+	return s.redis.StateClient().HSetNX(ctx, s.key(room, identity), "started", 1).Result()
+}
+
+// ReleaseStart lets a later track start the participant's recording again
+func (s *AutoRecordService) ReleaseStart(ctx context.Context, room, identity string) error {
+	if s.redis == nil {
+		return nil
+	}
+	return s.redis.StateClient().HDel(ctx, s.key(room, identity), "started").Err()
+}
+
+// Forget drops the state of a participant who left
+func (s *AutoRecordService) Forget(ctx context.Context, room, identity string) error {
+	if s.redis == nil {
+		return nil
+	}
+	return s.redis.StateClient().Del(ctx, s.key(room, identity)).Err()
+}
+
+func (s *AutoRecordService) key(room, identity string) string {
+	return autoRecordKeyPrefix + room + ":" + identity
+}
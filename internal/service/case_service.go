@@ -10,7 +10,8 @@ import (
 )
 
 var (
-	ErrCaseNotFound = errors.New("case not found")
+	ErrCaseNotFound  = errors.New("case not found")
+	ErrCaseLegalHold = errors.New("case is under legal hold")
 )
 
 // CreateCaseOptions holds options for creating a case
@@ -136,7 +137,14 @@ func (s *CaseService) GetRoomNameByCaseID(ctx context.Context, caseID, service i
 	return s.caseRepo.GetRoomName(ctx, caseID, service)
 }
 
-// DeleteCase deletes a case
+// DeleteCase deletes a case. A case under legal hold cannot be deleted, as that would lift the hold.
 func (s *CaseService) DeleteCase(ctx context.Context, id uint) error {
+	caseData, err := s.caseRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if caseData != nil && caseData.LegalHold == 1 {
+		return ErrCaseLegalHold
+	}
 	return s.caseRepo.Delete(ctx, id)
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"api-gateway-go/internal/config"
@@ -27,37 +28,45 @@ type CronJobInfo struct {
 
 // CrontabService handles cron jobs
 type CrontabService struct {
-	cron        *cron.Cron
-	roomService *RoomService
-	linkService *LinkService
-	livekitMgr  *config.LiveKitManager
-	cfg         *config.Config
-	status      *CronStatus
+	cron          *cron.Cron
+	roomService   *RoomService
+	linkService   *LinkService
+	recordService *RecordService
+	retention     *RecordRetentionService
+	livekitMgr    *config.LiveKitManager
+	cfg           *config.Config
+	status        *CronStatus
+	jobNames      map[cron.EntryID]string
+	mu            sync.RWMutex
+	lastReconcile *EgressReconcileReport
 }
 
 // NewCrontabService creates a new CrontabService
-func NewCrontabService(roomService *RoomService, linkService *LinkService, livekitMgr *config.LiveKitManager, cfg *config.Config) *CrontabService {
+func NewCrontabService(roomService *RoomService, linkService *LinkService, recordService *RecordService, retention *RecordRetentionService, livekitMgr *config.LiveKitManager, cfg *config.Config) *CrontabService {
 	// Create cron with Bangkok timezone
 	loc, _ := time.LoadLocation("Asia/Bangkok")
 	c := cron.New(cron.WithLocation(loc))
 
 	return &CrontabService{
-		cron:        c,
-		roomService: roomService,
-		linkService: linkService,
-		livekitMgr:  livekitMgr,
-		cfg:         cfg,
+		cron:          c,
+		roomService:   roomService,
+		linkService:   linkService,
+		recordService: recordService,
+		retention:     retention,
+		livekitMgr:    livekitMgr,
+		cfg:           cfg,
 		status: &CronStatus{
 			Running: false,
 			Jobs:    []CronJobInfo{},
 		},
+		jobNames: make(map[cron.EntryID]string),
 	}
 }
 
 // InitCronJobs initializes all cron jobs
 func (s *CrontabService) InitCronJobs() error {
 	// Room cleanup - every 30 minutes
-	_, err := s.cron.AddFunc("*/30 * * * *", func() {
+	err := s.addJob("Room Cleanup", "*/30 * * * *", func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
 
@@ -71,7 +80,7 @@ func (s *CrontabService) InitCronJobs() error {
 	}
 
 	// Link cleanup - every 30 minutes
-	_, err = s.cron.AddFunc("*/30 * * * *", func() {
+	err = s.addJob("Link Cleanup", "*/30 * * * *", func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
 
@@ -85,7 +94,7 @@ func (s *CrontabService) InitCronJobs() error {
 	}
 
 	// LiveKit health check - every 10 minutes
-	_, err = s.cron.AddFunc("*/10 * * * *", func() {
+	err = s.addJob("LiveKit Health Check", "*/10 * * * *", func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 		defer cancel()
 
@@ -100,6 +109,18 @@ func (s *CrontabService) InitCronJobs() error {
 		return err
 	}
 
+	// Recording reconciliation against LiveKit egress - every 5 minutes by default
+	err = s.addJob("Record Reconcile", s.cfg.RecordReconcile.Schedule, s.reconcileRecords)
+	if err != nil {
+		return err
+	}
+
+	// Recording retention - daily at 03:00 by default
+	err = s.addJob("Record Retention", s.cfg.RecordRetention.Schedule, s.applyRecordRetention)
+	if err != nil {
+		return err
+	}
+
 	// Start the cron scheduler
 	s.cron.Start()
 	s.status.Running = true
@@ -118,14 +139,14 @@ func (s *CrontabService) GetStatus() *CronStatus {
 	entries := s.cron.Entries()
 	s.status.Jobs = make([]CronJobInfo, len(entries))
 
-	jobNames := []string{"Room Cleanup", "Link Cleanup", "LiveKit Health Check"}
 	for i, entry := range entries {
-		name := "Unknown"
-		if i < len(jobNames) {
-			name = jobNames[i]
+		name, ok := s.jobNames[entry.ID]
+		if !ok {
+			name = "Unknown"
 		}
 		s.status.Jobs[i] = CronJobInfo{
 			Name:    name,
+			LastRun: entry.Prev,
 			NextRun: entry.Next,
 		}
 	}
@@ -133,6 +154,64 @@ func (s *CrontabService) GetStatus() *CronStatus {
 	return s.status
 }
 
+// addJob schedules a named cron job
+func (s *CrontabService) addJob(name, spec string, job func()) error {
+	id, err := s.cron.AddFunc(spec, job)
+	if err != nil {
+		return err
+	}
+	s.jobNames[id] = name
+	return nil
+}
+
+// reconcileRecords fixes recordings that drifted from LiveKit egress state,
+// e.g. after a missed egress_ended webhook, and keeps the report for /status
+func (s *CrontabService) reconcileRecords() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	log.Debug().Msg("Running record reconcile cron job")
+	report, err := s.recordService.ReconcileEgress(ctx, s.cfg.RecordReconcile.Grace)
+	if err != nil {
+		report.Error = err.Error()
+		log.Error().Err(err).Msg("Record reconcile cron job failed")
+	} else if report.Fixed > 0 || len(report.Discrepancies) > 0 {
+		log.Warn().
+			Int("fixed", report.Fixed).
+			Int("discrepancies", len(report.Discrepancies)).
+			Msg("Record reconcile found recordings out of sync with LiveKit")
+	}
+
+	s.mu.Lock()
+	s.lastReconcile = report
+	s.mu.Unlock()
+}
+
+// applyRecordRetention archives and deletes recordings under the per-service retention policies
+func (s *CrontabService) applyRecordRetention() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	log.Info().Msg("Running record retention cron job")
+	report, err := s.retention.Apply(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Record retention cron job failed")
+	}
+	log.Info().
+		Int("policies", report.Policies).
+		Int("archived", report.Archived).
+		Int("deleted", report.Deleted).
+		Int("failed", report.Failed).
+		Msg("Record retention cron job finished")
+}
+
+// LastRecordReconcile returns the report of the last recording reconciliation, nil before the first run
+func (s *CrontabService) LastRecordReconcile() *EgressReconcileReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastReconcile
+}
+
 // Stop stops the cron scheduler
 func (s *CrontabService) Stop() {
 	if s.cron != nil {
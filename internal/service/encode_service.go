@@ -0,0 +1,445 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Encode job statuses
+const (
+	EncodeStatusQueued   = "queued"
+	EncodeStatusEncoding = "encoding"
+	EncodeStatusDone     = "done"
+	EncodeStatusFailed   = "failed"
+)
+
+// record_media.encode values
+const (
+	recordEncodeWaiting = 0
+	recordEncodeFailed  = 2
+)
+
+const (
+	encodeJobBatchSize = 50
+	// encodeLookback limits which completed records the worker picks up on
+	// its own, so records from before the encode pipeline are left alone
+	encodeLookback = 24 * time.Hour
+)
+
+var (
+	ErrEncodeNotConfigured   = errors.New("recording encoding is not configured")
+	ErrEncodeJobNotFound     = errors.New("encode job not found")
+	ErrEncodeJobNotRetryable = errors.New("only failed encode jobs can be retried")
+	ErrEncodeJobNotEncoding  = errors.New("encode job is not encoding")
+	ErrEncodeCallbackToken   = errors.New("invalid encode callback token")
+	ErrInvalidEncodeCallback = errors.New("invalid encode callback")
+)
+
+// EncodeCallback is the result the encode service reports for a job
+type EncodeCallback struct {
+	Status    string
+	HLS       string
+	Thumbnail string
+	Error     string
+}
+
+// EncodeService submits completed recordings to the encode service (ENCODE_API)
+// for HLS transcoding and a thumbnail, and tracks each job through queued,
+// encoding, done and failed. Failed submissions and failed encodes are retried
+// with backoff until the job runs out of attempts. With ENCODE_API set empty
+// nothing is queued and the worker does not run.
+type EncodeService struct {
+	jobRepo    *repository.EncodeJobRepository
+	recordRepo *repository.RecordRepository
+	cfg        *config.Config
+	httpClient *http.Client
+	wake       chan struct{}
+	ticker     *time.Ticker
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewEncodeService creates a new EncodeService
+func NewEncodeService(jobRepo *repository.EncodeJobRepository, recordRepo *repository.RecordRepository, cfg *config.Config) *EncodeService {
+	return &EncodeService{
+		jobRepo:    jobRepo,
+		recordRepo: recordRepo,
+		cfg:        cfg,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+}
+
+// Enabled reports whether an encode service is configured
+func (s *EncodeService) Enabled() bool {
+	return s.cfg.EncodeAPI != ""
+}
+
+// Enqueue queues an encode job for a completed record. A record is only encoded once.
+func (s *EncodeService) Enqueue(ctx context.Context, record *models.RecordMedia) error {
+	if !s.Enabled() {
+		return ErrEncodeNotConfigured
+	}
+
+	token, err := newEncodeCallbackToken()
+	if err != nil {
+		return err
+	}
+
+	maxAttempts := s.cfg.Encode.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	created, err := s.jobRepo.Create(ctx, repository.CreateEncodeJobParams{
+		RecordID:      record.ID,
+		Room:          record.Room.String,
+		Source:        record.FilePath.String,
+		MaxAttempts:   maxAttempts,
+		CallbackToken: token,
+	})
+	if err != nil || !created {
+		return err
+	}
+
+	if err := s.recordRepo.UpdateEncodeStatus(ctx, record.ID, recordEncodeWaiting); err != nil {
+		log.Error().Err(err).Int("recordId", record.ID).Msg("Failed to update record encode status")
+	}
+	log.Info().Int("recordId", record.ID).Str("room", record.Room.String).Msg("Encode job queued")
+	return nil
+}
+
+// Wake asks the worker to pick up new recordings, e.g. after an egress completed
+func (s *EncodeService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// GetJob gets an encode job by ID
+func (s *EncodeService) GetJob(ctx context.Context, id uint) (*models.EncodeJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrEncodeJobNotFound
+	}
+	return job, nil
+}
+
+// ListJobs lists encode jobs
+func (s *EncodeService) ListJobs(ctx context.Context, filter repository.EncodeJobFilter, limit, offset int) ([]models.EncodeJob, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.jobRepo.List(ctx, filter, limit, offset)
+}
+
+// Retry requeues a failed job with a fresh attempt count
+func (s *EncodeService) Retry(ctx context.Context, id uint) (*models.EncodeJob, error) {
+	if !s.Enabled() {
+		return nil, ErrEncodeNotConfigured
+	}
+
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.jobRepo.Requeue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrEncodeJobNotRetryable
+	}
+
+	if err := s.recordRepo.UpdateEncodeStatus(ctx, job.RecordID, recordEncodeWaiting); err != nil {
+		log.Error().Err(err).Int("recordId", job.RecordID).Msg("Failed to update record encode status")
+	}
+	s.Wake()
+
+	return s.jobRepo.GetByID(ctx, id)
+}
+
+// HandleCallback records the result the encode service reports for a job.
+// The token must match the one sent with the job.
+func (s *EncodeService) HandleCallback(ctx context.Context, id uint, token string, callback EncodeCallback) (*models.EncodeJob, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(job.CallbackToken)) != 1 {
+		return nil, ErrEncodeCallbackToken
+	}
+
+	switch strings.ToLower(callback.Status) {
+	case EncodeStatusDone, "success", "complete", "completed":
+		if callback.HLS == "" {
+			return nil, ErrInvalidEncodeCallback
+		}
+		ok, err := s.jobRepo.MarkDone(ctx, id, callback.HLS, callback.Thumbnail)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrEncodeJobNotEncoding
+		}
+		if err := s.recordRepo.UpdateEncodeResult(ctx, job.RecordID, callback.HLS, callback.Thumbnail); err != nil {
+			return nil, err
+		}
+		log.Info().Uint("id", id).Int("recordId", job.RecordID).Msg("Encode job done")
+
+	case EncodeStatusFailed, "error":
+		reason := callback.Error
+		if reason == "" {
+			reason = "encode failed"
+		}
+		if err := s.fail(ctx, job, reason); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, ErrInvalidEncodeCallback
+	}
+
+	return s.jobRepo.GetByID(ctx, id)
+}
+
+// ProcessDue queues new recordings, fails jobs the encoder never reported
+// back on, and submits every job whose next attempt is due
+func (s *EncodeService) ProcessDue(ctx context.Context) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	records, err := s.jobRepo.GetUnqueuedRecords(ctx, time.Now().Add(-encodeLookback), encodeJobBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range records {
+		if err := s.Enqueue(ctx, &records[i]); err != nil {
+			log.Error().Err(err).Int("recordId", records[i].ID).Msg("Failed to queue encode job")
+		}
+	}
+
+	if s.cfg.Encode.Timeout > 0 {
+		ids, err := s.jobRepo.GetTimedOutIDs(ctx, time.Now().Add(-s.cfg.Encode.Timeout), encodeJobBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			job, err := s.jobRepo.GetByID(ctx, id)
+			if err != nil || job == nil {
+				continue
+			}
+			if err := s.fail(ctx, job, "no callback from encode service"); err != nil {
+				log.Error().Err(err).Uint("id", id).Msg("Failed to time out encode job")
+			}
+		}
+	}
+
+	ids, err := s.jobRepo.GetDueIDs(ctx, encodeJobBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.submit(ctx, id); err != nil {
+			log.Warn().Err(err).Uint("id", id).Msg("Encode job submission failed")
+		}
+	}
+
+	return nil
+}
+
+// submit claims a job and sends it to the encode service once
+func (s *EncodeService) submit(ctx context.Context, id uint) error {
+	claimed, err := s.jobRepo.Claim(ctx, id)
+	if err != nil || !claimed {
+		return err
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil || job == nil {
+		return err
+	}
+
+	encoderJobID, sendErr := s.send(ctx, job)
+	if sendErr != nil {
+		if err := s.fail(ctx, job, sendErr.Error()); err != nil {
+			return err
+		}
+		return sendErr
+	}
+
+	if err := s.jobRepo.MarkSubmitted(ctx, id, encoderJobID); err != nil {
+		return err
+	}
+	log.Info().Uint("id", id).Int("recordId", job.RecordID).Str("encoderJobId", encoderJobID).Msg("Encode job submitted")
+	return nil
+}
+
+// send posts a job to the encode service and returns the job ID it assigned, if any
+func (s *EncodeService) send(ctx context.Context, job *models.EncodeJob) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jobId":       job.ID,
+		"recordId":    job.RecordID,
+		"room":        job.Room.String,
+		"source":      job.Source,
+		"outputs":     []string{"hls", "thumbnail"},
+		"callbackUrl": fmt.Sprintf("%s/webhook/encode/%d", strings.TrimRight(s.cfg.APIURL, "/"), job.ID),
+		"token":       job.CallbackToken,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimRight(s.cfg.EncodeAPI, "/")+"/encode", bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("encode API error: %s", string(respBody))
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", nil
+	}
+	switch v := result["jobId"].(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", nil
+}
+
+// fail records a failed attempt. The job is queued again after a backoff,
+// or marked failed, along with its record, once it is out of attempts.
+func (s *EncodeService) fail(ctx context.Context, job *models.EncodeJob, reason string) error {
+	status := EncodeStatusQueued
+	if job.Attempts >= job.MaxAttempts {
+		status = EncodeStatusFailed
+	}
+
+	ok, err := s.jobRepo.MarkFailed(ctx, job.ID, status, reason, time.Now().Add(s.backoff(job.Attempts)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrEncodeJobNotEncoding
+	}
+
+	if status == EncodeStatusFailed {
+		if err := s.recordRepo.UpdateEncodeStatus(ctx, job.RecordID, recordEncodeFailed); err != nil {
+			log.Error().Err(err).Int("recordId", job.RecordID).Msg("Failed to update record encode status")
+		}
+		log.Error().Str("reason", reason).Uint("id", job.ID).Int("recordId", job.RecordID).Msg("Encode job failed")
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the next attempt
+func (s *EncodeService) backoff(attempts int) time.Duration {
+	delay := s.cfg.Encode.BackoffBase
+	if delay <= 0 {
+		delay = time.Minute
+	}
+
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if s.cfg.Encode.BackoffMax > 0 && delay >= s.cfg.Encode.BackoffMax {
+			return s.cfg.Encode.BackoffMax
+		}
+	}
+
+	return delay
+}
+
+// newEncodeCallbackToken returns the secret the encode service must send back with its callback
+func newEncodeCallbackToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Start starts the encode worker. It runs when woken and on every tick,
+// and only when ENCODE_API is not empty.
+func (s *EncodeService) Start() {
+	if !s.Enabled() {
+		log.Warn().Msg("ENCODE_API is empty, recordings will not be encoded to HLS or get thumbnails")
+		return
+	}
+
+	interval := s.cfg.Encode.Interval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	s.ticker = time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.wake:
+			case <-s.ticker.C:
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), interval*5)
+			if err := s.ProcessDue(ctx); err != nil {
+				log.Error().Err(err).Msg("Encode worker failed")
+			}
+			cancel()
+		}
+	}()
+
+	log.Info().Dur("interval", interval).Msg("Encode worker started")
+}
+
+// Stop stops the encode worker
+func (s *EncodeService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		if s.ticker != nil {
+			s.ticker.Stop()
+		}
+		log.Info().Msg("Encode worker stopped")
+	})
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultJWTSecret is the publicly known secret of earlier releases; it is refused in production
+const defaultJWTSecret = "default-secret-key"
+
+// minJWTSecretLength is the shortest HMAC secret accepted in production
+const minJWTSecretLength = 32
+
+var (
+	ErrNoSigningKey       = errors.New("no JWT signing key configured")
+	ErrJWTNotConfigured   = errors.New("JWT_SECRET or JWT_KEY_DIR must be set")
+	ErrInsecureJWTSecret  = errors.New("JWT_SECRET is the default or shorter than 32 bytes")
+	ErrSigningKeyNotFound = errors.New("JWT signing key not found")
+)
+
+// signingKey is one key of the key set. Keys loaded from a public key file
+// can only verify tokens; they are kept after rotation until old tokens expire.
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// keySet holds the asymmetric keys tokens are signed and verified with.
+// Rotation: add the new key file to the key directory on every instance, then
+// switch JWT_SIGNING_KEY_ID to it, and remove the old file once its tokens expire.
+type keySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// loadKeySet reads every .pem file in dir. The file name without extension
+// is the key ID. activeKID selects the signing key; it may be empty when the
+// directory holds a single private key.
+func loadKeySet(dir, activeKID string) (*keySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list JWT keys: %w", err)
+	}
+	sort.Strings(paths)
+
+	set := &keySet{keys: make(map[string]*signingKey)}
+	var privateKIDs []string
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := loadSigningKey(path, kid)
+		if err != nil {
+			return nil, err
+		}
+		set.keys[kid] = key
+		if key.private != nil {
+			privateKIDs = append(privateKIDs, kid)
+		}
+	}
+
+	if activeKID == "" && len(privateKIDs) == 1 {
+		activeKID = privateKIDs[0]
+	}
+	if activeKID == "" {
+		return nil, fmt.Errorf("%w: set JWT_SIGNING_KEY_ID to one of %v", ErrNoSigningKey, privateKIDs)
+	}
+
+	active, ok := set.keys[activeKID]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSigningKeyNotFound, activeKID)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+// loadSigningKey parses an RSA or Ed25519 private or public key from a PEM file
+func loadSigningKey(path, kid string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT key %s: %w", kid, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode JWT key %s: no PEM block", kid)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in JWT key %s", block.Type, kid)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT key %s: %w", kid, err)
+	}
+
+	key := &signingKey{kid: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T in JWT key %s", parsed, kid)
+	}
+
+	return key, nil
+}
+
+// jwks returns the public half of every key in the set
+func (s *keySet) jwks() JWKS {
+	kids := make([]string, 0, len(s.keys))
+	for kid := range s.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		key := s.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.method.Alg()}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
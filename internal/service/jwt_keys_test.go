@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"api-gateway-go/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// Test keys are generated once since RSA key generation is slow
+var (
+	testRSAKey     *rsa.PrivateKey
+	testEd25519Key ed25519.PrivateKey
+)
+
+func init() {
+	var err error
+	if testRSAKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		panic(err)
+	}
+	if _, testEd25519Key, err = ed25519.GenerateKey(rand.Reader); err != nil {
+		panic(err)
+	}
+}
+
+// writeKey writes a PEM file named kid.pem into dir
+func writeKey(t *testing.T, dir, kid, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeRSAPrivate(t *testing.T, dir, kid string) {
+	writeKey(t, dir, kid, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(testRSAKey))
+}
+
+func writeEd25519Private(t *testing.T, dir, kid string) {
+	der, err := x509.MarshalPKCS8PrivateKey(testEd25519Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, kid, "PRIVATE KEY", der)
+}
+
+func writeRSAPublic(t *testing.T, dir, kid string) {
+	der, err := x509.MarshalPKIXPublicKey(&testRSAKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, kid, "PUBLIC KEY", der)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string)
+		activeKID  string
+		wantActive string
+		wantAlg    string
+		wantKeys   int
+		wantErr    error
+	}{
+		{
+			name:       "single private key is active",
+			setup:      func(t *testing.T, dir string) { writeRSAPrivate(t, dir, "k1") },
+			wantActive: "k1",
+			wantAlg:    "RS256",
+			wantKeys:   1,
+		},
+		{
+			name: "active key selected by kid",
+			setup: func(t *testing.T, dir string) {
+				writeRSAPrivate(t, dir, "k1")
+				writeEd25519Private(t, dir, "k2")
+			},
+			activeKID:  "k2",
+			wantActive: "k2",
+			wantAlg:    "EdDSA",
+			wantKeys:   2,
+		},
+		{
+			name: "public keys stay for verification",
+			setup: func(t *testing.T, dir string) {
+				writeRSAPublic(t, dir, "old")
+				writeEd25519Private(t, dir, "new")
+			},
+			wantActive: "new",
+			wantAlg:    "EdDSA",
+			wantKeys:   2,
+		},
+		{
+			name: "several private keys need a kid",
+			setup: func(t *testing.T, dir string) {
+				writeRSAPrivate(t, dir, "k1")
+				writeEd25519Private(t, dir, "k2")
+			},
+			wantErr: ErrNoSigningKey,
+		},
+		{
+			name:    "empty directory",
+			setup:   func(t *testing.T, dir string) {},
+			wantErr: ErrNoSigningKey,
+		},
+		{
+			name:      "unknown kid",
+			setup:     func(t *testing.T, dir string) { writeRSAPrivate(t, dir, "k1") },
+			activeKID: "k9",
+			wantErr:   ErrSigningKeyNotFound,
+		},
+		{
+			name: "public key cannot sign",
+			setup: func(t *testing.T, dir string) {
+				writeRSAPublic(t, dir, "old")
+				writeEd25519Private(t, dir, "new")
+			},
+			activeKID: "old",
+			wantErr:   ErrSigningKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(t, dir)
+
+			set, err := loadKeySet(dir, tt.activeKID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("loadKeySet() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadKeySet() error = %v", err)
+			}
+			if set.active.kid != tt.wantActive || set.active.method.Alg() != tt.wantAlg {
+				t.Errorf("active key = %s/%s, want %s/%s", set.active.kid, set.active.method.Alg(), tt.wantActive, tt.wantAlg)
+			}
+			if len(set.keys) != tt.wantKeys {
+				t.Errorf("len(keys) = %d, want %d", len(set.keys), tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestLoadKeySetInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"no PEM block", []byte("not a key")},
+		{"unsupported block", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}})},
+		{"corrupt key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1, 2, 3}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "bad.pem"), tt.data, 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadKeySet(dir, ""); err == nil {
+				t.Fatal("loadKeySet() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestKeySetJWKS(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAPublic(t, dir, "a-rsa")
+	writeEd25519Private(t, dir, "b-ed")
+
+	set, err := loadKeySet(dir, "")
+	if err != nil {
+		t.Fatalf("loadKeySet() error = %v", err)
+	}
+
+	jwks := set.jwks()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("len(jwks.Keys) = %d, want 2", len(jwks.Keys))
+	}
+
+	rsaJWK, edJWK := jwks.Keys[0], jwks.Keys[1]
+	if rsaJWK.Kid != "a-rsa" || rsaJWK.Kty != "RSA" || rsaJWK.Alg != "RS256" || rsaJWK.Use != "sig" {
+		t.Errorf("RSA JWK = %+v", rsaJWK)
+	}
+	n, _ := base64.RawURLEncoding.DecodeString(rsaJWK.N)
+	e, _ := base64.RawURLEncoding.DecodeString(rsaJWK.E)
+	if new(big.Int).SetBytes(n).Cmp(testRSAKey.N) != 0 || int(new(big.Int).SetBytes(e).Int64()) != testRSAKey.E {
+		t.Errorf("RSA JWK modulus or exponent does not match the key")
+	}
+
+	if edJWK.Kid != "b-ed" || edJWK.Kty != "OKP" || edJWK.Crv != "Ed25519" || edJWK.Alg != "EdDSA" {
+		t.Errorf("Ed25519 JWK = %+v", edJWK)
+	}
+	x, _ := base64.RawURLEncoding.DecodeString(edJWK.X)
+	if !ed25519.PublicKey(x).Equal(testEd25519Key.Public()) {
+		t.Errorf("Ed25519 JWK x does not match the key")
+	}
+	if edJWK.N != "" || rsaJWK.X != "" {
+		t.Errorf("JWKs carry fields of the other key type")
+	}
+}
+
+func TestVerifyTokenKeyMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAPrivate(t, dir, "rsa")
+	writeEd25519Private(t, dir, "ed")
+
+	cfg := &config.Config{}
+	cfg.Auth.JWTKeyDir = dir
+	cfg.Auth.JWTSigningKeyID = "rsa"
+	cfg.Auth.JWTSecret = testJWTSecret
+	s, err := NewAuthService(cfg, nil)
+	if err != nil {
+		t.Fatalf("NewAuthService() error = %v", err)
+	}
+
+	claims := func() *Claims {
+		return &Claims{UserName: "alice", RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}}
+	}
+	signed := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims())
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		str, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return str
+	}
+
+	active, err := s.sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"active key", active, false},
+		{"rotated key", signed(jwt.SigningMethodEdDSA, "ed", testEd25519Key), false},
+		{"secret without kid", signed(jwt.SigningMethodHS256, "", []byte(testJWTSecret)), false},
+		{"unknown kid", signed(jwt.SigningMethodRS256, "gone", testRSAKey), true},
+		{"alg does not match kid", signed(jwt.SigningMethodEdDSA, "rsa", testEd25519Key), true},
+		{"secret with kid", signed(jwt.SigningMethodHS256, "rsa", []byte(testJWTSecret)), true},
+		{"asymmetric key without kid", signed(jwt.SigningMethodRS256, "", testRSAKey), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.VerifyToken(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
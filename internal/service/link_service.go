@@ -3,13 +3,18 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"api-gateway-go/internal/config"
 	"api-gateway-go/internal/models"
 	"api-gateway-go/internal/repository"
+	"api-gateway-go/internal/sms"
 	"api-gateway-go/pkg/utils"
+
+	"github.com/rs/zerolog/log"
 )
 
 var (
@@ -17,6 +22,44 @@ var (
 	ErrLinkExpired     = errors.New("link has expired")
 	ErrLinkDisabled    = errors.New("link is disabled")
 	ErrOneTimeLinkUsed = errors.New("one-time link already used")
+	ErrMobileRequired  = errors.New("mobile number required to send SMS")
+	ErrLinkEnabled     = errors.New("link is already enabled")
+	ErrInvalidExpiry   = errors.New("expiry must be in the future")
+
+	ErrLinkUserNameRequired = errors.New("user name required")
+	ErrLinkPasswordRequired = errors.New("link password required")
+	ErrLinkPasswordInvalid  = errors.New("invalid link password")
+)
+
+// Link redemption error codes returned to API clients
+const (
+	LinkCodeNotFound         = "LINK_NOT_FOUND"
+	LinkCodeDisabled         = "LINK_DISABLED"
+	LinkCodeExpired          = "LINK_EXPIRED"
+	LinkCodeUsed             = "LINK_ALREADY_USED"
+	LinkCodeUserNameRequired = "LINK_USERNAME_REQUIRED"
+	LinkCodePasswordRequired = "LINK_PASSWORD_REQUIRED"
+	LinkCodePasswordInvalid  = "LINK_PASSWORD_INVALID"
+)
+
+// Link lifecycle statuses written to usage_status_log
+const (
+	LinkStatusExpired  = "link-expired"
+	LinkStatusRevoked  = "link-revoked"
+	LinkStatusExtended = "link-extended"
+	LinkStatusEnabled  = "link-enabled"
+	LinkStatusRedeemed = "link-redeemed"
+)
+
+// linkExpireBatchSize is the number of expired links disabled per query
+const linkExpireBatchSize = 500
+
+// SMS status values stored in link_connect.sms
+const (
+	LinkSMSNotSent = 0
+	LinkSMSSent    = 1
+	LinkSMSFailed  = 2
+	LinkSMSQueued  = 3
 )
 
 // CreateLinkOptions holds options for creating a link
@@ -32,28 +75,100 @@ type CreateLinkOptions struct {
 	OneTimeLink           int
 	UserAgent             string
 	DaysExpired           int
+	Service               int
+	SendSMS               bool
+	Locale                string
+	CreatedBy             string
+}
+
+// RedeemLinkOptions holds the credentials presented when joining with a link
+type RedeemLinkOptions struct {
+	LinkID   string
+	Password string
+	UserName string
+}
+
+// CreateLinkResult holds the created link and the outcome of its invitation SMS
+type CreateLinkResult struct {
+	*models.LinkConnect
+	SMSSent      bool   `json:"smsSent"`
+	SMSStatus    string `json:"smsStatus,omitempty"`
+	SMSOutboxID  uint   `json:"smsOutboxId,omitempty"`
+	SMSError     string `json:"smsError,omitempty"`
+	SMSErrorCode string `json:"smsErrorCode,omitempty"`
 }
 
 // LinkService handles link business logic
 type LinkService struct {
-	linkRepo *repository.LinkRepository
-	roomRepo *repository.RoomRepository
-	cfg      *config.Config
+	linkRepo    *repository.LinkRepository
+	roomRepo    *repository.RoomRepository
+	serviceRepo *repository.ServiceRepository
+	usageLog    *repository.UsageLogRepository
+	smsOutbox   *SmsOutboxService
+	smsTemplate *SmsTemplateService
+	smsLimiter  *SmsLimiterService
+	cfg         *config.Config
 }
 
 // NewLinkService creates a new LinkService
-func NewLinkService(linkRepo *repository.LinkRepository, roomRepo *repository.RoomRepository, cfg *config.Config) *LinkService {
+func NewLinkService(linkRepo *repository.LinkRepository, roomRepo *repository.RoomRepository, serviceRepo *repository.ServiceRepository, usageLog *repository.UsageLogRepository, smsOutbox *SmsOutboxService, smsTemplate *SmsTemplateService, smsLimiter *SmsLimiterService, cfg *config.Config) *LinkService {
 	return &LinkService{
-		linkRepo: linkRepo,
-		roomRepo: roomRepo,
-		cfg:      cfg,
+		linkRepo:    linkRepo,
+		roomRepo:    roomRepo,
+		serviceRepo: serviceRepo,
+		usageLog:    usageLog,
+		smsOutbox:   smsOutbox,
+		smsTemplate: smsTemplate,
+		smsLimiter:  smsLimiter,
+		cfg:         cfg,
 	}
 }
 
-// CreateLink creates a new link
-func (s *LinkService) CreateLink(ctx context.Context, opts CreateLinkOptions) (*models.LinkConnect, error) {
+// CreateLink creates a new link and optionally sends the invitation SMS
+func (s *LinkService) CreateLink(ctx context.Context, opts CreateLinkOptions) (*CreateLinkResult, error) {
+	if opts.SendSMS && opts.Mobile == "" {
+		return nil, ErrMobileRequired
+	}
+	if opts.RequirePassword == 1 && opts.Password == "" {
+		return nil, ErrLinkPasswordRequired
+	}
+
+	// A link to a room belongs to the room's service. The lookup is tenant-scoped,
+	// so scoped callers cannot link to rooms of another service.
+	serviceID := opts.Service
+	if opts.Room != "" {
+		room, err := s.roomRepo.GetByRoom(ctx, opts.Room)
+		if err != nil {
+			return nil, err
+		}
+		if room == nil {
+			return nil, ErrRoomNotFound
+		}
+		if room.Service.Valid {
+			serviceID = int(room.Service.Int32)
+		} else if serviceID == 0 {
+			// Resolve service from the room's case when the room has none
+			serviceID, _ = s.roomRepo.GetServiceID(ctx, opts.Room)
+		}
+	}
+
+	smsStatus := LinkSMSNotSent
+	if opts.SendSMS {
+		smsStatus = LinkSMSFailed
+
+		// Reject blocked or rate limited numbers before creating the link
+		if s.smsLimiter != nil {
+			if err := s.smsLimiter.Check(ctx, opts.Mobile, serviceID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Generate link ID
-	linkID := utils.GenerateLinkID(s.cfg.CustomCharset)
+	newLinkID := func() string {
+		return utils.GenerateLinkID(s.cfg.CustomCharset, s.cfg.LinkIDLength)
+	}
+	linkID := newLinkID()
 	now := utils.FormatDateTimeNow()
 
 	// Calculate expiration
@@ -63,8 +178,20 @@ func (s *LinkService) CreateLink(ctx context.Context, opts CreateLinkOptions) (*
 	}
 	expiredAt := utils.AddDays(time.Now(), daysExpired)
 
+	// Store link passwords as bcrypt hashes
+	password := opts.Password
+	if password != "" {
+		hash, err := utils.HashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		password = hash
+	}
+
 	// Create link in database
 	params := repository.CreateLinkParams{
+		Service:               serviceID,
+		SMS:                   smsStatus,
 		Share:                 opts.Share,
 		Mobile:                opts.Mobile,
 		LinkID:                linkID,
@@ -73,19 +200,168 @@ func (s *LinkService) CreateLink(ctx context.Context, opts CreateLinkOptions) (*
 		RequireJoinPermission: opts.RequireJoinPermission,
 		RequireUserName:       opts.RequireUserName,
 		RequirePassword:       opts.RequirePassword,
-		Password:              opts.Password,
+		Password:              password,
 		OneTimeLink:           opts.OneTimeLink,
 		UserAgent:             opts.UserAgent,
 		DtmCreated:            now,
 		DtmExpired:            expiredAt,
+		NewLinkID:             newLinkID,
+	}
+
+	_, err := s.linkRepo.Create(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+	linkID = params.LinkID
+
+	result := &CreateLinkResult{}
+	if opts.SendSMS {
+		msg, err := s.sendInvitationSMS(ctx, serviceID, linkID, expiredAt, opts)
+		if err != nil {
+			log.Error().Err(err).Str("linkID", linkID).Msg("Failed to queue link invitation SMS")
+			if err := s.linkRepo.UpdateSMSDeliveryStatus(ctx, linkID, sms.DeliveryFailed); err != nil {
+				log.Error().Err(err).Str("linkID", linkID).Msg("Failed to update link SMS delivery status")
+			}
+			result.SMSError = err.Error()
+			result.SMSErrorCode = SMSErrorCode(err)
+		} else {
+			result.SMSOutboxID = msg.ID
+			result.SMSStatus = msg.Status
+			result.SMSSent = msg.Status == SMSStatusSent
+			if !result.SMSSent {
+				result.SMSError = utils.NullStringValue(msg.LastError)
+			}
+		}
 	}
 
-	_, err := s.linkRepo.Create(ctx, params)
+	link, err := s.GetLinkDetail(ctx, linkID, "")
 	if err != nil {
 		return nil, err
 	}
+	result.LinkConnect = link
+
+	return result, nil
+}
+
+// sendInvitationSMS composes the invitation message from the service settings and sends it through the outbox
+func (s *LinkService) sendInvitationSMS(ctx context.Context, serviceID int, linkID, expiredAt string, opts CreateLinkOptions) (*models.SmsOutbox, error) {
+	if s.smsOutbox == nil {
+		return nil, ErrSMSDisabled
+	}
+
+	var svc *models.Services
+	if serviceID > 0 {
+		var err error
+		svc, err = s.serviceRepo.GetByID(ctx, serviceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	domainIndex := s.nextDomainIndex(ctx, linkID)
+	sender, prefix, url := s.composeInvitationMessage(svc, opts.UserType, linkID, domainIndex)
+	messageType := smsMessageType(opts.UserType)
+
+	// Prefer a configured template, fall back to the service prefix
+	message := strings.TrimSpace(prefix + " " + url)
+	if s.smsTemplate != nil {
+		expiry := expiredAt
+		if t, err := utils.ParseDateTime(expiredAt); err == nil {
+			expiry = t.Format("02/01/2006 15:04")
+		}
+
+		rendered, err := s.smsTemplate.Render(ctx, serviceID, messageType, opts.Locale, map[string]string{
+			"linkUrl":   url,
+			"room":      opts.Room,
+			"agentName": opts.CreatedBy,
+			"expiry":    expiry,
+			"prefix":    prefix,
+			"mobile":    opts.Mobile,
+		})
+		if err != nil {
+			log.Warn().Err(err).Int("service", serviceID).Msg("Failed to render SMS template, using service prefix")
+		} else if rendered != nil {
+			message = rendered.Message
+		}
+	}
+
+	provider := ""
+	if svc != nil {
+		provider = utils.NullStringValue(svc.SmsProvider)
+	}
+
+	return s.smsOutbox.Send(ctx, EnqueueSMSOptions{
+		Service:     serviceID,
+		LinkID:      linkID,
+		Room:        opts.Room,
+		Mobile:      opts.Mobile,
+		Sender:      sender,
+		Provider:    provider,
+		MessageType: messageType,
+		Message:     message,
+		CreatedBy:   opts.CreatedBy,
+	})
+}
+
+// composeInvitationMessage returns the SMS sender, prefix and link URL
+// using the prefix and domain list configured on the service
+func (s *LinkService) composeInvitationMessage(svc *models.Services, userType, linkID string, domainIndex int) (string, string, string) {
+	if svc == nil {
+		return "", "", s.linkURL(s.cfg.APIURL, linkID)
+	}
+
+	prefix := utils.NullStringValue(svc.PrefixTextVideoSMS)
+	domains := utils.NullStringValue(svc.DomainsVideo)
+	switch userType {
+	case "hls":
+		prefix = utils.NullStringValue(svc.PrefixHLSRecordVideoSMS)
+	case "location":
+		prefix = utils.NullStringValue(svc.PrefixTextLocationSMS)
+		domains = utils.NullStringValue(svc.DomainsLocation)
+	}
+
+	url := s.linkURL(pickDomain(domains, domainIndex, s.cfg.APIURL), linkID)
+
+	return utils.NullStringValue(svc.SmsSenderName), prefix, url
+}
+
+// linkURL builds the public URL of a link
+func (s *LinkService) linkURL(domain, linkID string) string {
+	return strings.TrimRight(domain, "/") + "/" + linkID
+}
+
+// nextDomainIndex advances the round-robin domain index for a link
+func (s *LinkService) nextDomainIndex(ctx context.Context, linkID string) int {
+	lastIndex, err := s.linkRepo.GetLastDomainIndex(ctx, linkID)
+	if err != nil {
+		lastIndex = 0
+	}
+
+	index := lastIndex + 1
+	if err := s.linkRepo.UpdateDomainIndex(ctx, linkID, index); err != nil {
+		log.Error().Err(err).Str("linkID", linkID).Msg("Failed to update domain index")
+	}
+
+	return index
+}
+
+// pickDomain selects a domain from a comma-separated list by index
+func pickDomain(domains string, index int, fallback string) string {
+	var list []string
+	for _, d := range strings.Split(domains, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			list = append(list, d)
+		}
+	}
+
+	if len(list) == 0 {
+		return fallback
+	}
+	if index < 0 {
+		index = 0
+	}
 
-	return s.GetLinkDetail(ctx, linkID, "")
+	return list[index%len(list)]
 }
 
 // GetLinkDetail gets link details by linkID
@@ -143,18 +419,152 @@ func (s *LinkService) UpdatePatientLocation(ctx context.Context, linkID string,
 	return s.linkRepo.UpdatePatientLocation(ctx, linkID, lat, lng)
 }
 
-// CheckAndUpdateOneTimeLink checks and updates one-time link status
-func (s *LinkService) CheckAndUpdateOneTimeLink(ctx context.Context, linkID string) error {
-	status, err := s.linkRepo.GetOneTimeLinkStatus(ctx, linkID)
+// RedeemLink validates a link before a participant joins with it and claims
+// one-time links, so they can only be used once
+func (s *LinkService) RedeemLink(ctx context.Context, opts RedeemLinkOptions) (*models.LinkConnect, error) {
+	link, err := s.CheckLink(ctx, opts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Claim one-time links last so a wrong password does not use them up
+	if err := s.claimOneTimeLink(ctx, link, opts.UserName); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// CheckLink validates a link without using it up. The link must exist, be
+// enabled and unexpired, and carry a user name and the correct password when required.
+func (s *LinkService) CheckLink(ctx context.Context, opts RedeemLinkOptions) (*models.LinkConnect, error) {
+	link, err := s.getUsableLink(ctx, opts.LinkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.NullIntValue(link.RequireUserName) == 1 && strings.TrimSpace(opts.UserName) == "" {
+		return nil, ErrLinkUserNameRequired
 	}
 
-	if status == 1 {
+	if utils.NullIntValue(link.RequirePassword) == 1 {
+		if opts.Password == "" {
+			return nil, ErrLinkPasswordRequired
+		}
+		if err := s.verifyLinkPassword(ctx, link, opts.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	return link, nil
+}
+
+// ClaimLink uses up a link checked earlier, e.g. once a waiting guest is let in.
+// The link is checked again, as it may have expired or been disabled meanwhile.
+func (s *LinkService) ClaimLink(ctx context.Context, linkID, userName string) (*models.LinkConnect, error) {
+	link, err := s.getUsableLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.claimOneTimeLink(ctx, link, userName); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// getUsableLink gets a link that is enabled, unexpired and, for a one-time link, not yet used
+func (s *LinkService) getUsableLink(ctx context.Context, linkID string) (*models.LinkConnect, error) {
+	link, err := s.getLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.NullIntValue(link.Enabled) == 0 {
+		return nil, ErrLinkDisabled
+	}
+	if link.DtmExpired.Valid && !link.DtmExpired.Time.After(time.Now()) {
+		return nil, ErrLinkExpired
+	}
+	if utils.NullIntValue(link.OneTimeLink) == 1 && link.DtmRedeemed.Valid {
+		return nil, ErrOneTimeLinkUsed
+	}
+
+	return link, nil
+}
+
+// claimOneTimeLink atomically marks a one-time link redeemed. Other links are left alone.
+func (s *LinkService) claimOneTimeLink(ctx context.Context, link *models.LinkConnect, userName string) error {
+	if utils.NullIntValue(link.OneTimeLink) != 1 {
+		return nil
+	}
+
+	ok, err := s.linkRepo.Redeem(ctx, utils.NullStringValue(link.LinkID), utils.FormatDateTimeNow())
+	if err != nil {
+		return err
+	}
+	if !ok {
 		return ErrOneTimeLinkUsed
 	}
+	s.logLinkTransition(ctx, link, LinkStatusRedeemed, userName, nil)
+
+	return nil
+}
+
+// verifyLinkPassword checks a link password and upgrades a legacy plaintext password to a hash
+func (s *LinkService) verifyLinkPassword(ctx context.Context, link *models.LinkConnect, password string) error {
+	stored := utils.NullStringValue(link.Password)
+	if stored == "" || !utils.VerifyPassword(stored, password) {
+		return ErrLinkPasswordInvalid
+	}
+
+	if !utils.IsPasswordHash(stored) {
+		hash, err := utils.HashPassword(password)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to hash link password")
+			return nil
+		}
+		if err := s.linkRepo.UpdatePassword(ctx, utils.NullStringValue(link.LinkID), hash); err != nil {
+			log.Error().Err(err).Str("linkID", utils.NullStringValue(link.LinkID)).Msg("Failed to upgrade link password")
+		}
+	}
+
+	return nil
+}
 
-	return s.linkRepo.UpdateOneTimeLink(ctx, linkID, 1)
+// JoinUserType returns the participant type for a redeemed link.
+// Admin and host rights are only granted by links created as admin links.
+func JoinUserType(link *models.LinkConnect, requested string) string {
+	if userType := utils.NullStringValue(link.UserType); userType != "" {
+		return userType
+	}
+	if (requested == "admin" || requested == "host") && utils.NullStringValue(link.IsAdmin) != "1" {
+		return "guest"
+	}
+	return requested
+}
+
+// LinkErrorCode returns the API error code for a link redemption error, or "" for other errors
+func LinkErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrLinkNotFound):
+		return LinkCodeNotFound
+	case errors.Is(err, ErrLinkDisabled):
+		return LinkCodeDisabled
+	case errors.Is(err, ErrLinkExpired):
+		return LinkCodeExpired
+	case errors.Is(err, ErrOneTimeLinkUsed):
+		return LinkCodeUsed
+	case errors.Is(err, ErrLinkUserNameRequired):
+		return LinkCodeUserNameRequired
+	case errors.Is(err, ErrLinkPasswordRequired):
+		return LinkCodePasswordRequired
+	case errors.Is(err, ErrLinkPasswordInvalid):
+		return LinkCodePasswordInvalid
+	default:
+		return ""
+	}
 }
 
 // GetLinkIDList gets links by room and mobile
@@ -202,13 +612,173 @@ func (s *LinkService) UpdateLinkUserName(ctx context.Context, linkID, userName s
 	return s.linkRepo.UpdateLinkUserName(ctx, linkID, userName)
 }
 
-// AutoLinkExpiredClose closes expired links
+// AutoLinkExpiredClose disables links whose expiry has passed
 func (s *LinkService) AutoLinkExpiredClose(ctx context.Context) error {
-	// This would typically query for expired links and disable them
-	// For now, we'll implement a simple version
+	now := utils.FormatDateTimeNow()
+	closed := 0
+
+	for {
+		links, err := s.linkRepo.GetExpiredEnabled(ctx, now, linkExpireBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range links {
+			link := &links[i]
+			linkID := utils.NullStringValue(link.LinkID)
+
+			ok, err := s.linkRepo.DisableExpired(ctx, linkID, now)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+
+			closed++
+			s.logLinkTransition(ctx, link, LinkStatusExpired, "system", map[string]interface{}{
+				"dtmExpired": utils.FormatDateTime(link.DtmExpired.Time),
+			})
+		}
+
+		if len(links) < linkExpireBatchSize {
+			break
+		}
+	}
+
+	if closed > 0 {
+		log.Info().Int("count", closed).Msg("Disabled expired links")
+	}
+
 	return nil
 }
 
+// RevokeLink disables a link before it expires
+func (s *LinkService) RevokeLink(ctx context.Context, linkID, reason, actor string) (*models.LinkConnect, error) {
+	link, err := s.getLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if utils.NullIntValue(link.Enabled) == 0 {
+		return nil, ErrLinkDisabled
+	}
+
+	if err := s.linkRepo.SetEnabled(ctx, linkID, 0); err != nil {
+		return nil, err
+	}
+
+	s.logLinkTransition(ctx, link, LinkStatusRevoked, actor, map[string]interface{}{
+		"reason": reason,
+	})
+
+	return s.getLink(ctx, linkID)
+}
+
+// ExtendLink moves the expiry of a link to days from now, or to expiredAt when given.
+// A disabled link stays disabled until it is re-enabled.
+func (s *LinkService) ExtendLink(ctx context.Context, linkID string, days int, expiredAt, actor string) (*models.LinkConnect, error) {
+	link, err := s.getLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if days <= 0 {
+		days = s.cfg.RoomDayDefaultTimeout
+	}
+
+	newExpiry := time.Now().AddDate(0, 0, days)
+	if expiredAt != "" {
+		newExpiry, err = utils.ParseDateTime(expiredAt)
+		if err != nil {
+			return nil, ErrInvalidExpiry
+		}
+	}
+	if !newExpiry.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	if err := s.linkRepo.UpdateExpired(ctx, linkID, utils.FormatDateTime(newExpiry)); err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"dtmExpired": utils.FormatDateTime(newExpiry),
+	}
+	if link.DtmExpired.Valid {
+		data["previousExpired"] = utils.FormatDateTime(link.DtmExpired.Time)
+	}
+	s.logLinkTransition(ctx, link, LinkStatusExtended, actor, data)
+
+	return s.getLink(ctx, linkID)
+}
+
+// EnableLink re-enables a revoked or expired link. An expired link must be extended first.
+func (s *LinkService) EnableLink(ctx context.Context, linkID, actor string) (*models.LinkConnect, error) {
+	link, err := s.getLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if utils.NullIntValue(link.Enabled) == 1 {
+		return nil, ErrLinkEnabled
+	}
+	if link.DtmExpired.Valid && !link.DtmExpired.Time.After(time.Now()) {
+		return nil, ErrLinkExpired
+	}
+
+	if err := s.linkRepo.SetEnabled(ctx, linkID, 1); err != nil {
+		return nil, err
+	}
+
+	s.logLinkTransition(ctx, link, LinkStatusEnabled, actor, nil)
+
+	return s.getLink(ctx, linkID)
+}
+
+// getLink gets a link by linkID, returning ErrLinkNotFound when it does not exist
+func (s *LinkService) getLink(ctx context.Context, linkID string) (*models.LinkConnect, error) {
+	if linkID == "" {
+		return nil, ErrLinkNotFound
+	}
+
+	link, err := s.linkRepo.GetByLinkID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, ErrLinkNotFound
+	}
+
+	return link, nil
+}
+
+// logLinkTransition records a link lifecycle change in usage_status_log
+func (s *LinkService) logLinkTransition(ctx context.Context, link *models.LinkConnect, status, actor string, data map[string]interface{}) {
+	if s.usageLog == nil {
+		return
+	}
+
+	var dataJSON string
+	if len(data) > 0 {
+		if b, err := json.Marshal(data); err == nil {
+			dataJSON = string(b)
+		}
+	}
+
+	_, err := s.usageLog.AddStatusLog(ctx, repository.AddStatusLogParams{
+		LinkID:   utils.NullStringValue(link.LinkID),
+		LinkType: utils.NullStringValue(link.LinkType),
+		Mobile:   link.Mobile,
+		Room:     utils.NullStringValue(link.Room),
+		UserName: actor,
+		UserType: utils.NullStringValue(link.UserType),
+		Status:   status,
+		Data:     dataJSON,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("linkID", utils.NullStringValue(link.LinkID)).Str("status", status).Msg("Failed to log link transition")
+	}
+}
+
 // GetDomain gets domain for a service
 func (s *LinkService) GetDomain(ctx context.Context, service int, sender, linkType, linkID string) (string, error) {
 	// Get last domain index
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// roomsConnector serves room_conference lookups by name from a fixed set of
+// rooms, honouring the tenant condition, and fails every other statement
+type roomsConnector struct {
+	rooms map[string]int64
+}
+
+func (c roomsConnector) Connect(context.Context) (driver.Conn, error) { return roomsConn(c), nil }
+func (c roomsConnector) Driver() driver.Driver                        { return nil }
+
+type roomsConn roomsConnector
+
+func (c roomsConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("unexpected statement: %s", query)
+}
+func (c roomsConn) Close() error              { return nil }
+func (c roomsConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported") }
+
+func (c roomsConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "FROM room_conference WHERE room = ?") {
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+
+	name, _ := args[0].Value.(string)
+	service, ok := c.rooms[name]
+	if ok && strings.Contains(query, "AND service = ?") && args[1].Value != service {
+		ok = false
+	}
+	if strings.Contains(query, "AND 1 = 0") {
+		ok = false
+	}
+
+	rows := &roomRows{}
+	if ok {
+		rows.values = [][]driver.Value{{int64(1), name, service}}
+	}
+	return rows, nil
+}
+
+type roomRows struct {
+	values [][]driver.Value
+}
+
+func (r *roomRows) Columns() []string { return []string{"id", "room", "service"} }
+func (r *roomRows) Close() error      { return nil }
+
+func (r *roomRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestCreateLinkRejectsRoomOfAnotherService(t *testing.T) {
+	db := sqlx.NewDb(sql.OpenDB(roomsConnector{rooms: map[string]int64{"room-2": 2}}), "mysql")
+	t.Cleanup(func() { db.Close() })
+
+	roomRepo := repository.NewRoomRepository(db)
+	s := NewLinkService(repository.NewLinkRepository(db), roomRepo, nil, nil, nil, nil, nil, &config.Config{})
+
+	// The owning tenant sees the room
+	room, err := roomRepo.GetByRoom(repository.WithTenant(context.Background(), repository.Tenant{Service: 2}), "room-2")
+	if err != nil || room == nil {
+		t.Fatalf("GetByRoom() for service 2 = %v, %v, want the room", room, err)
+	}
+
+	tests := []struct {
+		name   string
+		tenant repository.Tenant
+		opts   CreateLinkOptions
+	}{
+		{"room of another service", repository.Tenant{Service: 1}, CreateLinkOptions{Room: "room-2", UserType: "user"}},
+		{"requested service ignored", repository.Tenant{Service: 1}, CreateLinkOptions{Room: "room-2", UserType: "user", Service: 2}},
+		{"hls link", repository.Tenant{Service: 1}, CreateLinkOptions{Room: "room-2", UserType: "hls"}},
+		{"tenant without service", repository.Tenant{}, CreateLinkOptions{Room: "room-2", UserType: "user"}},
+		{"unknown room", repository.Tenant{Service: 2}, CreateLinkOptions{Room: "room-9", UserType: "user"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := repository.WithTenant(context.Background(), tt.tenant)
+			result, err := s.CreateLink(ctx, tt.opts)
+			if !errors.Is(err, ErrRoomNotFound) {
+				t.Fatalf("CreateLink() = %v, %v, want %v", result, err, ErrRoomNotFound)
+			}
+		})
+	}
+}
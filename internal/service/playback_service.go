@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/pkg/utils"
+)
+
+var (
+	ErrPlaybackNotConfigured = errors.New("recording playback is not configured")
+	ErrPlaybackNotHLSLink    = errors.New("link is not an HLS playback link")
+	ErrPlaybackSignature     = errors.New("invalid or expired playback URL")
+	ErrPlaybackNotFound      = errors.New("recording file not found")
+)
+
+// playlistURIAttr matches URI attributes of playlist tags such as EXT-X-KEY and EXT-X-MAP
+var playlistURIAttr = regexp.MustCompile(`URI="([^"]*)"`)
+
+// PlaybackRecording is an encoded recording with signed playback URLs
+type PlaybackRecording struct {
+	ID          int        `json:"id"`
+	Identity    string     `json:"identity,omitempty"`
+	RecordType  string     `json:"recordType,omitempty"`
+	Duration    int        `json:"duration"`
+	StartRecord *time.Time `json:"startRecord,omitempty"`
+	Playlist    string     `json:"playlist"`
+	Thumbnail   string     `json:"thumbnail,omitempty"`
+}
+
+// PlaybackResult holds the recordings an HLS link gives access to
+type PlaybackResult struct {
+	Room       string              `json:"room"`
+	ExpiresAt  time.Time           `json:"expiresAt"`
+	Recordings []PlaybackRecording `json:"recordings"`
+}
+
+// PlaybackFile is a recording file ready to be served
+type PlaybackFile struct {
+	Path        string
+	ContentType string
+	// Content holds a rewritten playlist; other files are served from Path
+	Content []byte
+}
+
+// PlaybackService turns HLS links into signed, expiring URLs for the encoded
+// recordings of their room, and checks those URLs when files are requested.
+// Files are served from RECORD_PATH under /playback/:recordId/<file>, and
+// playlists are rewritten so every segment they reference is signed as well.
+type PlaybackService struct {
+	linkService *LinkService
+	recordRepo  *repository.RecordRepository
+	cfg         *config.Config
+}
+
+// NewPlaybackService creates a new PlaybackService
+func NewPlaybackService(linkService *LinkService, recordRepo *repository.RecordRepository, cfg *config.Config) *PlaybackService {
+	return &PlaybackService{
+		linkService: linkService,
+		recordRepo:  recordRepo,
+		cfg:         cfg,
+	}
+}
+
+// ResolveLink redeems an HLS link and returns signed playback URLs for the encoded recordings of its room
+func (s *PlaybackService) ResolveLink(ctx context.Context, opts RedeemLinkOptions) (*PlaybackResult, error) {
+	if s.secret() == "" {
+		return nil, ErrPlaybackNotConfigured
+	}
+
+	link, err := s.linkService.RedeemLink(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if utils.NullStringValue(link.UserType) != "hls" {
+		return nil, ErrPlaybackNotHLSLink
+	}
+
+	room := utils.NullStringValue(link.Room)
+	records, err := s.recordRepo.GetEncodedByRoom(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().Add(s.ttl())
+	// The URLs must not outlive the link itself
+	if link.DtmExpired.Valid && link.DtmExpired.Time.Before(expires) {
+		expires = link.DtmExpired.Time
+	}
+
+	result := &PlaybackResult{
+		Room:       room,
+		ExpiresAt:  expires,
+		Recordings: make([]PlaybackRecording, 0, len(records)),
+	}
+	apiURL := strings.TrimRight(s.cfg.APIURL, "/")
+
+	for _, record := range records {
+		recording := PlaybackRecording{
+			ID:         record.ID,
+			Identity:   record.Identity.String,
+			RecordType: record.RecordType.String,
+			Duration:   int(record.Duration.Int32),
+			Playlist:   apiURL + s.signedURL(record.ID, s.relativePath(record.HLS.String), expires),
+		}
+		if record.StartRecord.Valid {
+			recording.StartRecord = &record.StartRecord.Time
+		}
+		if record.Thumbnail.Valid && record.Thumbnail.String != "" {
+			recording.Thumbnail = apiURL + s.signedURL(record.ID, s.relativePath(record.Thumbnail.String), expires)
+		}
+		result.Recordings = append(result.Recordings, recording)
+	}
+
+	return result, nil
+}
+
+// OpenFile checks a signed playback URL and returns the file it points to.
+// Playlists are rewritten so the files they reference carry the same expiry.
+func (s *PlaybackService) OpenFile(ctx context.Context, recordID int, file, expiresParam, signature string) (*PlaybackFile, error) {
+	if s.secret() == "" {
+		return nil, ErrPlaybackNotConfigured
+	}
+
+	file = path.Clean("/" + file)[1:]
+	expiresUnix, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil || file == "" {
+		return nil, ErrPlaybackSignature
+	}
+	expires := time.Unix(expiresUnix, 0)
+	if !time.Now().Before(expires) || !hmac.Equal([]byte(signature), []byte(s.sign(recordID, file, expiresUnix))) {
+		return nil, ErrPlaybackSignature
+	}
+
+	record, err := s.recordRepo.GetByID(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.Status.String != "complete" {
+		return nil, ErrPlaybackNotFound
+	}
+
+	fullPath := filepath.Join(s.cfg.RecordPath, filepath.FromSlash(file))
+	info, err := os.Stat(fullPath)
+	if err != nil || info.IsDir() {
+		return nil, ErrPlaybackNotFound
+	}
+
+	playback := &PlaybackFile{Path: fullPath, ContentType: playbackContentType(file)}
+	if strings.HasSuffix(strings.ToLower(file), ".m3u8") {
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read playlist: %w", err)
+		}
+		playback.Content = s.rewritePlaylist(content, recordID, path.Dir(file), expires)
+	}
+
+	return playback, nil
+}
+
+// rewritePlaylist replaces the relative URIs of a playlist with signed playback URLs
+func (s *PlaybackService) rewritePlaylist(content []byte, recordID int, dir string, expires time.Time) []byte {
+	var out bytes.Buffer
+	signURI := func(uri string) string {
+		if uri == "" || strings.Contains(uri, "://") || strings.HasPrefix(uri, "/") {
+			return uri
+		}
+		if i := strings.IndexAny(uri, "?#"); i >= 0 {
+			uri = uri[:i]
+		}
+		return s.signedURL(recordID, path.Join(dir, uri), expires)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case strings.HasPrefix(line, "#"):
+			line = playlistURIAttr.ReplaceAllStringFunc(line, func(attr string) string {
+				return `URI="` + signURI(playlistURIAttr.FindStringSubmatch(attr)[1]) + `"`
+			})
+		case strings.TrimSpace(line) != "":
+			line = signURI(strings.TrimSpace(line))
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+
+	return out.Bytes()
+}
+
+// signedURL returns the root-relative signed URL of a file of a recording
+func (s *PlaybackService) signedURL(recordID int, file string, expires time.Time) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("sig", s.sign(recordID, file, expires.Unix()))
+
+	escaped := make([]string, 0)
+	for _, part := range strings.Split(file, "/") {
+		escaped = append(escaped, url.PathEscape(part))
+	}
+
+	return fmt.Sprintf("/playback/%d/%s?%s", recordID, strings.Join(escaped, "/"), query.Encode())
+}
+
+// sign returns the signature of a recording file path and expiry
+func (s *PlaybackService) sign(recordID int, file string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.secret()))
+	fmt.Fprintf(mac, "%d\n%s\n%d", recordID, file, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// relativePath returns a stored HLS or thumbnail path relative to RECORD_PATH
+func (s *PlaybackService) relativePath(stored string) string {
+	return recordRelativePath(s.cfg.RecordPath, stored)
+}
+
+// recordRelativePath returns a stored recording file path relative to root.
+// Paths may be stored with or without the root in front; the result never leaves root.
+func recordRelativePath(root, stored string) string {
+	if stored == "" {
+		return ""
+	}
+	stored = filepath.ToSlash(stored)
+	root = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(root)), "./")
+	stored = strings.TrimPrefix(strings.TrimPrefix(stored, "./"), root+"/")
+	return path.Clean("/" + stored)[1:]
+}
+
+// secret returns the key playback URLs are signed with. Playback stays
+// disabled without PLAYBACK_SECRET so URLs are never signed with the JWT secret.
+func (s *PlaybackService) secret() string {
+	return s.cfg.Playback.Secret
+}
+
+// ttl returns how long signed playback URLs stay valid
+func (s *PlaybackService) ttl() time.Duration {
+	if s.cfg.Playback.TTL > 0 {
+		return s.cfg.Playback.TTL
+	}
+	return time.Hour
+}
+
+// playbackContentType returns the content type of an HLS file
+func playbackContentType(file string) string {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".ts":
+		return "video/mp2t"
+	case ".m4s", ".mp4":
+		return "video/mp4"
+	case ".aac":
+		return "audio/aac"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "application/octet-stream"
+	}
+}
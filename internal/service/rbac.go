@@ -0,0 +1,158 @@
+package service
+
+// Role is the access role carried by an API token
+type Role string
+
+// Roles, from most to least privileged
+const (
+	RoleAdmin      Role = "admin"
+	RoleSupervisor Role = "supervisor"
+	RoleAgent      Role = "agent"
+	RoleDevice     Role = "device"
+	RoleGuest      Role = "guest"
+)
+
+// Permission is an action on an API resource
+type Permission string
+
+// Permissions checked by the API routes
+const (
+	// PermPublic marks a route that needs no token
+	PermPublic Permission = ""
+	// PermAuthenticated allows any valid token regardless of role
+	PermAuthenticated Permission = "authenticated"
+
+	PermRoomRead          Permission = "room:read"
+	PermRoomManage        Permission = "room:manage"
+	PermParticipantManage Permission = "participant:manage"
+	PermLinkRead          Permission = "link:read"
+	PermLinkManage        Permission = "link:manage"
+	PermWaitingRoomManage Permission = "waitingroom:manage"
+	PermChatManage        Permission = "chat:manage"
+	PermNotificationRead  Permission = "notification:read"
+	PermNotificationWrite Permission = "notification:write"
+	PermNotificationAdmin Permission = "notification:admin"
+	PermRecordRead        Permission = "record:read"
+	PermRecordManage      Permission = "record:manage"
+	PermRecordAdmin       Permission = "record:admin"
+	PermCarWrite          Permission = "car:write"
+	PermCarAdmin          Permission = "car:admin"
+	PermCaseRead          Permission = "case:read"
+	PermCaseWrite         Permission = "case:write"
+	PermCaseAdmin         Permission = "case:admin"
+	PermRadioLocation     Permission = "radio:location"
+	PermRadioManage       Permission = "radio:manage"
+	PermUploadAdmin       Permission = "upload:admin"
+	PermStatsRead         Permission = "stats:read"
+	PermSMSRead           Permission = "sms:read"
+	PermSMSManage         Permission = "sms:manage"
+	PermStaffManage       Permission = "staff:manage"
+	PermAPIKeyManage      Permission = "apikey:manage"
+	PermTokenCreate       Permission = "token:create"
+	PermMetricsRead       Permission = "metrics:read"
+	PermSystemManage      Permission = "system:manage"
+)
+
+// agentPermissions are granted to agents and every role above them
+var agentPermissions = []Permission{
+	PermRoomRead,
+	PermRoomManage,
+	PermParticipantManage,
+	PermLinkRead,
+	PermLinkManage,
+	PermWaitingRoomManage,
+	PermNotificationRead,
+	PermNotificationWrite,
+	PermRecordRead,
+	PermRecordManage,
+	PermCarWrite,
+	PermCaseRead,
+	PermCaseWrite,
+	PermStatsRead,
+	PermRadioLocation,
+	PermSMSRead,
+}
+
+// supervisorPermissions are granted to supervisors and admins on top of agentPermissions
+var supervisorPermissions = []Permission{
+	PermChatManage,
+	PermNotificationAdmin,
+	PermRecordAdmin,
+	PermCarAdmin,
+	PermCaseAdmin,
+	PermRadioManage,
+	PermUploadAdmin,
+	PermSMSManage,
+}
+
+// rolePermissions is the permission set of each role
+var rolePermissions = map[Role]map[Permission]bool{
+	RoleAdmin:      permissionSet(agentPermissions, supervisorPermissions, []Permission{PermStaffManage, PermAPIKeyManage, PermTokenCreate, PermMetricsRead, PermSystemManage}),
+	RoleSupervisor: permissionSet(agentPermissions, supervisorPermissions),
+	RoleAgent:      permissionSet(agentPermissions),
+	RoleDevice:     permissionSet([]Permission{PermCarWrite, PermRadioLocation}),
+	RoleGuest:      permissionSet(),
+}
+
+func permissionSet(groups ...[]Permission) map[Permission]bool {
+	set := make(map[Permission]bool)
+	for _, group := range groups {
+		for _, perm := range group {
+			set[perm] = true
+		}
+	}
+	return set
+}
+
+// ParseRole returns the role with the given name, or false if there is none
+func ParseRole(name string) (Role, bool) {
+	role := Role(name)
+	_, ok := rolePermissions[role]
+	return role, ok
+}
+
+// ParsePermission returns the permission with the given name, or false if no role can be granted it
+func ParsePermission(name string) (Permission, bool) {
+	perm := Permission(name)
+	return perm, rolePermissions[RoleAdmin][perm]
+}
+
+// HasPermission reports whether the role is granted the permission
+func (r Role) HasPermission(perm Permission) bool {
+	if perm == PermPublic || perm == PermAuthenticated {
+		return true
+	}
+	return rolePermissions[r][perm]
+}
+
+// RoleFromClaims returns the role of a token. Tokens without a role, such as
+// room tokens, are guests unless they were issued to a device.
+func RoleFromClaims(claims *Claims) Role {
+	if claims == nil {
+		return RoleGuest
+	}
+	if role, ok := ParseRole(claims.Role); ok {
+		return role
+	}
+	if claims.Role == "" && claims.UserType == string(RoleDevice) {
+		return RoleDevice
+	}
+	return RoleGuest
+}
+
+// ClaimsHavePermission reports whether a request is granted the permission.
+// API keys hold exactly their scopes; tokens hold the permissions of their role.
+func ClaimsHavePermission(claims *Claims, perm Permission) bool {
+	if claims == nil || claims.UserType != UserTypeAPIKey {
+		return RoleFromClaims(claims).HasPermission(perm)
+	}
+	if perm == PermPublic || perm == PermAuthenticated {
+		return true
+	}
+	for _, scope := range claims.Scopes {
+		if Permission(scope) == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package service
+
+import "testing"
+
+func TestRoleFromClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   Role
+	}{
+		{"nil claims", nil, RoleGuest},
+		{"admin", &Claims{Role: "admin"}, RoleAdmin},
+		{"supervisor", &Claims{Role: "supervisor"}, RoleSupervisor},
+		{"agent", &Claims{Role: "agent", UserType: "device"}, RoleAgent},
+		{"room token", &Claims{UserType: "user"}, RoleGuest},
+		{"device without role", &Claims{UserType: "device"}, RoleDevice},
+		{"unknown role", &Claims{Role: "owner"}, RoleGuest},
+		{"unknown role on device", &Claims{Role: "owner", UserType: "device"}, RoleGuest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoleFromClaims(tt.claims); got != tt.want {
+				t.Errorf("RoleFromClaims() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimsHavePermission(t *testing.T) {
+	apiKey := &Claims{
+		UserType: UserTypeAPIKey,
+		Role:     "admin",
+		Scopes:   []string{string(PermRoomRead), string(PermSMSRead)},
+	}
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		perm   Permission
+		want   bool
+	}{
+		{"nil claims public", nil, PermPublic, true},
+		{"nil claims authenticated", nil, PermAuthenticated, true},
+		{"nil claims room read", nil, PermRoomRead, false},
+		{"guest room read", &Claims{UserType: "user"}, PermRoomRead, false},
+		{"agent room read", &Claims{Role: "agent"}, PermRoomRead, true},
+		{"agent chat manage", &Claims{Role: "agent"}, PermChatManage, false},
+		{"supervisor chat manage", &Claims{Role: "supervisor"}, PermChatManage, true},
+		{"supervisor staff manage", &Claims{Role: "supervisor"}, PermStaffManage, false},
+		{"admin staff manage", &Claims{Role: "admin"}, PermStaffManage, true},
+		{"device car write", &Claims{UserType: "device"}, PermCarWrite, true},
+		{"device radio location", &Claims{UserType: "device"}, PermRadioLocation, true},
+		{"device room read", &Claims{UserType: "device"}, PermRoomRead, false},
+		{"api key scope", apiKey, PermRoomRead, true},
+		{"api key other scope", apiKey, PermSMSRead, true},
+		{"api key ignores role", apiKey, PermStaffManage, false},
+		{"api key authenticated", apiKey, PermAuthenticated, true},
+		{"api key without scopes", &Claims{UserType: UserTypeAPIKey}, PermRoomRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClaimsHavePermission(tt.claims, tt.perm); got != tt.want {
+				t.Errorf("ClaimsHavePermission(%q) = %v, want %v", tt.perm, got, tt.want)
+			}
+		})
+	}
+}
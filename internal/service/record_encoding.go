@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"api-gateway-go/internal/config"
+
+	"github.com/livekit/protocol/livekit"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	ErrInvalidRecordType      = errors.New("invalid record type")
+	ErrInvalidEncodingOptions = errors.New("invalid encoding options")
+	ErrInvalidRecordFileType  = errors.New("invalid record file type")
+	ErrOGGRequiresAudioOnly   = errors.New("OGG recordings must be audio-only")
+)
+
+// Record file types
+const (
+	RecordFileTypeMP4 = "MP4"
+	RecordFileTypeOGG = "OGG"
+)
+
+// recordEncoding holds the resolved output settings of a recording
+type recordEncoding struct {
+	egress    config.EgressEncoding
+	fileType  livekit.EncodedFileType
+	extension string
+}
+
+// resolveRecordEncoding maps the recordType, encodingOptionsPreset, layout and
+// file type of a recording to LiveKit egress settings
+func resolveRecordEncoding(recordType, encodingOptions, layout, fileType string) (*recordEncoding, error) {
+	switch recordType {
+	case RecordTypeRoomCompositeVideoAudio, RecordTypeRoomCompositeAudio, RecordTypeTrackComposite, RecordTypeTrack:
+	default:
+		return nil, ErrInvalidRecordType
+	}
+	audioOnly := recordType == RecordTypeRoomCompositeAudio
+
+	preset, advanced, err := parseEncodingOptions(encodingOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := &recordEncoding{
+		egress: config.EgressEncoding{
+			AudioOnly: audioOnly,
+			Layout:    layout,
+			Preset:    preset,
+			Advanced:  advanced,
+		},
+	}
+
+	switch strings.ToUpper(fileType) {
+	case "":
+		if audioOnly {
+			encoding.fileType, encoding.extension = livekit.EncodedFileType_OGG, ".ogg"
+		} else {
+			encoding.fileType, encoding.extension = livekit.EncodedFileType_MP4, ".mp4"
+		}
+	case RecordFileTypeMP4:
+		encoding.fileType, encoding.extension = livekit.EncodedFileType_MP4, ".mp4"
+	case RecordFileTypeOGG:
+		if !audioOnly {
+			return nil, ErrOGGRequiresAudioOnly
+		}
+		encoding.fileType, encoding.extension = livekit.EncodedFileType_OGG, ".ogg"
+	default:
+		return nil, ErrInvalidRecordFileType
+	}
+
+	return encoding, nil
+}
+
+// parseEncodingOptions parses an encodingOptionsPreset value: either the name
+// of a LiveKit preset such as H264_1080P_30, or custom encoding options as
+// JSON, e.g. {"width":1280,"height":720,"framerate":30,"videoBitrate":2000}.
+// An empty value uses the LiveKit default preset.
+func parseEncodingOptions(value string) (livekit.EncodingOptionsPreset, *livekit.EncodingOptions, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return livekit.EncodingOptionsPreset_H264_720P_30, nil, nil
+	}
+
+	if strings.HasPrefix(value, "{") {
+		var options livekit.EncodingOptions
+		if err := protojson.Unmarshal([]byte(value), &options); err != nil {
+			return 0, nil, errors.Join(ErrInvalidEncodingOptions, err)
+		}
+		return 0, &options, nil
+	}
+
+	preset, ok := livekit.EncodingOptionsPreset_value[strings.ToUpper(value)]
+	if !ok {
+		return 0, nil, ErrInvalidEncodingOptions
+	}
+	return livekit.EncodingOptionsPreset(preset), nil, nil
+}
+
+// ValidateRecordSettings checks the recording settings of a room. An empty record type is allowed.
+func ValidateRecordSettings(recordType, encodingOptions, fileType string) error {
+	if recordType == "" {
+		recordType = RecordTypeRoomCompositeVideoAudio
+	}
+	_, err := resolveRecordEncoding(recordType, encodingOptions, "", fileType)
+	return err
+}
+
+// IsRecordSettingsError reports whether an error comes from invalid recording settings
+func IsRecordSettingsError(err error) bool {
+	return errors.Is(err, ErrInvalidRecordType) ||
+		errors.Is(err, ErrInvalidEncodingOptions) ||
+		errors.Is(err, ErrInvalidRecordFileType) ||
+		errors.Is(err, ErrOGGRequiresAudioOnly) ||
+		errors.Is(err, ErrRecordTrack) ||
+		errors.Is(err, ErrRecordComposite)
+}
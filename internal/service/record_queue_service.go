@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrRecordQueueNotFound       = errors.New("queued recording not found")
+	ErrRecordQueueNotCancellable = errors.New("only queued recordings can be cancelled")
+)
+
+// recordQueueStaleAfter is how long a request may stay claimed before it is queued again
+const recordQueueStaleAfter = 5 * time.Minute
+
+// RecordRequestOptions holds options for requesting a recording
+type RecordRequestOptions struct {
+	StartRecordOptions
+	Priority  int
+	CreatedBy string
+}
+
+// RecordRequestResult holds either the egress of a recording that started
+// right away or the queue entry of one waiting for an egress slot
+type RecordRequestResult struct {
+	Egress *livekit.EgressInfo `json:"egress,omitempty"`
+	Queued *models.RecordQueue `json:"queued,omitempty"`
+}
+
+// RecordQueueService schedules recordings on the EGRESS_LIMIT egress slots.
+// Requests past the limit wait in record_queue and start, highest priority
+// first, when egress_ended frees a slot or the worker finds one free.
+type RecordQueueService struct {
+	queueRepo     *repository.RecordQueueRepository
+	recordService *RecordService
+	cfg           *config.Config
+	mu            sync.Mutex
+	wake          chan struct{}
+	ticker        *time.Ticker
+	done          chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewRecordQueueService creates a new RecordQueueService
+func NewRecordQueueService(queueRepo *repository.RecordQueueRepository, recordService *RecordService, cfg *config.Config) *RecordQueueService {
+	return &RecordQueueService{
+		queueRepo:     queueRepo,
+		recordService: recordService,
+		cfg:           cfg,
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+}
+
+// Request starts a recording when an egress slot is free and nothing is
+// waiting, and queues it otherwise. A request matching one already queued
+// for the same room, participant and record type returns that entry.
+func (s *RecordQueueService) Request(ctx context.Context, opts RecordRequestOptions) (*RecordRequestResult, error) {
+	if err := s.recordService.ValidateRecord(ctx, &opts.StartRecordOptions); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	waiting, err := s.queueRepo.CountQueued(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if waiting == 0 {
+		info, err := s.recordService.StartRecord(ctx, opts.StartRecordOptions)
+		if err == nil {
+			return &RecordRequestResult{Egress: info}, nil
+		}
+		if !errors.Is(err, ErrEgressLimit) {
+			return nil, err
+		}
+	}
+
+	existing, err := s.queueRepo.GetQueued(ctx, opts.Room, opts.Identity, opts.RecordType)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return &RecordRequestResult{Queued: existing}, nil
+	}
+
+	options, err := json.Marshal(opts.StartRecordOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.queueRepo.Create(ctx, repository.CreateRecordQueueParams{
+		Room:       opts.Room,
+		Identity:   opts.Identity,
+		RecordType: opts.RecordType,
+		Options:    string(options),
+		Priority:   opts.Priority,
+		CreatedBy:  opts.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.queueRepo.GetByID(ctx, uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("room", opts.Room).Str("identity", opts.Identity).Int("priority", opts.Priority).Msg("Recording queued until an egress slot frees")
+	return &RecordRequestResult{Queued: entry}, nil
+}
+
+// ListQueued lists waiting recordings in the order they will start
+func (s *RecordQueueService) ListQueued(ctx context.Context) ([]models.RecordQueue, error) {
+	return s.queueRepo.ListQueued(ctx)
+}
+
+// Cancel cancels a waiting recording
+func (s *RecordQueueService) Cancel(ctx context.Context, id uint) (*models.RecordQueue, error) {
+	entry, err := s.queueRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrRecordQueueNotFound
+	}
+
+	ok, err := s.queueRepo.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrRecordQueueNotCancellable
+	}
+
+	return s.queueRepo.GetByID(ctx, id)
+}
+
+// CancelRoom cancels the waiting recordings of a room, or of one participant when identity is set
+func (s *RecordQueueService) CancelRoom(ctx context.Context, room, identity string) {
+	cancelled, err := s.queueRepo.CancelByRoom(ctx, room, identity)
+	if err != nil {
+		log.Error().Err(err).Str("room", room).Msg("Failed to cancel queued recordings")
+		return
+	}
+	if cancelled > 0 {
+		log.Info().Int64("count", cancelled).Str("room", room).Str("identity", identity).Msg("Cancelled queued recordings")
+	}
+}
+
+// Wake asks the worker to start queued recordings, e.g. after an egress ended
+func (s *RecordQueueService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Dispatch starts queued recordings while egress slots are free
+func (s *RecordQueueService) Dispatch(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		available, err := s.recordService.CheckEgressAvailable(ctx)
+		if err != nil || !available {
+			return err
+		}
+
+		entry, err := s.queueRepo.GetNextQueued(ctx)
+		if err != nil || entry == nil {
+			return err
+		}
+
+		claimed, err := s.queueRepo.Claim(ctx, entry.ID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		s.start(ctx, entry)
+	}
+}
+
+// start starts a claimed request and records the outcome
+func (s *RecordQueueService) start(ctx context.Context, entry *models.RecordQueue) {
+	var opts StartRecordOptions
+	if err := json.Unmarshal([]byte(entry.Options), &opts); err != nil {
+		_ = s.queueRepo.MarkFailed(ctx, entry.ID, err.Error())
+		return
+	}
+
+	info, err := s.recordService.StartRecord(ctx, opts)
+	if errors.Is(err, ErrEgressLimit) {
+		_ = s.queueRepo.Requeue(ctx, entry.ID)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Uint("id", entry.ID).Str("room", entry.Room).Msg("Failed to start queued recording")
+		if markErr := s.queueRepo.MarkFailed(ctx, entry.ID, err.Error()); markErr != nil {
+			log.Error().Err(markErr).Uint("id", entry.ID).Msg("Failed to mark queued recording failed")
+		}
+		return
+	}
+
+	if err := s.queueRepo.MarkStarted(ctx, entry.ID, info.EgressId); err != nil {
+		log.Error().Err(err).Uint("id", entry.ID).Msg("Failed to mark queued recording started")
+	}
+	log.Info().Uint("id", entry.ID).Str("room", entry.Room).Str("egressId", info.EgressId).Msg("Started queued recording")
+}
+
+// Reconcile releases requests stuck while starting, then starts what fits in
+// the free slots. Records of ended egresses are closed by the crontab reconciler.
+func (s *RecordQueueService) Reconcile(ctx context.Context) error {
+	released, err := s.queueRepo.ReleaseStale(ctx, time.Now().Add(-recordQueueStaleAfter))
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		log.Warn().Int64("count", released).Msg("Released stale queued recordings")
+	}
+
+	return s.Dispatch(ctx)
+}
+
+// Start starts the queue worker. It dispatches when woken and reconciles on every tick.
+func (s *RecordQueueService) Start() {
+	interval := s.cfg.RecordQueue.Interval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	s.ticker = time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.wake:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := s.Dispatch(ctx); err != nil {
+					log.Error().Err(err).Msg("Record queue dispatch failed")
+				}
+				cancel()
+			case <-s.ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := s.Reconcile(ctx); err != nil {
+					log.Error().Err(err).Msg("Record queue worker failed")
+				}
+				cancel()
+			}
+		}
+	}()
+
+	log.Info().Dur("interval", interval).Msg("Record queue worker started")
+}
+
+// Stop stops the queue worker
+func (s *RecordQueueService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		if s.ticker != nil {
+			s.ticker.Stop()
+		}
+		log.Info().Msg("Record queue worker stopped")
+	})
+}
@@ -0,0 +1,474 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Record audit actions
+const (
+	RecordAuditDelete           = "delete"
+	RecordAuditDeleteFailed     = "delete_failed"
+	RecordAuditArchive          = "archive"
+	RecordAuditLegalHold        = "legal_hold"
+	RecordAuditLegalHoldRelease = "legal_hold_release"
+)
+
+const (
+	recordRetentionBatchSize = 200
+	// recordRetentionActor is the actor recorded for deletions and archives made by the cron job
+	recordRetentionActor = "retention"
+)
+
+var (
+	ErrRetentionPolicyNotFound = errors.New("retention policy not found")
+	ErrInvalidRetentionPolicy  = errors.New("retention days must not be negative, and recordings must be archived before they are deleted")
+	ErrRecordOnLegalHold       = errors.New("recording is under legal hold")
+	ErrRecordActive            = errors.New("recording is still in progress")
+)
+
+// RetentionPolicyOptions holds options for setting the retention policy of a service
+type RetentionPolicyOptions struct {
+	Service     int
+	RetainDays  int
+	ArchiveDays int
+	Enabled     bool
+	UpdatedBy   string
+}
+
+// RetentionReport is the result of a retention run
+type RetentionReport struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Policies  int       `json:"policies"`
+	Archived  int       `json:"archived"`
+	Deleted   int       `json:"deleted"`
+	Failed    int       `json:"failed"`
+}
+
+// RecordRetentionService applies per-service retention policies to recordings.
+// Recordings older than archiveDays move from RECORD_PATH to RECORD_ARCHIVE_PATH,
+// and recordings older than retainDays are deleted with their files and rows.
+// Recordings of a case under legal hold are never archived or deleted, and
+// every deletion, archive and hold change is written to record_audit.
+type RecordRetentionService struct {
+	policyRepo    *repository.RetentionPolicyRepository
+	recordRepo    *repository.RecordRepository
+	encodeJobRepo *repository.EncodeJobRepository
+	auditRepo     *repository.RecordAuditRepository
+	caseRepo      *repository.CaseRepository
+	roomRepo      *repository.RoomRepository
+	cfg           *config.Config
+}
+
+// NewRecordRetentionService creates a new RecordRetentionService
+func NewRecordRetentionService(
+	policyRepo *repository.RetentionPolicyRepository,
+	recordRepo *repository.RecordRepository,
+	encodeJobRepo *repository.EncodeJobRepository,
+	auditRepo *repository.RecordAuditRepository,
+	caseRepo *repository.CaseRepository,
+	roomRepo *repository.RoomRepository,
+	cfg *config.Config,
+) *RecordRetentionService {
+	return &RecordRetentionService{
+		policyRepo:    policyRepo,
+		recordRepo:    recordRepo,
+		encodeJobRepo: encodeJobRepo,
+		auditRepo:     auditRepo,
+		caseRepo:      caseRepo,
+		roomRepo:      roomRepo,
+		cfg:           cfg,
+	}
+}
+
+// ListPolicies lists the retention policies
+func (s *RecordRetentionService) ListPolicies(ctx context.Context) ([]models.RetentionPolicy, error) {
+	return s.policyRepo.List(ctx)
+}
+
+// SetPolicy creates or replaces the retention policy of a service. A zero
+// retainDays keeps recordings forever and a zero archiveDays never archives them.
+func (s *RecordRetentionService) SetPolicy(ctx context.Context, opts RetentionPolicyOptions) (*models.RetentionPolicy, error) {
+	if opts.RetainDays < 0 || opts.ArchiveDays < 0 ||
+		(opts.RetainDays > 0 && opts.ArchiveDays > 0 && opts.ArchiveDays >= opts.RetainDays) {
+		return nil, ErrInvalidRetentionPolicy
+	}
+
+	err := s.policyRepo.Upsert(ctx, repository.UpsertRetentionPolicyParams{
+		Service:     opts.Service,
+		RetainDays:  opts.RetainDays,
+		ArchiveDays: opts.ArchiveDays,
+		Enabled:     opts.Enabled,
+		UpdatedBy:   opts.UpdatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.policyRepo.GetByService(ctx, opts.Service)
+}
+
+// DeletePolicy removes the retention policy of a service, so its recordings are kept
+func (s *RecordRetentionService) DeletePolicy(ctx context.Context, service int) error {
+	ok, err := s.policyRepo.Delete(ctx, service)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRetentionPolicyNotFound
+	}
+	return nil
+}
+
+// ListAudit lists audit entries
+func (s *RecordRetentionService) ListAudit(ctx context.Context, filter repository.RecordAuditFilter, limit, offset int) ([]models.RecordAudit, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.auditRepo.List(ctx, filter, limit, offset)
+}
+
+// SetLegalHold places a case under legal hold, which blocks archiving and
+// deleting the recordings of its room, or releases the hold
+func (s *RecordRetentionService) SetLegalHold(ctx context.Context, id uint, hold bool, reason, actor string) (*models.CaseData, error) {
+	caseData, err := s.caseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if caseData == nil {
+		return nil, ErrCaseNotFound
+	}
+
+	if err := s.caseRepo.SetLegalHold(ctx, id, hold, reason, actor); err != nil {
+		return nil, err
+	}
+
+	action := RecordAuditLegalHold
+	if !hold {
+		action = RecordAuditLegalHoldRelease
+	}
+	if err := s.auditRepo.Create(ctx, repository.CreateRecordAuditParams{
+		Action:  action,
+		CaseID:  caseData.CaseID,
+		Service: int(caseData.Service.Int32),
+		Reason:  reason,
+		Actor:   actor,
+	}); err != nil {
+		log.Error().Err(err).Uint("id", id).Msg("Failed to audit case legal hold")
+	}
+
+	return s.caseRepo.GetByID(ctx, id)
+}
+
+// DeleteRecord deletes a finished recording with its files
+func (s *RecordRetentionService) DeleteRecord(ctx context.Context, id int, reason, actor string) error {
+	record, err := s.recordRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrRecordNotFound
+	}
+	if record.Status.String == "recording" {
+		return ErrRecordActive
+	}
+
+	held, err := s.recordRepo.IsOnLegalHold(ctx, id)
+	if err != nil {
+		return err
+	}
+	if held {
+		return ErrRecordOnLegalHold
+	}
+
+	service := 0
+	if room, err := s.roomRepo.GetByRoom(ctx, record.Room.String); err == nil && room != nil {
+		service = int(room.Service.Int32)
+	}
+
+	return s.remove(ctx, record, service, reason, actor)
+}
+
+// Apply archives and deletes the recordings due under every enabled policy
+func (s *RecordRetentionService) Apply(ctx context.Context) (*RetentionReport, error) {
+	report := &RetentionReport{CheckedAt: time.Now()}
+
+	policies, err := s.policyRepo.GetEnabled(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Policies = len(policies)
+
+	for _, policy := range policies {
+		if policy.ArchiveDays > 0 {
+			before := time.Now().AddDate(0, 0, -policy.ArchiveDays)
+			records, err := s.recordRepo.GetArchiveCandidates(ctx, policy.Service, before, recordRetentionBatchSize)
+			if err != nil {
+				return report, err
+			}
+			for i := range records {
+				if err := s.archive(ctx, &records[i], policy.Service); err != nil {
+					report.Failed++
+					log.Error().Err(err).Int("recordId", records[i].ID).Msg("Failed to archive recording")
+					continue
+				}
+				report.Archived++
+			}
+		}
+
+		if policy.RetainDays > 0 {
+			before := time.Now().AddDate(0, 0, -policy.RetainDays)
+			records, err := s.recordRepo.GetExpiredRecords(ctx, policy.Service, before, recordRetentionBatchSize)
+			if err != nil {
+				return report, err
+			}
+			reason := fmt.Sprintf("retention policy: older than %d days", policy.RetainDays)
+			for i := range records {
+				if err := s.remove(ctx, &records[i], policy.Service, reason, recordRetentionActor); err != nil {
+					report.Failed++
+					log.Error().Err(err).Int("recordId", records[i].ID).Msg("Failed to delete expired recording")
+					continue
+				}
+				report.Deleted++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// archive moves the files of a record to the archive and marks the record archived
+func (s *RecordRetentionService) archive(ctx context.Context, record *models.RecordMedia, service int) error {
+	files, err := s.archiveFiles(record)
+	if err != nil {
+		return err
+	}
+
+	archivePath := ""
+	if file := recordRelativePath(s.cfg.RecordPath, record.FilePath.String); file != "" {
+		archivePath = path.Join(filepath.ToSlash(s.cfg.RecordRetention.ArchivePath), file)
+	}
+	if err := s.recordRepo.MarkArchived(ctx, record.ID, archivePath); err != nil {
+		return err
+	}
+
+	return s.auditRepo.Create(ctx, repository.CreateRecordAuditParams{
+		Action:   RecordAuditArchive,
+		RecordID: record.ID,
+		Room:     record.Room.String,
+		Service:  service,
+		Files:    auditFiles(files),
+		Reason:   "retention policy",
+		Actor:    recordRetentionActor,
+	})
+}
+
+// remove deletes the files and rows of a record. The deletion is audited
+// before anything is removed, so no recording disappears without a trace.
+func (s *RecordRetentionService) remove(ctx context.Context, record *models.RecordMedia, service int, reason, actor string) error {
+	dir := s.recordDir(record)
+	files := recordFiles(s.cfg.RecordPath, dir, record)
+
+	audit := repository.CreateRecordAuditParams{
+		Action:   RecordAuditDelete,
+		RecordID: record.ID,
+		Room:     record.Room.String,
+		Service:  service,
+		Files:    auditFiles(files),
+		Reason:   reason,
+		Actor:    actor,
+	}
+	if err := s.auditRepo.Create(ctx, audit); err != nil {
+		return err
+	}
+
+	removeErr := func() error {
+		if err := deleteRecordFiles(dir, files); err != nil {
+			return err
+		}
+		if err := s.encodeJobRepo.DeleteByRecordID(ctx, record.ID); err != nil {
+			return err
+		}
+		return s.recordRepo.Delete(ctx, record.ID)
+	}()
+	if removeErr != nil {
+		audit.Action = RecordAuditDeleteFailed
+		audit.Reason = removeErr.Error()
+		if err := s.auditRepo.Create(ctx, audit); err != nil {
+			log.Error().Err(err).Int("recordId", record.ID).Msg("Failed to audit failed recording deletion")
+		}
+		return removeErr
+	}
+
+	log.Info().Int("recordId", record.ID).Str("room", record.Room.String).Str("actor", actor).Msg("Recording deleted")
+	return nil
+}
+
+// archiveFiles moves the files of a record from RECORD_PATH to the archive,
+// keeping their paths relative to the root, and returns them
+func (s *RecordRetentionService) archiveFiles(record *models.RecordMedia) ([]string, error) {
+	files := recordFiles(s.cfg.RecordPath, s.cfg.RecordPath, record)
+	for _, file := range files {
+		src := filepath.Join(s.cfg.RecordPath, filepath.FromSlash(file))
+		dst := filepath.Join(s.cfg.RecordRetention.ArchivePath, filepath.FromSlash(file))
+		if err := moveFile(src, dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// recordDir returns the directory the files of a record are in
+func (s *RecordRetentionService) recordDir(record *models.RecordMedia) string {
+	if record.DtmArchived.Valid {
+		return s.cfg.RecordRetention.ArchivePath
+	}
+	return s.cfg.RecordPath
+}
+
+// deleteRecordFiles deletes files given relative to dir; files already gone are skipped
+func deleteRecordFiles(dir string, files []string) error {
+	for _, file := range files {
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(file))); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordFiles returns the files of a record relative to the recording root:
+// the recording, its thumbnail, and its HLS playlists with every file they
+// reference. The stored paths point under recordPath even once the files are
+// archived; playlists are read from dir, where the files are now.
+func recordFiles(recordPath, dir string, record *models.RecordMedia) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(file string) bool {
+		if file == "" || seen[file] {
+			return false
+		}
+		seen[file] = true
+		files = append(files, file)
+		return true
+	}
+
+	add(recordRelativePath(recordPath, record.FilePath.String))
+	add(recordRelativePath(recordPath, record.Thumbnail.String))
+
+	playlists := []string{recordRelativePath(recordPath, record.HLS.String)}
+	for len(playlists) > 0 {
+		playlist := playlists[0]
+		playlists = playlists[1:]
+		if !add(playlist) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(playlist)))
+		if err != nil {
+			continue
+		}
+		for _, uri := range playlistURIs(content) {
+			file := path.Join(path.Dir(playlist), uri)
+			// Never follow a playlist out of the recording root
+			if file == ".." || strings.HasPrefix(file, "../") {
+				continue
+			}
+			if strings.HasSuffix(strings.ToLower(file), ".m3u8") {
+				playlists = append(playlists, file)
+			} else {
+				add(file)
+			}
+		}
+	}
+
+	return files
+}
+
+// playlistURIs returns the relative URIs a playlist references
+func playlistURIs(content []byte) []string {
+	var uris []string
+	add := func(uri string) {
+		if i := strings.IndexAny(uri, "?#"); i >= 0 {
+			uri = uri[:i]
+		}
+		if uri != "" && !strings.Contains(uri, "://") && !strings.HasPrefix(uri, "/") {
+			uris = append(uris, uri)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#"):
+			for _, match := range playlistURIAttr.FindAllStringSubmatch(line, -1) {
+				add(match[1])
+			}
+		case line != "":
+			add(line)
+		}
+	}
+
+	return uris
+}
+
+// auditFiles encodes a file list for the audit trail
+func auditFiles(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(files)
+	if err != nil {
+		return strings.Join(files, "\n")
+	}
+	return string(encoded)
+}
+
+// moveFile moves a file, copying it when source and destination are on different file systems
+func moveFile(src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+)
+
+func TestArchivedRecordFilesAreDeleted(t *testing.T) {
+	dir := t.TempDir()
+	recordPath := filepath.Join(dir, "record-file")
+	archivePath := filepath.Join(dir, "record-archive")
+
+	files := map[string]string{
+		"room/x.mp4":                "mp4",
+		"room/x.jpg":                "jpg",
+		"room/hls/index.m3u8":       "#EXTM3U\nvideo/index.m3u8\n",
+		"room/hls/video/index.m3u8": "#EXTM3U\n#EXTINF:4.0,\nseg0.ts\n",
+		"room/hls/video/seg0.ts":    "ts",
+	}
+	for file, content := range files {
+		name := filepath.Join(recordPath, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &RecordRetentionService{cfg: &config.Config{
+		RecordPath:      recordPath,
+		RecordRetention: config.RecordRetentionConfig{ArchivePath: archivePath},
+	}}
+	record := &models.RecordMedia{
+		FilePath:  sql.NullString{String: recordPath + "/room/x.mp4", Valid: true},
+		Thumbnail: sql.NullString{String: recordPath + "/room/x.jpg", Valid: true},
+		HLS:       sql.NullString{String: recordPath + "/room/hls/index.m3u8", Valid: true},
+	}
+
+	archived, err := s.archiveFiles(record)
+	if err != nil {
+		t.Fatalf("archiveFiles() error = %v", err)
+	}
+	if len(archived) != len(files) {
+		t.Fatalf("archiveFiles() moved %v, want %d files", archived, len(files))
+	}
+	for file := range files {
+		if _, err := os.Stat(filepath.Join(archivePath, filepath.FromSlash(file))); err != nil {
+			t.Fatalf("%s not archived: %v", file, err)
+		}
+	}
+
+	// The stored paths still point under RECORD_PATH once archived
+	record.DtmArchived = sql.NullTime{Time: time.Now(), Valid: true}
+	recordDir := s.recordDir(record)
+	if recordDir != archivePath {
+		t.Fatalf("recordDir() = %q, want %q", recordDir, archivePath)
+	}
+	removed := recordFiles(s.cfg.RecordPath, recordDir, record)
+	if len(removed) != len(files) {
+		t.Fatalf("recordFiles() = %v, want %d files", removed, len(files))
+	}
+	if err := deleteRecordFiles(recordDir, removed); err != nil {
+		t.Fatalf("deleteRecordFiles() error = %v", err)
+	}
+	for file := range files {
+		if _, err := os.Stat(filepath.Join(archivePath, filepath.FromSlash(file))); !os.IsNotExist(err) {
+			t.Fatalf("%s left in the archive: %v", file, err)
+		}
+	}
+}
@@ -4,24 +4,45 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"api-gateway-go/internal/config"
 	"api-gateway-go/internal/models"
 	"api-gateway-go/internal/repository"
 
 	"github.com/livekit/protocol/livekit"
+	"github.com/rs/zerolog/log"
 )
 
 var (
-	ErrRecordNotFound = errors.New("record not found")
-	ErrEgressLimit    = errors.New("egress limit reached")
+	ErrRecordNotFound  = errors.New("record not found")
+	ErrEgressLimit     = errors.New("egress limit reached")
+	ErrRecordTrack     = errors.New("track ID is required")
+	ErrRecordComposite = errors.New("audio and video track IDs are required")
 )
 
-// StartRecordOptions holds options for starting a recording
+// Record types
+const (
+	RecordTypeRoomCompositeVideoAudio = "RoomCompositeVideoAudio"
+	RecordTypeRoomCompositeAudio      = "RoomCompositeAudio"
+	RecordTypeTrackComposite          = "TrackComposite"
+	RecordTypeTrack                   = "Track"
+)
+
+// StartRecordOptions holds options for starting a recording. TrackComposite
+// recordings need AudioTrackID and VideoTrackID, Track recordings need TrackID.
+// RecordType, EncodingOptionsPreset, Layout and FileType default to the settings of the room.
 type StartRecordOptions struct {
-	Room       string
-	RecordType string
-	FilePath   string
+	Room                  string
+	RecordType            string
+	FilePath              string
+	Identity              string
+	AudioTrackID          string
+	VideoTrackID          string
+	TrackID               string
+	EncodingOptionsPreset string
+	Layout                string
+	FileType              string
 }
 
 // RecordService handles recording business logic
@@ -42,12 +63,18 @@ func NewRecordService(recordRepo *repository.RecordRepository, roomRepo *reposit
 	}
 }
 
-// StartRecord starts a recording
+// StartRecord starts a recording. Room composite recordings are the default;
+// TrackComposite and Track recordings belong to a single participant.
 func (s *RecordService) StartRecord(ctx context.Context, opts StartRecordOptions) (*livekit.EgressInfo, error) {
 	if s.livekitMgr == nil || s.livekitMgr.EgressClient() == nil {
 		return nil, errors.New("LiveKit not configured")
 	}
 
+	encoding, err := s.prepareRecord(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check egress limit
 	available, err := s.recordRepo.CheckEgressAvailable(ctx, s.cfg.EgressLimit)
 	if err != nil {
@@ -57,37 +84,125 @@ func (s *RecordService) StartRecord(ctx context.Context, opts StartRecordOptions
 		return nil, ErrEgressLimit
 	}
 
-	// Start room composite egress
 	filePath := opts.FilePath
 	if filePath == "" {
-		filePath = s.cfg.RecordPath + "/" + opts.Room + ".mp4"
+		filePath = s.defaultFilePath(opts, encoding.extension)
 	}
 
-	output := &livekit.EncodedFileOutput{
-		Filepath: filePath,
+	var info *livekit.EgressInfo
+	switch opts.RecordType {
+	case RecordTypeTrackComposite:
+		info, err = s.livekitMgr.StartTrackCompositeEgress(ctx, opts.Room, opts.AudioTrackID, opts.VideoTrackID, &livekit.EncodedFileOutput{
+			FileType: encoding.fileType,
+			Filepath: filePath,
+		}, encoding.egress)
+	case RecordTypeTrack:
+		info, err = s.livekitMgr.StartTrackEgress(ctx, opts.Room, opts.TrackID, &livekit.DirectFileOutput{
+			Filepath: filePath,
+		})
+	default:
+		info, err = s.livekitMgr.StartRoomCompositeEgress(ctx, opts.Room, &livekit.EncodedFileOutput{
+			FileType: encoding.fileType,
+			Filepath: filePath,
+		}, encoding.egress)
 	}
-
-	info, err := s.livekitMgr.StartRoomCompositeEgress(ctx, opts.Room, output)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update room record status
-	_ = s.roomRepo.UpdateRecordStatus(ctx, opts.Room, 1)
-	_ = s.roomRepo.UpdateRecordID(ctx, opts.Room, info.EgressId)
-
-	// Create record entry in database
-	_, _ = s.recordRepo.Create(ctx, repository.CreateRecordParams{
+	// Create record entry in database, one per egress. Slot accounting counts
+	// these rows, so an egress without one is stopped rather than left running.
+	_, err = s.recordRepo.Create(ctx, repository.CreateRecordParams{
 		EgressID:   info.EgressId,
 		Room:       opts.Room,
+		Identity:   opts.Identity,
 		FilePath:   filePath,
 		RecordType: opts.RecordType,
 		Status:     "recording",
 	})
+	if err != nil {
+		if _, stopErr := s.livekitMgr.StopEgress(ctx, info.EgressId); stopErr != nil {
+			log.Error().Err(stopErr).Str("egressId", info.EgressId).Str("room", opts.Room).Msg("Failed to stop unrecorded egress, left for reconciliation")
+		}
+		return nil, err
+	}
+
+	// Update room record status
+	_ = s.roomRepo.UpdateRecordStatus(ctx, opts.Room, 1)
+	_ = s.roomRepo.UpdateRecordID(ctx, opts.Room, info.EgressId)
 
 	return info, nil
 }
 
+// ValidateRecord fills the recording settings not given in opts from the room
+// and checks them, so a request can be rejected before it is queued
+func (s *RecordService) ValidateRecord(ctx context.Context, opts *StartRecordOptions) error {
+	_, err := s.prepareRecord(ctx, opts)
+	return err
+}
+
+// prepareRecord applies the room settings to opts and resolves its output settings
+func (s *RecordService) prepareRecord(ctx context.Context, opts *StartRecordOptions) (*recordEncoding, error) {
+	if err := s.applyRoomSettings(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	switch opts.RecordType {
+	case RecordTypeTrackComposite:
+		if opts.AudioTrackID == "" || opts.VideoTrackID == "" {
+			return nil, ErrRecordComposite
+		}
+	case RecordTypeTrack:
+		if opts.TrackID == "" {
+			return nil, ErrRecordTrack
+		}
+	}
+
+	return resolveRecordEncoding(opts.RecordType, opts.EncodingOptionsPreset, opts.Layout, opts.FileType)
+}
+
+// applyRoomSettings fills the recording settings not given in opts from the room
+func (s *RecordService) applyRoomSettings(ctx context.Context, opts *StartRecordOptions) error {
+	if opts.RecordType == "" || opts.EncodingOptionsPreset == "" || opts.Layout == "" || opts.FileType == "" {
+		room, err := s.roomRepo.GetByRoom(ctx, opts.Room)
+		if err != nil {
+			return err
+		}
+		if room != nil {
+			if opts.RecordType == "" {
+				opts.RecordType = room.RecordType.String
+			}
+			if opts.EncodingOptionsPreset == "" {
+				opts.EncodingOptionsPreset = room.EncodingOptionsPreset.String
+			}
+			if opts.Layout == "" {
+				opts.Layout = room.RecordLayout.String
+			}
+			if opts.FileType == "" {
+				opts.FileType = room.RecordFileType.String
+			}
+		}
+	}
+
+	if opts.RecordType == "" {
+		opts.RecordType = RecordTypeRoomCompositeVideoAudio
+	}
+	return nil
+}
+
+// defaultFilePath returns where a recording is written when no path is given.
+// Participant recordings get a file per participant; track recordings let
+// egress choose the extension from the track codec.
+func (s *RecordService) defaultFilePath(opts StartRecordOptions, extension string) string {
+	switch opts.RecordType {
+	case RecordTypeTrackComposite:
+		return s.cfg.RecordPath + "/" + opts.Room + "/" + opts.Identity + "-{time}" + extension
+	case RecordTypeTrack:
+		return s.cfg.RecordPath + "/" + opts.Room + "/" + opts.Identity + "-{track_type}-{time}"
+	}
+	return s.cfg.RecordPath + "/" + opts.Room + extension
+}
+
 // StopRecord stops a recording
 func (s *RecordService) StopRecord(ctx context.Context, recordID string) (*livekit.EgressInfo, error) {
 	if s.livekitMgr == nil || s.livekitMgr.EgressClient() == nil {
@@ -176,12 +291,285 @@ func (s *RecordService) UpdateRecordByEgressID(ctx context.Context, egressID, fi
 	return s.recordRepo.UpdateByEgressID(ctx, egressID, fileName, filePath, status, fileSize, duration)
 }
 
-// GetRecordQueue gets the record queue
-func (s *RecordService) GetRecordQueue(ctx context.Context) ([]models.RecordMedia, error) {
-	return s.recordRepo.GetRecordQueue(ctx)
-}
-
 // GetActiveRecordCount gets count of active recordings
 func (s *RecordService) GetActiveRecordCount(ctx context.Context) (int, error) {
 	return s.recordRepo.GetActiveRecordCount(ctx)
 }
+
+// EndRecord records the result of an ended egress. Participant recordings run
+// side by side, so the room only stops recording when its last egress has ended.
+func (s *RecordService) EndRecord(ctx context.Context, info *livekit.EgressInfo) error {
+	switch info.Status {
+	case livekit.EgressStatus_EGRESS_COMPLETE, livekit.EgressStatus_EGRESS_LIMIT_REACHED:
+		var fileName, filePath string
+		var fileSize, duration int
+
+		if len(info.GetFileResults()) > 0 {
+			result := info.GetFileResults()[0]
+			fileName = result.Filename
+			filePath = result.Filename
+			fileSize = int(result.Size)
+			duration = int(result.Duration / 1000000000) // nanoseconds to seconds
+		}
+
+		if err := s.recordRepo.UpdateByEgressID(ctx, info.EgressId, fileName, filePath, "complete", fileSize, duration); err != nil {
+			return err
+		}
+	case livekit.EgressStatus_EGRESS_FAILED, livekit.EgressStatus_EGRESS_ABORTED:
+		if err := s.recordRepo.UpdateByEgressID(ctx, info.EgressId, "", "", "failed", 0, 0); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	return s.releaseRoom(ctx, EgressRoomName(info))
+}
+
+// releaseRoom clears the recording state of a room once it has no active recordings
+func (s *RecordService) releaseRoom(ctx context.Context, room string) error {
+	if room == "" {
+		return nil
+	}
+
+	active, err := s.recordRepo.GetActiveCountByRoom(ctx, room)
+	if err != nil || active > 0 {
+		return err
+	}
+
+	_ = s.roomRepo.UpdateRecordID(ctx, room, "")
+	return s.roomRepo.UpdateRecordStatus(ctx, room, 2)
+}
+
+// Egress reconciliation discrepancy kinds
+const (
+	EgressDiscrepancyEnded     = "ended"
+	EgressDiscrepancyMissing   = "missing"
+	EgressDiscrepancyRevived   = "revived"
+	EgressDiscrepancyUntracked = "untracked"
+	EgressDiscrepancyFileInfo  = "file_info"
+	EgressDiscrepancyRoom      = "room_status"
+)
+
+// EgressDiscrepancy is a difference between LiveKit egress state and the database
+type EgressDiscrepancy struct {
+	Kind     string `json:"kind"`
+	EgressID string `json:"egressId,omitempty"`
+	Room     string `json:"room,omitempty"`
+	Detail   string `json:"detail"`
+}
+
+// EgressReconcileReport is the result of a reconciliation run
+type EgressReconcileReport struct {
+	CheckedAt     time.Time           `json:"checkedAt"`
+	ActiveEgress  int                 `json:"activeEgress"`
+	Recording     int                 `json:"recording"`
+	Fixed         int                 `json:"fixed"`
+	Discrepancies []EgressDiscrepancy `json:"discrepancies"`
+	Error         string              `json:"error,omitempty"`
+}
+
+// EgressReconcileSummary is the part of a reconciliation report shown on /status,
+// without the egress IDs and rooms of the discrepancies
+type EgressReconcileSummary struct {
+	CheckedAt     time.Time `json:"checkedAt"`
+	Fixed         int       `json:"fixed"`
+	Discrepancies int       `json:"discrepancies"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Summary returns the summary of the report, or nil before the first run
+func (r *EgressReconcileReport) Summary() *EgressReconcileSummary {
+	if r == nil {
+		return nil
+	}
+	return &EgressReconcileSummary{
+		CheckedAt:     r.CheckedAt,
+		Fixed:         r.Fixed,
+		Discrepancies: len(r.Discrepancies),
+		Error:         r.Error,
+	}
+}
+
+// add records a discrepancy; fixed discrepancies also count towards Fixed
+func (r *EgressReconcileReport) add(fixed bool, kind, egressID, room, detail string) {
+	r.Discrepancies = append(r.Discrepancies, EgressDiscrepancy{
+		Kind:     kind,
+		EgressID: egressID,
+		Room:     room,
+		Detail:   detail,
+	})
+	if fixed {
+		r.Fixed++
+	}
+}
+
+// ReconcileEgress compares the egresses LiveKit reports with record_media and
+// room_conference and fixes what drifted, e.g. after a missed egress_ended webhook:
+//   - records of ended egresses are closed with the file size and duration of the egress
+//   - records LiveKit no longer knows are marked failed once grace has passed
+//   - records of egresses LiveKit still runs are marked recording again
+//   - rooms get recordStatus and recordId matching their active recordings
+//
+// Active egresses without a record are reported only.
+func (s *RecordService) ReconcileEgress(ctx context.Context, grace time.Duration) (*EgressReconcileReport, error) {
+	report := &EgressReconcileReport{CheckedAt: time.Now(), Discrepancies: []EgressDiscrepancy{}}
+
+	if s.livekitMgr == nil || s.livekitMgr.EgressClient() == nil {
+		return report, errors.New("LiveKit not configured")
+	}
+
+	egresses, err := s.livekitMgr.ListEgress(ctx, "")
+	if err != nil {
+		return report, err
+	}
+
+	known := make(map[string]bool, len(egresses))
+	for _, info := range egresses {
+		known[info.EgressId] = true
+		if err := s.reconcileEgress(ctx, info, report); err != nil {
+			return report, err
+		}
+	}
+
+	records, err := s.recordRepo.GetRecording(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Recording = len(records)
+
+	cutoff := time.Now().Add(-grace)
+	for _, record := range records {
+		if known[record.EgressID.String] || (record.DtmCreated.Valid && record.DtmCreated.Time.After(cutoff)) {
+			continue
+		}
+
+		if err := s.recordRepo.UpdateByEgressID(ctx, record.EgressID.String, "", "", "failed", 0, 0); err != nil {
+			return report, err
+		}
+		report.add(true, EgressDiscrepancyMissing, record.EgressID.String, record.Room.String, "egress unknown to LiveKit, record marked failed")
+	}
+
+	if err := s.reconcileRooms(ctx, report); err != nil {
+		return report, err
+	}
+
+	for _, discrepancy := range report.Discrepancies {
+		log.Warn().
+			Str("kind", discrepancy.Kind).
+			Str("egressId", discrepancy.EgressID).
+			Str("room", discrepancy.Room).
+			Msg(discrepancy.Detail)
+	}
+
+	return report, nil
+}
+
+// reconcileEgress brings the record of one LiveKit egress in line with its state
+func (s *RecordService) reconcileEgress(ctx context.Context, info *livekit.EgressInfo, report *EgressReconcileReport) error {
+	active := false
+	switch info.Status {
+	case livekit.EgressStatus_EGRESS_STARTING, livekit.EgressStatus_EGRESS_ACTIVE, livekit.EgressStatus_EGRESS_ENDING:
+		active = true
+		report.ActiveEgress++
+	}
+
+	record, err := s.recordRepo.GetByEgressID(ctx, info.EgressId)
+	if err != nil {
+		return err
+	}
+	room := EgressRoomName(info)
+
+	if record == nil {
+		if active {
+			report.add(false, EgressDiscrepancyUntracked, info.EgressId, room, "active egress has no record")
+		}
+		return nil
+	}
+
+	status := record.Status.String
+	switch {
+	case active && status != "recording":
+		if err := s.recordRepo.UpdateStatus(ctx, record.ID, "recording"); err != nil {
+			return err
+		}
+		report.add(true, EgressDiscrepancyRevived, info.EgressId, room, "egress still active, record was "+status)
+
+	case !active && status == "recording":
+		if err := s.EndRecord(ctx, info); err != nil {
+			return err
+		}
+		report.add(true, EgressDiscrepancyEnded, info.EgressId, room, "egress ended as "+info.Status.String()+", record closed")
+
+	case info.Status == livekit.EgressStatus_EGRESS_COMPLETE && status == "complete" &&
+		len(info.GetFileResults()) > 0 && (!record.FileSize.Valid || record.FileSize.Int32 == 0 || !record.Duration.Valid):
+		if err := s.EndRecord(ctx, info); err != nil {
+			return err
+		}
+		report.add(true, EgressDiscrepancyFileInfo, info.EgressId, room, "file size and duration filled from egress results")
+	}
+
+	return nil
+}
+
+// reconcileRooms sets recordStatus and recordId of rooms from their active recordings
+func (s *RecordService) reconcileRooms(ctx context.Context, report *EgressReconcileReport) error {
+	records, err := s.recordRepo.GetRecording(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Records are newest first, so the first egress seen is the latest of its room
+	latest := make(map[string]string)
+	for _, record := range records {
+		room := record.Room.String
+		if _, ok := latest[room]; !ok && room != "" {
+			latest[room] = record.EgressID.String
+		}
+	}
+
+	recordingRooms, err := s.roomRepo.GetRecordingRooms(ctx)
+	if err != nil {
+		return err
+	}
+	flagged := make(map[string]bool, len(recordingRooms))
+	for _, room := range recordingRooms {
+		flagged[room] = true
+		if _, ok := latest[room]; !ok {
+			if err := s.releaseRoom(ctx, room); err != nil {
+				return err
+			}
+			report.add(true, EgressDiscrepancyRoom, "", room, "room marked recording without an active recording, cleared")
+		}
+	}
+
+	for room, egressID := range latest {
+		if flagged[room] {
+			continue
+		}
+		_ = s.roomRepo.UpdateRecordStatus(ctx, room, 1)
+		if err := s.roomRepo.UpdateRecordID(ctx, room, egressID); err != nil {
+			return err
+		}
+		report.add(true, EgressDiscrepancyRoom, egressID, room, "room has an active recording but was not marked recording")
+	}
+
+	return nil
+}
+
+// EgressRoomName returns the room an egress records
+func EgressRoomName(info *livekit.EgressInfo) string {
+	if info.RoomName != "" {
+		return info.RoomName
+	}
+	if rc := info.GetRoomComposite(); rc != nil {
+		return rc.RoomName
+	}
+	if tc := info.GetTrackComposite(); tc != nil {
+		return tc.RoomName
+	}
+	if t := info.GetTrack(); t != nil {
+		return t.RoomName
+	}
+	return ""
+}
@@ -13,9 +13,10 @@ import (
 )
 
 var (
-	ErrRoomNotFound = errors.New("room not found")
-	ErrRoomClosed   = errors.New("room is closed")
-	ErrRoomExpired  = errors.New("room has expired")
+	ErrRoomNotFound  = errors.New("room not found")
+	ErrRoomClosed    = errors.New("room is closed")
+	ErrRoomExpired   = errors.New("room has expired")
+	ErrRoomLegalHold = errors.New("room has a case under legal hold")
 )
 
 // CreateRoomOptions holds options for creating a room
@@ -25,6 +26,8 @@ type CreateRoomOptions struct {
 	AutoRecord            int
 	RecordType            string
 	EncodingOptionsPreset string
+	RecordLayout          string
+	RecordFileType        string
 	ChatEnabled           int
 	WebSocketURL          string
 	UserAgent             string
@@ -49,8 +52,15 @@ func NewRoomService(roomRepo *repository.RoomRepository, livekitMgr *config.Live
 
 // CreateRoom creates a new room
 func (s *RoomService) CreateRoom(ctx context.Context, opts CreateRoomOptions) (*models.RoomConference, error) {
+	if err := ValidateRecordSettings(opts.RecordType, opts.EncodingOptionsPreset, opts.RecordFileType); err != nil {
+		return nil, err
+	}
+
 	// Generate room name
-	roomName := utils.GenerateRoomName()
+	newRoomName := func() string {
+		return utils.GenerateRoomName(s.cfg.RoomNameLength)
+	}
+	roomName := newRoomName()
 	now := utils.FormatDateTimeNow()
 
 	// Calculate expiration
@@ -69,18 +79,22 @@ func (s *RoomService) CreateRoom(ctx context.Context, opts CreateRoomOptions) (*
 		AutoRecord:            opts.AutoRecord,
 		RecordType:            opts.RecordType,
 		EncodingOptionsPreset: opts.EncodingOptionsPreset,
+		RecordLayout:          opts.RecordLayout,
+		RecordFileType:        opts.RecordFileType,
 		ChatEnabled:           opts.ChatEnabled,
 		WebSocketURL:          opts.WebSocketURL,
 		UserAgent:             opts.UserAgent,
 		DtmCreated:            now,
 		DtmUpdated:            now,
 		DtmExpired:            expiredAt,
+		NewRoom:               newRoomName,
 	}
 
-	roomID, err := s.roomRepo.Create(ctx, params)
+	roomID, err := s.roomRepo.Create(ctx, &params)
 	if err != nil {
 		return nil, err
 	}
+	roomName = params.Room
 
 	// Create room in LiveKit if available
 	if s.livekitMgr != nil && s.livekitMgr.RoomClient() != nil {
@@ -193,8 +207,17 @@ func (s *RoomService) AutoRoomExpiredClose(ctx context.Context) error {
 	return nil
 }
 
-// DeleteRoom deletes a room
+// DeleteRoom deletes a room. Rooms with a case under legal hold are kept, since
+// the hold on their recordings is found through the room.
 func (s *RoomService) DeleteRoom(ctx context.Context, roomName string) error {
+	held, err := s.roomRepo.IsOnLegalHold(ctx, roomName)
+	if err != nil {
+		return err
+	}
+	if held {
+		return ErrRoomLegalHold
+	}
+
 	// Delete from LiveKit first
 	if s.livekitMgr != nil && s.livekitMgr.RoomClient() != nil {
 		_ = s.livekitMgr.DeleteRoom(ctx, roomName)
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrSMSBlocked           = errors.New("mobile number is blocked from receiving SMS")
+	ErrSMSRateLimitMobile   = errors.New("too many SMS sent to this mobile number")
+	ErrSMSRateLimitService  = errors.New("too many SMS sent for this service")
+	ErrSMSRateLimitGlobal   = errors.New("too many SMS sent")
+	ErrSMSBlocklistNotFound = errors.New("blocklist entry not found")
+	ErrSMSBlocklistGlobal   = errors.New("blocks for every service are managed by administrators only")
+	ErrInvalidMobile        = errors.New("invalid mobile number")
+)
+
+// SMS rejection error codes returned to API clients
+const (
+	SMSCodeBlocked          = "SMS_BLOCKED"
+	SMSCodeRateLimitMobile  = "SMS_RATE_LIMIT_MOBILE"
+	SMSCodeRateLimitService = "SMS_RATE_LIMIT_SERVICE"
+	SMSCodeRateLimitGlobal  = "SMS_RATE_LIMIT_GLOBAL"
+)
+
+// smsRateLimitKeyPrefix is the Redis key prefix for SMS sliding windows
+const smsRateLimitKeyPrefix = "sms:ratelimit:"
+
+// slidingWindowScript checks every window and, when ARGV[3] is "1" and all pass,
+// records the send in each of them. Returns the 1-based index of the first full
+// window, or 0 when the send is allowed.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+for i, key in ipairs(KEYS) do
+	local limit = tonumber(ARGV[2 + i * 2])
+	local window = tonumber(ARGV[3 + i * 2])
+	redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+	if redis.call('ZCARD', key) >= limit then
+		return i
+	end
+end
+if ARGV[3] == '1' then
+	for i, key in ipairs(KEYS) do
+		local window = tonumber(ARGV[3 + i * 2])
+		redis.call('ZADD', key, now, ARGV[2])
+		redis.call('PEXPIRE', key, window)
+	end
+end
+return 0
+`)
+
+// BlockMobileOptions holds options for blocking a mobile number
+type BlockMobileOptions struct {
+	Mobile    string
+	Service   int
+	Source    string
+	Reason    string
+	CreatedBy string
+}
+
+// SmsLimiterService guards SMS sends with a blocklist and Redis sliding-window limits
+type SmsLimiterService struct {
+	redis         *config.RedisManager
+	blocklistRepo *repository.SmsBlocklistRepository
+	cfg           *config.Config
+}
+
+// NewSmsLimiterService creates a new SmsLimiterService
+func NewSmsLimiterService(redis *config.RedisManager, blocklistRepo *repository.SmsBlocklistRepository, cfg *config.Config) *SmsLimiterService {
+	return &SmsLimiterService{
+		redis:         redis,
+		blocklistRepo: blocklistRepo,
+		cfg:           cfg,
+	}
+}
+
+// Check reports whether an SMS to the mobile number would be allowed, without counting it
+func (s *SmsLimiterService) Check(ctx context.Context, mobile string, service int) error {
+	return s.check(ctx, mobile, service, false)
+}
+
+// Acquire checks the limits and counts the SMS against every window
+func (s *SmsLimiterService) Acquire(ctx context.Context, mobile string, service int) error {
+	return s.check(ctx, mobile, service, true)
+}
+
+func (s *SmsLimiterService) check(ctx context.Context, mobile string, service int, consume bool) error {
+	mobile = utils.NormalizeMobile(mobile)
+	if mobile == "" {
+		return ErrInvalidMobile
+	}
+
+	blocked, err := s.blocklistRepo.IsBlocked(ctx, mobile, service)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrSMSBlocked
+	}
+
+	// Fail open when Redis is unavailable so SMS keeps working
+	if s.redis == nil {
+		return nil
+	}
+
+	limits := s.cfg.SMSRateLimit
+	var keys []string
+	var scopeErrors []error
+	now := time.Now().UnixMilli()
+	args := []interface{}{now, fmt.Sprintf("%d-%s", time.Now().UnixNano(), mobile), "0"}
+	if consume {
+		args[2] = "1"
+	}
+
+	addScope := func(key string, limit int, window time.Duration, scopeErr error) {
+		if limit <= 0 || window <= 0 {
+			return
+		}
+		keys = append(keys, smsRateLimitKeyPrefix+key)
+		scopeErrors = append(scopeErrors, scopeErr)
+		args = append(args, limit, window.Milliseconds())
+	}
+	addScope("mobile:"+mobile, limits.MobileLimit, limits.MobileWindow, ErrSMSRateLimitMobile)
+	addScope("service:"+strconv.Itoa(service), limits.ServiceLimit, limits.ServiceWindow, ErrSMSRateLimitService)
+	addScope("global", limits.GlobalLimit, limits.GlobalWindow, ErrSMSRateLimitGlobal)
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	full, err := slidingWindowScript.Run(ctx, s.redis.Client(), keys, args...).Int()
+	if err != nil {
+		log.Error().Err(err).Msg("SMS rate limit check failed, allowing send")
+		return nil
+	}
+	if full > 0 && full <= len(scopeErrors) {
+		return scopeErrors[full-1]
+	}
+
+	return nil
+}
+
+// ListBlocked lists blocked mobile numbers
+func (s *SmsLimiterService) ListBlocked(ctx context.Context, mobile string, limit, offset int) ([]models.SmsBlocklist, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if mobile != "" {
+		mobile = utils.NormalizeMobile(mobile)
+	}
+	return s.blocklistRepo.List(ctx, mobile, limit, offset)
+}
+
+// Block adds a mobile number to the blocklist. Scoped callers block for their
+// own service only; service 0 blocks every service and is left to administrators.
+func (s *SmsLimiterService) Block(ctx context.Context, opts BlockMobileOptions) (*models.SmsBlocklist, error) {
+	mobile := utils.NormalizeMobile(opts.Mobile)
+	if mobile == "" {
+		return nil, ErrInvalidMobile
+	}
+
+	if tenant, ok := repository.TenantFromContext(ctx); ok {
+		if tenant.Service == 0 {
+			return nil, ErrSMSBlocklistGlobal
+		}
+		opts.Service = tenant.Service
+	}
+
+	source := opts.Source
+	if source == "" {
+		source = "admin"
+	}
+
+	id, err := s.blocklistRepo.Create(ctx, repository.CreateSmsBlocklistParams{
+		Mobile:    mobile,
+		Service:   opts.Service,
+		Source:    source,
+		Reason:    opts.Reason,
+		CreatedBy: opts.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.blocklistRepo.GetByID(ctx, int(id))
+}
+
+// Unblock removes a mobile number from the blocklist
+func (s *SmsLimiterService) Unblock(ctx context.Context, id int) error {
+	entry, err := s.blocklistRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return ErrSMSBlocklistNotFound
+	}
+	return s.blocklistRepo.Delete(ctx, id)
+}
+
+// SMSErrorCode returns the API error code for an SMS rejection, or "" for other errors
+func SMSErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrSMSBlocked):
+		return SMSCodeBlocked
+	case errors.Is(err, ErrSMSRateLimitMobile):
+		return SMSCodeRateLimitMobile
+	case errors.Is(err, ErrSMSRateLimitService):
+		return SMSCodeRateLimitService
+	case errors.Is(err, ErrSMSRateLimitGlobal):
+		return SMSCodeRateLimitGlobal
+	default:
+		return ""
+	}
+}
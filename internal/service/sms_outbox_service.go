@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/internal/sms"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SMS outbox statuses
+const (
+	SMSStatusPending   = "pending"
+	SMSStatusSending   = "sending"
+	SMSStatusSent      = "sent"
+	SMSStatusDead      = "dead"
+	SMSStatusCancelled = "cancelled"
+)
+
+const (
+	smsOutboxBatchSize  = 50
+	smsOutboxStaleAfter = 5 * time.Minute
+)
+
+var (
+	ErrSMSOutboxNotFound       = errors.New("SMS not found")
+	ErrSMSOutboxNotRetryable   = errors.New("SMS cannot be retried in its current status")
+	ErrSMSOutboxNotCancellable = errors.New("only pending SMS can be cancelled")
+	ErrInvalidDeliveryReport   = errors.New("invalid delivery report")
+	ErrDeliveryReportSecret    = errors.New("SMS delivery reports are not configured")
+	ErrDeliveryReportSignature = errors.New("invalid delivery report signature")
+)
+
+// EnqueueSMSOptions holds options for queueing an SMS
+type EnqueueSMSOptions struct {
+	Service     int
+	LinkID      string
+	Room        string
+	Mobile      string
+	Sender      string
+	Provider    string
+	MessageType string
+	Message     string
+	CreatedBy   string
+}
+
+// SmsOutboxService queues SMS and delivers them with retries
+type SmsOutboxService struct {
+	outboxRepo *repository.SmsOutboxRepository
+	linkRepo   *repository.LinkRepository
+	smsService *SMSService
+	limiter    *SmsLimiterService
+	cfg        *config.Config
+	ticker     *time.Ticker
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewSmsOutboxService creates a new SmsOutboxService
+func NewSmsOutboxService(outboxRepo *repository.SmsOutboxRepository, linkRepo *repository.LinkRepository, smsService *SMSService, limiter *SmsLimiterService, cfg *config.Config) *SmsOutboxService {
+	return &SmsOutboxService{
+		outboxRepo: outboxRepo,
+		linkRepo:   linkRepo,
+		smsService: smsService,
+		limiter:    limiter,
+		cfg:        cfg,
+		done:       make(chan struct{}),
+	}
+}
+
+// Enqueue stores an SMS in the outbox for the worker to deliver.
+// Blocked numbers and sends over the rate limits are rejected before queueing.
+func (s *SmsOutboxService) Enqueue(ctx context.Context, opts EnqueueSMSOptions) (*models.SmsOutbox, error) {
+	if s.limiter != nil {
+		if err := s.limiter.Acquire(ctx, opts.Mobile, opts.Service); err != nil {
+			return nil, err
+		}
+	}
+
+	maxAttempts := s.cfg.SMSOutbox.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	id, err := s.outboxRepo.Create(ctx, repository.CreateSmsOutboxParams{
+		Service:     opts.Service,
+		LinkID:      opts.LinkID,
+		Room:        opts.Room,
+		Mobile:      opts.Mobile,
+		Sender:      opts.Sender,
+		Provider:    opts.Provider,
+		MessageType: opts.MessageType,
+		Message:     opts.Message,
+		MaxAttempts: maxAttempts,
+		CreatedBy:   opts.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.outboxRepo.GetByID(ctx, int(id))
+}
+
+// Send queues an SMS and makes the first delivery attempt immediately.
+// A failed attempt stays in the outbox for the worker to retry.
+func (s *SmsOutboxService) Send(ctx context.Context, opts EnqueueSMSOptions) (*models.SmsOutbox, error) {
+	msg, err := s.Enqueue(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deliver(ctx, int(msg.ID)); err != nil {
+		log.Warn().Err(err).Uint("id", msg.ID).Msg("First SMS delivery attempt failed")
+	}
+
+	return s.outboxRepo.GetByID(ctx, int(msg.ID))
+}
+
+// GetMessage gets a queued SMS by ID
+func (s *SmsOutboxService) GetMessage(ctx context.Context, id int) (*models.SmsOutbox, error) {
+	msg, err := s.outboxRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, ErrSMSOutboxNotFound
+	}
+	return msg, nil
+}
+
+// ListMessages lists queued SMS
+func (s *SmsOutboxService) ListMessages(ctx context.Context, filter repository.SmsOutboxFilter, limit, offset int) ([]models.SmsOutbox, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.outboxRepo.List(ctx, filter, limit, offset)
+}
+
+// Retry requeues a pending, dead or cancelled SMS and attempts delivery immediately
+func (s *SmsOutboxService) Retry(ctx context.Context, id int) (*models.SmsOutbox, error) {
+	if _, err := s.GetMessage(ctx, id); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.outboxRepo.Requeue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSMSOutboxNotRetryable
+	}
+
+	if err := s.deliver(ctx, id); err != nil {
+		log.Warn().Err(err).Int("id", id).Msg("SMS retry attempt failed")
+	}
+
+	return s.outboxRepo.GetByID(ctx, id)
+}
+
+// Cancel cancels a pending SMS
+func (s *SmsOutboxService) Cancel(ctx context.Context, id int) (*models.SmsOutbox, error) {
+	if _, err := s.GetMessage(ctx, id); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.outboxRepo.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSMSOutboxNotCancellable
+	}
+
+	return s.outboxRepo.GetByID(ctx, id)
+}
+
+// DeliveryReport is a carrier delivery receipt for a sent SMS
+type DeliveryReport struct {
+	ProviderMessageID string
+	Status            string
+	ErrorCode         string
+}
+
+// VerifyDeliveryReport checks that a delivery report call comes from the
+// carrier. The carrier either signs the body with an HMAC-SHA256 of
+// SMS_DLR_SECRET, sent hex encoded, or sends the secret itself as token when it
+// can only call a fixed URL. Every call is rejected while no secret is set.
+func (s *SmsOutboxService) VerifyDeliveryReport(signature, token string, body []byte) error {
+	secret := s.cfg.SMSDLRSecret
+	if secret == "" {
+		return ErrDeliveryReportSecret
+	}
+
+	if signature != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+			return nil
+		}
+		return ErrDeliveryReportSignature
+	}
+
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return ErrDeliveryReportSignature
+	}
+	return nil
+}
+
+// HandleDeliveryReport matches a delivery receipt to its outbox row and linked
+// link, and records the delivery status. Returns nil when no SMS matches.
+func (s *SmsOutboxService) HandleDeliveryReport(ctx context.Context, report DeliveryReport) (*models.SmsOutbox, error) {
+	status := sms.NormalizeDeliveryStatus(report.Status)
+	if report.ProviderMessageID == "" || status == "" {
+		return nil, ErrInvalidDeliveryReport
+	}
+
+	msg, err := s.outboxRepo.GetByProviderMessageID(ctx, report.ProviderMessageID)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+
+	if err := s.outboxRepo.UpdateDeliveryStatus(ctx, int(msg.ID), status, report.ErrorCode); err != nil {
+		return nil, err
+	}
+
+	if msg.LinkID.Valid && msg.LinkID.String != "" {
+		if err := s.linkRepo.UpdateSMSDeliveryStatus(ctx, msg.LinkID.String, status); err != nil {
+			log.Error().Err(err).Str("linkID", msg.LinkID.String).Msg("Failed to update link SMS delivery status")
+		}
+		if status == sms.DeliveryFailed || status == sms.DeliveryExpired {
+			s.updateLinkSMS(ctx, msg, LinkSMSFailed)
+		}
+	}
+
+	return s.outboxRepo.GetByID(ctx, int(msg.ID))
+}
+
+// ProcessDue delivers all SMS whose next attempt is due
+func (s *SmsOutboxService) ProcessDue(ctx context.Context) error {
+	released, err := s.outboxRepo.ReleaseStale(ctx, time.Now().Add(-smsOutboxStaleAfter))
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		log.Warn().Int64("count", released).Msg("Released stale SMS outbox messages")
+	}
+
+	ids, err := s.outboxRepo.GetDueIDs(ctx, smsOutboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.deliver(ctx, id); err != nil {
+			log.Warn().Err(err).Int("id", id).Msg("SMS delivery attempt failed")
+		}
+	}
+
+	return nil
+}
+
+// deliver claims an SMS and attempts to send it once
+func (s *SmsOutboxService) deliver(ctx context.Context, id int) error {
+	claimed, err := s.outboxRepo.Claim(ctx, id)
+	if err != nil || !claimed {
+		return err
+	}
+
+	msg, err := s.outboxRepo.GetByID(ctx, id)
+	if err != nil || msg == nil {
+		return err
+	}
+
+	messageID, sendErr := s.smsService.Send(ctx, msg.Provider.String, sms.Message{
+		To:     msg.Mobile,
+		Sender: msg.Sender.String,
+		Text:   msg.Message.String,
+	})
+	if sendErr == nil {
+		if err := s.outboxRepo.MarkSent(ctx, id, messageID); err != nil {
+			return err
+		}
+		s.updateLinkSMS(ctx, msg, LinkSMSSent)
+		return nil
+	}
+
+	status := SMSStatusPending
+	linkStatus := LinkSMSQueued
+	if msg.Attempts >= msg.MaxAttempts || !isRetryableSMSError(sendErr) {
+		status = SMSStatusDead
+		linkStatus = LinkSMSFailed
+	}
+
+	nextAttempt := time.Now().Add(s.backoff(msg.Attempts))
+	if err := s.outboxRepo.MarkFailed(ctx, id, status, sendErr.Error(), nextAttempt); err != nil {
+		return err
+	}
+	s.updateLinkSMS(ctx, msg, linkStatus)
+
+	if status == SMSStatusDead {
+		log.Error().Err(sendErr).Int("id", id).Str("mobile", msg.Mobile).Msg("SMS moved to dead letter")
+	}
+
+	return sendErr
+}
+
+// backoff returns the delay before the next attempt
+func (s *SmsOutboxService) backoff(attempts int) time.Duration {
+	delay := s.cfg.SMSOutbox.BackoffBase
+	if delay <= 0 {
+		delay = 30 * time.Second
+	}
+
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if s.cfg.SMSOutbox.BackoffMax > 0 && delay >= s.cfg.SMSOutbox.BackoffMax {
+			return s.cfg.SMSOutbox.BackoffMax
+		}
+	}
+
+	return delay
+}
+
+// updateLinkSMS mirrors the delivery result on the link the SMS belongs to
+func (s *SmsOutboxService) updateLinkSMS(ctx context.Context, msg *models.SmsOutbox, status int) {
+	if !msg.LinkID.Valid || msg.LinkID.String == "" {
+		return
+	}
+
+	if err := s.linkRepo.UpdateSMS(ctx, msg.LinkID.String, status); err != nil {
+		log.Error().Err(err).Str("linkID", msg.LinkID.String).Msg("Failed to update link SMS status")
+	}
+}
+
+// isRetryableSMSError reports whether a send error may succeed on a later attempt
+func isRetryableSMSError(err error) bool {
+	return !errors.Is(err, ErrSMSDisabled) &&
+		!errors.Is(err, sms.ErrUnknownProvider) &&
+		!errors.Is(err, sms.ErrProviderNotConfigured)
+}
+
+// Start starts the outbox worker
+func (s *SmsOutboxService) Start() {
+	interval := s.cfg.SMSOutbox.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	s.ticker = time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval*5)
+				if err := s.ProcessDue(ctx); err != nil {
+					log.Error().Err(err).Msg("SMS outbox worker failed")
+				}
+				cancel()
+			}
+		}
+	}()
+
+	log.Info().Dur("interval", interval).Msg("SMS outbox worker started")
+	if s.cfg.SMSDLRSecret == "" {
+		log.Warn().Msg("SMS_DLR_SECRET is not set, SMS delivery reports will be rejected")
+	}
+}
+
+// Stop stops the outbox worker
+func (s *SmsOutboxService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		if s.ticker != nil {
+			s.ticker.Stop()
+		}
+		log.Info().Msg("SMS outbox worker stopped")
+	})
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"api-gateway-go/internal/config"
+)
+
+func TestVerifyDeliveryReport(t *testing.T) {
+	body := []byte(`{"messageId":"abc","status":"DELIVRD"}`)
+	mac := hmac.New(sha256.New, []byte("dlr-secret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		token     string
+		want      error
+	}{
+		{"valid signature", "dlr-secret", signature, "", nil},
+		{"valid token", "dlr-secret", "", "dlr-secret", nil},
+		{"wrong signature", "dlr-secret", hex.EncodeToString([]byte("nope")), "", ErrDeliveryReportSignature},
+		{"wrong signature ignores token", "dlr-secret", "00", "dlr-secret", ErrDeliveryReportSignature},
+		{"wrong token", "dlr-secret", "", "guess", ErrDeliveryReportSignature},
+		{"unsigned", "dlr-secret", "", "", ErrDeliveryReportSignature},
+		{"no secret configured", "", signature, "dlr-secret", ErrDeliveryReportSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SmsOutboxService{cfg: &config.Config{SMSDLRSecret: tt.secret}}
+			if err := s.VerifyDeliveryReport(tt.signature, tt.token, body); !errors.Is(err, tt.want) {
+				t.Fatalf("VerifyDeliveryReport() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,12 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
-	"io"
-	"net/http"
-	"time"
+	"sync"
 
 	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/sms"
 )
 
 var (
@@ -19,61 +16,73 @@ var (
 
 // SMSService handles SMS sending
 type SMSService struct {
-	cfg        *config.Config
-	httpClient *http.Client
+	cfg       *config.Config
+	providers map[string]sms.Provider
+	mu        sync.Mutex
 }
 
 // NewSMSService creates a new SMSService
 func NewSMSService(cfg *config.Config) *SMSService {
 	return &SMSService{
-		cfg: cfg,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		cfg:       cfg,
+		providers: make(map[string]sms.Provider),
 	}
 }
 
 // SendSMS sends an SMS message
 func (s *SMSService) SendSMS(ctx context.Context, phoneNumber, message string) error {
+	return s.SendSMSWithSender(ctx, phoneNumber, "", message)
+}
+
+// SendSMSWithSender sends an SMS message using the given sender name
+func (s *SMSService) SendSMSWithSender(ctx context.Context, phoneNumber, sender, message string) error {
+	_, err := s.Send(ctx, "", sms.Message{
+		To:     phoneNumber,
+		Sender: sender,
+		Text:   message,
+	})
+	return err
+}
+
+// Send sends an SMS message through the named provider and returns the provider message ID.
+// An empty provider name uses the default provider from SMS_PROVIDER.
+func (s *SMSService) Send(ctx context.Context, providerName string, msg sms.Message) (string, error) {
 	if !s.cfg.SMSEnable {
-		return ErrSMSDisabled
+		return "", ErrSMSDisabled
 	}
 
-	if s.cfg.SMSAPIURL == "" {
-		return ErrSMSDisabled
+	provider, err := s.GetProvider(providerName)
+	if err != nil {
+		return "", err
 	}
 
-	// Prepare request body
-	body := map[string]string{
-		"phoneNumber": phoneNumber,
-		"message":     message,
+	if msg.Sender == "" {
+		msg.Sender = s.cfg.SMSSender
 	}
 
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return err
-	}
+	return provider.Send(ctx, msg)
+}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", s.cfg.SMSAPIURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return err
+// GetProvider returns the named provider, creating it on first use
+func (s *SMSService) GetProvider(name string) (sms.Provider, error) {
+	if name == "" {
+		name = s.cfg.SMSProvider
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	// Send request
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return err
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if provider, ok := s.providers[name]; ok {
+		return provider, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return errors.New("SMS API error: " + string(body))
+	provider, err := sms.NewProvider(name, s.cfg)
+	if err != nil {
+		return nil, err
 	}
+	s.providers[name] = provider
 
-	return nil
+	return provider, nil
 }
 
 // SendCustomMessage sends a custom SMS message
@@ -83,5 +92,9 @@ func (s *SMSService) SendCustomMessage(ctx context.Context, phoneNumber, message
 
 // IsEnabled returns whether SMS is enabled
 func (s *SMSService) IsEnabled() bool {
-	return s.cfg.SMSEnable && s.cfg.SMSAPIURL != ""
+	if !s.cfg.SMSEnable {
+		return false
+	}
+	_, err := s.GetProvider("")
+	return err == nil
 }
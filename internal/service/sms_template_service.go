@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/pkg/utils"
+)
+
+// SMS message types
+const (
+	SMSTypeVideoInvite     = "video"
+	SMSTypeLocationRequest = "location"
+	SMSTypeHLSRecord       = "hls"
+	SMSTypeReminder        = "reminder"
+)
+
+// SMS template locales
+const (
+	SMSLocaleThai    = "th"
+	SMSLocaleEnglish = "en"
+)
+
+var (
+	ErrSMSTemplateNotFound        = errors.New("SMS template not found")
+	ErrSMSTemplateInvalidType     = errors.New("invalid SMS message type")
+	ErrSMSTemplateInvalidLocale   = errors.New("invalid SMS template locale")
+	ErrSMSTemplateBodyRequired    = errors.New("SMS template body required")
+	ErrSMSTemplateUnknownVariable = errors.New("unknown SMS template variable")
+	ErrSMSTemplateDefaultAdmin    = errors.New("default SMS templates are managed by administrators only")
+)
+
+// templateVariablePattern matches {{variable}} placeholders
+var templateVariablePattern = regexp.MustCompile(`{{\s*(\w+)\s*}}`)
+
+// smsTemplateVariables lists the variables templates may use
+var smsTemplateVariables = map[string]bool{
+	"linkUrl":   true,
+	"room":      true,
+	"agentName": true,
+	"expiry":    true,
+	"prefix":    true,
+	"mobile":    true,
+}
+
+// SMSTemplateOptions holds options for creating or updating a template
+type SMSTemplateOptions struct {
+	Service     int
+	MessageType string
+	Locale      string
+	Body        string
+	Enabled     *bool
+	UpdatedBy   string
+}
+
+// RenderedSMS is a rendered template with its segment information
+type RenderedSMS struct {
+	TemplateID uint                 `json:"templateId,omitempty"`
+	Message    string               `json:"message"`
+	Segments   utils.SMSSegmentInfo `json:"segments"`
+}
+
+// SmsTemplateService handles SMS templates
+type SmsTemplateService struct {
+	templateRepo *repository.SmsTemplateRepository
+	cfg          *config.Config
+}
+
+// NewSmsTemplateService creates a new SmsTemplateService
+func NewSmsTemplateService(templateRepo *repository.SmsTemplateRepository, cfg *config.Config) *SmsTemplateService {
+	return &SmsTemplateService{
+		templateRepo: templateRepo,
+		cfg:          cfg,
+	}
+}
+
+// ListTemplates lists templates. A negative service lists all services.
+func (s *SmsTemplateService) ListTemplates(ctx context.Context, service int, messageType string) ([]models.SmsTemplate, error) {
+	return s.templateRepo.List(ctx, service, messageType)
+}
+
+// GetTemplate gets a template by ID
+func (s *SmsTemplateService) GetTemplate(ctx context.Context, id int) (*models.SmsTemplate, error) {
+	tpl, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tpl == nil {
+		return nil, ErrSMSTemplateNotFound
+	}
+	return tpl, nil
+}
+
+// CreateTemplate creates a template. Scoped callers create templates of their own service only.
+func (s *SmsTemplateService) CreateTemplate(ctx context.Context, opts SMSTemplateOptions) (*models.SmsTemplate, error) {
+	service, err := templateService(ctx, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+	opts.Service = service
+
+	params, err := s.templateParams(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.templateRepo.Create(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTemplate(ctx, int(id))
+}
+
+// UpdateTemplate updates a template
+func (s *SmsTemplateService) UpdateTemplate(ctx context.Context, id int, opts SMSTemplateOptions) (*models.SmsTemplate, error) {
+	existing, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := templateService(ctx, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+	opts.Service = service
+
+	// Keep the current enabled flag unless the caller sets it
+	if opts.Enabled == nil {
+		enabled := utils.NullIntValue(existing.Enabled) == 1
+		opts.Enabled = &enabled
+	}
+
+	params, err := s.templateParams(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Update(ctx, id, params); err != nil {
+		return nil, err
+	}
+
+	return s.GetTemplate(ctx, id)
+}
+
+// DeleteTemplate deletes a template
+func (s *SmsTemplateService) DeleteTemplate(ctx context.Context, id int) error {
+	if _, err := s.GetTemplate(ctx, id); err != nil {
+		return err
+	}
+	if _, err := templateService(ctx, 0); err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(ctx, id)
+}
+
+// templateService returns the service a template is written for. Scoped callers
+// write only their own service; the service 0 defaults, used by every tenant
+// without its own template, are left to unscoped administrators.
+func templateService(ctx context.Context, requested int) (int, error) {
+	tenant, ok := repository.TenantFromContext(ctx)
+	if !ok {
+		return requested, nil
+	}
+	if tenant.Service == 0 {
+		return 0, ErrSMSTemplateDefaultAdmin
+	}
+	return tenant.Service, nil
+}
+
+// Render renders the template for a service, message type and locale.
+// Returns nil when no template is configured.
+func (s *SmsTemplateService) Render(ctx context.Context, service int, messageType, locale string, vars map[string]string) (*RenderedSMS, error) {
+	if locale == "" {
+		locale = s.cfg.SMSDefaultLocale
+	}
+
+	tpl, err := s.templateRepo.Find(ctx, service, messageType, locale, s.cfg.SMSDefaultLocale)
+	if err != nil || tpl == nil {
+		return nil, err
+	}
+
+	rendered := s.Preview(tpl.Body, vars)
+	rendered.TemplateID = tpl.ID
+
+	return rendered, nil
+}
+
+// PreviewStored renders the stored template a service would send. Scoped
+// callers preview their own service, falling back to the defaults.
+func (s *SmsTemplateService) PreviewStored(ctx context.Context, service int, messageType, locale string, vars map[string]string) (*RenderedSMS, error) {
+	if tenant, ok := repository.TenantFromContext(ctx); ok {
+		service = tenant.Service
+	}
+	return s.Render(ctx, service, messageType, locale, vars)
+}
+
+// Preview renders a template body and calculates its segments
+func (s *SmsTemplateService) Preview(body string, vars map[string]string) *RenderedSMS {
+	message := templateVariablePattern.ReplaceAllStringFunc(body, func(match string) string {
+		name := templateVariablePattern.FindStringSubmatch(match)[1]
+		return vars[name]
+	})
+	message = strings.TrimSpace(message)
+
+	return &RenderedSMS{
+		Message:  message,
+		Segments: utils.CalculateSMSSegments(message),
+	}
+}
+
+// templateParams validates template options
+func (s *SmsTemplateService) templateParams(opts SMSTemplateOptions) (repository.SmsTemplateParams, error) {
+	switch opts.MessageType {
+	case SMSTypeVideoInvite, SMSTypeLocationRequest, SMSTypeHLSRecord, SMSTypeReminder:
+	default:
+		return repository.SmsTemplateParams{}, ErrSMSTemplateInvalidType
+	}
+
+	locale := opts.Locale
+	if locale == "" {
+		locale = s.cfg.SMSDefaultLocale
+	}
+	if locale != SMSLocaleThai && locale != SMSLocaleEnglish {
+		return repository.SmsTemplateParams{}, ErrSMSTemplateInvalidLocale
+	}
+
+	if strings.TrimSpace(opts.Body) == "" {
+		return repository.SmsTemplateParams{}, ErrSMSTemplateBodyRequired
+	}
+
+	for _, match := range templateVariablePattern.FindAllStringSubmatch(opts.Body, -1) {
+		if !smsTemplateVariables[match[1]] {
+			return repository.SmsTemplateParams{}, ErrSMSTemplateUnknownVariable
+		}
+	}
+
+	enabled := 1
+	if opts.Enabled != nil && !*opts.Enabled {
+		enabled = 0
+	}
+
+	return repository.SmsTemplateParams{
+		Service:     opts.Service,
+		MessageType: opts.MessageType,
+		Locale:      locale,
+		Body:        opts.Body,
+		Enabled:     enabled,
+		UpdatedBy:   opts.UpdatedBy,
+	}, nil
+}
+
+// smsMessageType maps a link user type to its SMS message type
+func smsMessageType(userType string) string {
+	switch userType {
+	case "hls":
+		return SMSTypeHLSRecord
+	case "location":
+		return SMSTypeLocationRequest
+	default:
+		return SMSTypeVideoInvite
+	}
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrAccountLocked      = errors.New("account is locked")
+	ErrAccountDisabled    = errors.New("account is disabled")
+	ErrStaffNotFound      = errors.New("staff account not found")
+	ErrStaffExists        = errors.New("staff account already exists")
+	ErrWeakPassword       = errors.New("password must be at least 8 characters")
+	ErrUserNameRequired   = errors.New("userName is required")
+	ErrInvalidRefresh     = errors.New("invalid or expired refresh token")
+	ErrRefreshReused      = errors.New("refresh token was already used")
+	ErrInvalidRole        = errors.New("invalid role")
+)
+
+// Staff login error codes returned to API clients
+const (
+	AuthCodeInvalidCredentials = "AUTH_INVALID_CREDENTIALS"
+	AuthCodeAccountLocked      = "AUTH_ACCOUNT_LOCKED"
+	AuthCodeAccountDisabled    = "AUTH_ACCOUNT_DISABLED"
+	AuthCodeInvalidRefresh     = "AUTH_REFRESH_INVALID"
+	AuthCodeRefreshReused      = "AUTH_REFRESH_REUSED"
+)
+
+// minPasswordLength is the shortest password accepted for a staff account
+const minPasswordLength = 8
+
+// dummyPasswordHash is compared against when the user name is unknown so that
+// failed logins take the same time whether or not the account exists
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+// LoginOptions holds options for a staff login
+type LoginOptions struct {
+	UserName  string
+	Password  string
+	UserAgent string
+	IP        string
+}
+
+// RefreshOptions holds options for exchanging a refresh token
+type RefreshOptions struct {
+	RefreshToken string
+	UserAgent    string
+	IP           string
+}
+
+// LoginResult is returned by a successful staff login
+type LoginResult struct {
+	Verified         bool                 `json:"verified"`
+	AccessToken      string               `json:"accessToken"`
+	TokenType        string               `json:"tokenType"`
+	ExpiresIn        int64                `json:"expiresIn"`
+	RefreshToken     string               `json:"refreshToken"`
+	RefreshExpiresAt time.Time            `json:"refreshExpiresAt"`
+	Staff            *models.StaffAccount `json:"staff"`
+}
+
+// CreateStaffOptions holds options for creating a staff account
+type CreateStaffOptions struct {
+	UserName     string
+	Password     string
+	DisplayName  string
+	Role         string
+	Service      int
+	Organization string
+	CreatedBy    string
+}
+
+// StaffService handles staff accounts and login
+type StaffService struct {
+	staffRepo   *repository.StaffRepository
+	authService *AuthService
+	cfg         *config.Config
+}
+
+// NewStaffService creates a new StaffService
+func NewStaffService(staffRepo *repository.StaffRepository, authService *AuthService, cfg *config.Config) *StaffService {
+	return &StaffService{
+		staffRepo:   staffRepo,
+		authService: authService,
+		cfg:         cfg,
+	}
+}
+
+// Login verifies staff credentials and issues an access token and a refresh token.
+// Repeated failures lock the account for the configured lockout duration.
+func (s *StaffService) Login(ctx context.Context, opts LoginOptions) (*LoginResult, error) {
+	userName := strings.TrimSpace(opts.UserName)
+	if userName == "" || opts.Password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	staff, err := s.staffRepo.GetByUserName(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+	if staff == nil {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(opts.Password))
+		return nil, ErrInvalidCredentials
+	}
+
+	if staff.DtmLocked.Valid && staff.DtmLocked.Time.After(time.Now()) {
+		return nil, ErrAccountLocked
+	}
+
+	if !utils.IsPasswordHash(staff.PasswordHash) || !utils.VerifyPassword(staff.PasswordHash, opts.Password) {
+		lockUntil := time.Now().Add(s.cfg.Auth.LockoutDuration).Format("2006-01-02 15:04:05")
+		if err := s.staffRepo.RecordFailedLogin(ctx, staff.ID, s.cfg.Auth.MaxFailedLogins, lockUntil); err != nil {
+			log.Error().Err(err).Uint("staffId", staff.ID).Msg("Failed to record failed login")
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	// Only reveal a disabled account once the password is proven
+	if utils.NullIntValue(staff.Enabled) != 1 {
+		return nil, ErrAccountDisabled
+	}
+
+	if err := s.staffRepo.RecordLogin(ctx, staff.ID); err != nil {
+		return nil, err
+	}
+
+	familyID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := s.issueTokens(ctx, staff, familyID, opts.UserAgent, opts.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("userName", staff.UserName).Msg("Staff logged in")
+	return result, nil
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// access token and refresh token are issued. Presenting a token that was
+// already rotated revokes every token from the same login.
+func (s *StaffService) Refresh(ctx context.Context, opts RefreshOptions) (*LoginResult, error) {
+	if opts.RefreshToken == "" {
+		return nil, ErrInvalidRefresh
+	}
+
+	current, err := s.staffRepo.GetRefreshTokenByHash(ctx, hashSecret(opts.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrInvalidRefresh
+	}
+	familyID := utils.NullStringValue(current.FamilyID)
+
+	if current.DtmRevoked.Valid {
+		if current.ReplacedBy.Valid {
+			return nil, s.revokeReusedFamily(ctx, current)
+		}
+		return nil, ErrInvalidRefresh
+	}
+	if !current.DtmExpired.Valid || current.DtmExpired.Time.Before(time.Now()) {
+		return nil, ErrInvalidRefresh
+	}
+
+	staff, err := s.staffRepo.GetByID(ctx, current.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	if staff == nil || utils.NullIntValue(staff.Enabled) != 1 {
+		if err := s.staffRepo.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrAccountDisabled
+	}
+
+	result, nextID, err := s.issueTokens(ctx, staff, familyID, opts.UserAgent, opts.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lost the race to a concurrent refresh with the same token
+	replaced, err := s.staffRepo.ReplaceRefreshToken(ctx, current.ID, nextID)
+	if err != nil {
+		return nil, err
+	}
+	if !replaced {
+		return nil, s.revokeReusedFamily(ctx, current)
+	}
+
+	return result, nil
+}
+
+// revokeReusedFamily revokes a token family after a rotated refresh token was presented again
+func (s *StaffService) revokeReusedFamily(ctx context.Context, token *models.StaffRefreshToken) error {
+	log.Warn().Uint("staffId", token.StaffID).Uint("tokenId", token.ID).Msg("Refresh token reuse detected, revoking session")
+
+	if err := s.staffRepo.RevokeRefreshTokenFamily(ctx, utils.NullStringValue(token.FamilyID)); err != nil {
+		return err
+	}
+	return ErrRefreshReused
+}
+
+// Logout revokes the caller's access token and, when given, the refresh token of the same session
+func (s *StaffService) Logout(ctx context.Context, claims *Claims, refreshToken string) error {
+	if err := s.authService.RevokeToken(ctx, claims); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := s.staffRepo.GetRefreshTokenByHash(ctx, hashSecret(refreshToken))
+	if err != nil {
+		return err
+	}
+	if token == nil || token.StaffID != claims.StaffID {
+		return nil
+	}
+	return s.staffRepo.RevokeRefreshTokenFamily(ctx, utils.NullStringValue(token.FamilyID))
+}
+
+// RevokeSessions revokes every refresh token and access token of a staff account
+func (s *StaffService) RevokeSessions(ctx context.Context, id uint) error {
+	if _, err := s.GetStaff(ctx, id); err != nil {
+		return err
+	}
+	return s.revokeSessions(ctx, id)
+}
+
+func (s *StaffService) revokeSessions(ctx context.Context, id uint) error {
+	if err := s.staffRepo.RevokeRefreshTokens(ctx, id); err != nil {
+		return err
+	}
+	if err := s.authService.RevokeStaffTokens(ctx, id, s.cfg.Auth.AccessTokenTTL); err != nil {
+		return err
+	}
+
+	log.Info().Uint("staffId", id).Msg("Staff sessions revoked")
+	return nil
+}
+
+// issueTokens creates an access token and a refresh token in the given family
+func (s *StaffService) issueTokens(ctx context.Context, staff *models.StaffAccount, familyID, userAgent, ip string) (*LoginResult, int64, error) {
+	accessToken, err := s.authService.CreateStaffToken(ctx, staff, s.cfg.Auth.AccessTokenTTL)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, 0, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(buf)
+	refreshExpiresAt := time.Now().Add(s.cfg.Auth.RefreshTokenTTL)
+
+	id, err := s.staffRepo.CreateRefreshToken(ctx, repository.CreateRefreshTokenParams{
+		StaffID:    staff.ID,
+		TokenHash:  hashSecret(refreshToken),
+		FamilyID:   familyID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		DtmExpired: refreshExpiresAt.Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &LoginResult{
+		Verified:         true,
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(s.cfg.Auth.AccessTokenTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		Staff:            staff,
+	}, id, nil
+}
+
+// hashSecret returns the hex SHA-256 of a refresh token or API key as stored in the database
+func hashSecret(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ListStaff lists staff accounts. A negative service lists all services.
+func (s *StaffService) ListStaff(ctx context.Context, service int) ([]models.StaffAccount, error) {
+	return s.staffRepo.List(ctx, service)
+}
+
+// GetStaff gets a staff account by ID
+func (s *StaffService) GetStaff(ctx context.Context, id uint) (*models.StaffAccount, error) {
+	staff, err := s.staffRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if staff == nil {
+		return nil, ErrStaffNotFound
+	}
+	return staff, nil
+}
+
+// CreateStaff creates a staff account with a bcrypt-hashed password
+func (s *StaffService) CreateStaff(ctx context.Context, opts CreateStaffOptions) (*models.StaffAccount, error) {
+	userName := strings.TrimSpace(opts.UserName)
+	if userName == "" {
+		return nil, ErrUserNameRequired
+	}
+	if len(opts.Password) < minPasswordLength {
+		return nil, ErrWeakPassword
+	}
+	role, err := staffRole(opts.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.staffRepo.GetByUserName(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrStaffExists
+	}
+
+	hash, err := utils.HashPassword(opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.staffRepo.Create(ctx, repository.CreateStaffParams{
+		UserName:     userName,
+		PasswordHash: hash,
+		DisplayName:  opts.DisplayName,
+		Role:         string(role),
+		Service:      opts.Service,
+		Organization: opts.Organization,
+		CreatedBy:    opts.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.staffRepo.GetByID(ctx, uint(id))
+}
+
+// ChangePassword sets a new password, clears any lockout and revokes existing sessions
+func (s *StaffService) ChangePassword(ctx context.Context, id uint, password string) error {
+	if len(password) < minPasswordLength {
+		return ErrWeakPassword
+	}
+	if _, err := s.GetStaff(ctx, id); err != nil {
+		return err
+	}
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.staffRepo.UpdatePassword(ctx, id, hash); err != nil {
+		return err
+	}
+	return s.revokeSessions(ctx, id)
+}
+
+// SetEnabled enables or disables a staff account. Disabling revokes its sessions.
+func (s *StaffService) SetEnabled(ctx context.Context, id uint, enabled bool) error {
+	if _, err := s.GetStaff(ctx, id); err != nil {
+		return err
+	}
+
+	if !enabled {
+		if err := s.staffRepo.SetEnabled(ctx, id, 0); err != nil {
+			return err
+		}
+		return s.revokeSessions(ctx, id)
+	}
+	return s.staffRepo.SetEnabled(ctx, id, 1)
+}
+
+// SetRole changes the role of a staff account. Existing sessions are revoked so
+// that the new role applies from the next login.
+func (s *StaffService) SetRole(ctx context.Context, id uint, roleName string) error {
+	role, err := staffRole(roleName)
+	if err != nil {
+		return err
+	}
+	if _, err := s.GetStaff(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.staffRepo.SetRole(ctx, id, string(role)); err != nil {
+		return err
+	}
+	return s.revokeSessions(ctx, id)
+}
+
+// staffRole validates the role of a staff account. Staff default to agents
+// and can never hold the device or guest roles.
+func staffRole(name string) (Role, error) {
+	if name == "" {
+		return RoleAgent, nil
+	}
+	switch role := Role(name); role {
+	case RoleAdmin, RoleSupervisor, RoleAgent:
+		return role, nil
+	}
+	return "", ErrInvalidRole
+}
+
+// UnlockStaff clears a lockout caused by failed logins
+func (s *StaffService) UnlockStaff(ctx context.Context, id uint) error {
+	if _, err := s.GetStaff(ctx, id); err != nil {
+		return err
+	}
+	return s.staffRepo.Unlock(ctx, id)
+}
+
+// EnsureBootstrapAdmin creates the configured bootstrap account if it does not exist yet
+func (s *StaffService) EnsureBootstrapAdmin(ctx context.Context) error {
+	userName := s.cfg.Auth.AdminUserName
+	if userName == "" || s.cfg.Auth.AdminPassword == "" {
+		return nil
+	}
+
+	existing, err := s.staffRepo.GetByUserName(ctx, userName)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	_, err = s.CreateStaff(ctx, CreateStaffOptions{
+		UserName:    userName,
+		Password:    s.cfg.Auth.AdminPassword,
+		DisplayName: userName,
+		Role:        string(RoleAdmin),
+		CreatedBy:   "system",
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("userName", userName).Msg("Created bootstrap staff account")
+	return nil
+}
+
+// AuthErrorCode returns the API error code for a staff login rejection, or "" for other errors
+func AuthErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidCredentials):
+		return AuthCodeInvalidCredentials
+	case errors.Is(err, ErrAccountLocked):
+		return AuthCodeAccountLocked
+	case errors.Is(err, ErrAccountDisabled):
+		return AuthCodeAccountDisabled
+	case errors.Is(err, ErrInvalidRefresh):
+		return AuthCodeInvalidRefresh
+	case errors.Is(err, ErrRefreshReused):
+		return AuthCodeRefreshReused
+	default:
+		return ""
+	}
+}
@@ -138,6 +138,18 @@ func (s *UserService) GenerateLiveKitToken(ctx context.Context, identity, name,
 	return s.livekitMgr.GenerateToken(identity, name, room, grants, "", 24*time.Hour)
 }
 
+// RoomInTenant reports whether a room exists within the tenant of the request
+func (s *UserService) RoomInTenant(ctx context.Context, room string) (bool, error) {
+	if room == "" {
+		return false, nil
+	}
+	roomConf, err := s.roomRepo.GetByRoom(ctx, room)
+	if err != nil {
+		return false, err
+	}
+	return roomConf != nil, nil
+}
+
 // GetUserDetail gets user details
 func (s *UserService) GetUserDetail(ctx context.Context, room, identity, socketID string) (*models.RoomUser, error) {
 	user, err := s.userRepo.GetUserDetail(ctx, room, identity, socketID)
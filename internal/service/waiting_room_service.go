@@ -0,0 +1,559 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+	"api-gateway-go/pkg/utils"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Join request statuses
+const (
+	JoinStatusPending  = "pending"
+	JoinStatusApproved = "approved"
+	JoinStatusDenied   = "denied"
+	JoinStatusExpired  = "expired"
+	JoinStatusJoined   = "joined"
+)
+
+// Join request statuses written to usage_status_log
+const (
+	LinkStatusJoinRequested = "join-requested"
+	LinkStatusJoinApproved  = "join-approved"
+	LinkStatusJoinDenied    = "join-denied"
+	LinkStatusJoinExpired   = "join-expired"
+)
+
+var (
+	ErrWaitingRoomUnavailable  = errors.New("waiting room unavailable")
+	ErrJoinRequestNotFound     = errors.New("join request not found")
+	ErrJoinRequestDecided      = errors.New("join request already decided")
+	ErrJoinRequestNotApproved  = errors.New("join request not approved")
+	ErrJoinRequestAlreadyTaken = errors.New("join request already used")
+)
+
+// waitingRoomKeyPrefix is the Redis key prefix for waiting room state
+const waitingRoomKeyPrefix = "waitingroom:"
+
+// JoinRequest is a guest waiting for an agent to let them into a room
+type JoinRequest struct {
+	ID        string     `json:"id"`
+	LinkID    string     `json:"linkID"`
+	Room      string     `json:"room"`
+	Service   int        `json:"service,omitempty"`
+	UserName  string     `json:"userName"`
+	UserType  string     `json:"userType"`
+	UserAgent string     `json:"userAgent,omitempty"`
+	Status    string     `json:"status"`
+	DecidedBy string     `json:"decidedBy,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	DecidedAt *time.Time `json:"decidedAt,omitempty"`
+}
+
+// JoinTicket is a new join request together with the poll token only the guest
+// receives. Request IDs are shown to the room's agents; the poll token is never
+// broadcast, and the guest needs it to read the request and claim their token.
+type JoinTicket struct {
+	*JoinRequest
+	PollToken string `json:"pollToken"`
+}
+
+// CreateJoinRequestOptions holds options for queueing a guest
+type CreateJoinRequestOptions struct {
+	Link      *models.LinkConnect
+	UserName  string
+	UserType  string
+	UserAgent string
+}
+
+// WaitingRoomNotifier pushes waiting room changes to connected agents and guests
+type WaitingRoomNotifier interface {
+	NotifyJoinRequest(req *JoinRequest)
+	NotifyJoinAnswer(req *JoinRequest)
+}
+
+// WaitingRoomService queues guests of links with requireJoinPermission until an
+// agent approves them. The guest's link is only used up when the approved guest
+// claims their token, so a denied or expired guest may ask again with the same link.
+// Requests are kept with the service of their room, and agents scoped to a tenant
+// only see and decide the requests of their own service.
+type WaitingRoomService struct {
+	redis       *config.RedisManager
+	linkService *LinkService
+	roomRepo    *repository.RoomRepository
+	usageLog    *repository.UsageLogRepository
+	cfg         *config.Config
+	notifier    WaitingRoomNotifier
+	ticker      *time.Ticker
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+// NewWaitingRoomService creates a new WaitingRoomService
+func NewWaitingRoomService(redis *config.RedisManager, linkService *LinkService, roomRepo *repository.RoomRepository, usageLog *repository.UsageLogRepository, cfg *config.Config) *WaitingRoomService {
+	return &WaitingRoomService{
+		redis:       redis,
+		linkService: linkService,
+		roomRepo:    roomRepo,
+		usageLog:    usageLog,
+		cfg:         cfg,
+		done:        make(chan struct{}),
+	}
+}
+
+// SetNotifier sets the notifier used to reach agents and guests
+func (s *WaitingRoomService) SetNotifier(notifier WaitingRoomNotifier) {
+	s.notifier = notifier
+}
+
+// RequiresPermission reports whether guests of a link must wait for approval
+func RequiresPermission(link *models.LinkConnect) bool {
+	return utils.NullIntValue(link.RequireJoinPermission) == 1
+}
+
+// CreateRequest puts a guest in the waiting room and notifies the room's agents
+func (s *WaitingRoomService) CreateRequest(ctx context.Context, opts CreateJoinRequestOptions) (*JoinTicket, error) {
+	if s.redis == nil {
+		return nil, ErrWaitingRoomUnavailable
+	}
+
+	id, err := newJoinRequestID()
+	if err != nil {
+		return nil, err
+	}
+	pollToken, err := newJoinRequestID()
+	if err != nil {
+		return nil, err
+	}
+
+	room := utils.NullStringValue(opts.Link.Room)
+	service, err := s.roomService(ctx, room, opts.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	req := &JoinRequest{
+		ID:        id,
+		LinkID:    utils.NullStringValue(opts.Link.LinkID),
+		Room:      room,
+		Service:   service,
+		UserName:  opts.UserName,
+		UserType:  opts.UserType,
+		UserAgent: opts.UserAgent,
+		Status:    JoinStatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.cfg.WaitingRoom.Timeout),
+	}
+
+	if err := s.save(ctx, req); err != nil {
+		return nil, err
+	}
+
+	client := s.redis.StateClient()
+	pipe := client.TxPipeline()
+	pipe.Set(ctx, s.requestKey(req.ID)+":poll", hashPollToken(pollToken), time.Until(req.ExpiresAt)+s.cfg.WaitingRoom.Retention)
+	pipe.ZAdd(ctx, s.roomKey(req.Room), redis.Z{Score: float64(req.ExpiresAt.Unix()), Member: req.ID})
+	pipe.SAdd(ctx, waitingRoomKeyPrefix+"rooms", req.Room)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, req, LinkStatusJoinRequested, req.UserName)
+	if s.notifier != nil {
+		s.notifier.NotifyJoinRequest(req)
+	}
+
+	return &JoinTicket{JoinRequest: req, PollToken: pollToken}, nil
+}
+
+// GetGuestRequest gets a join request for the guest who created it. A wrong
+// poll token is reported as a missing request.
+func (s *WaitingRoomService) GetGuestRequest(ctx context.Context, id, pollToken string) (*JoinRequest, error) {
+	if s.redis == nil {
+		return nil, ErrWaitingRoomUnavailable
+	}
+	if pollToken == "" {
+		return nil, ErrJoinRequestNotFound
+	}
+
+	stored, err := s.redis.StateClient().Get(ctx, s.requestKey(id)+":poll").Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrJoinRequestNotFound
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashPollToken(pollToken))) != 1 {
+		return nil, ErrJoinRequestNotFound
+	}
+
+	return s.GetRequest(ctx, id)
+}
+
+// GetRequest gets a join request, expiring it if its timeout has passed
+func (s *WaitingRoomService) GetRequest(ctx context.Context, id string) (*JoinRequest, error) {
+	req, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status == JoinStatusPending && time.Now().After(req.ExpiresAt) {
+		expired, err := s.decide(ctx, id, JoinStatusExpired, "system", "timeout")
+		if err == nil {
+			return expired, nil
+		}
+		if !errors.Is(err, ErrJoinRequestDecided) {
+			return nil, err
+		}
+		return s.load(ctx, id)
+	}
+
+	return req, nil
+}
+
+// ListPending lists the guests waiting for a room, oldest first
+func (s *WaitingRoomService) ListPending(ctx context.Context, room string) ([]*JoinRequest, error) {
+	if s.redis == nil {
+		return nil, ErrWaitingRoomUnavailable
+	}
+
+	ids, err := s.redis.StateClient().ZRange(ctx, s.roomKey(room), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]*JoinRequest, 0, len(ids))
+	for _, id := range ids {
+		req, err := s.GetRequest(ctx, id)
+		if err != nil {
+			continue
+		}
+		if req.Status == JoinStatusPending && inTenant(ctx, req) {
+			requests = append(requests, req)
+		}
+	}
+
+	return requests, nil
+}
+
+// Approve lets a waiting guest into the room
+func (s *WaitingRoomService) Approve(ctx context.Context, id, decidedBy string) (*JoinRequest, error) {
+	return s.decide(ctx, id, JoinStatusApproved, decidedBy, "")
+}
+
+// Deny refuses a waiting guest
+func (s *WaitingRoomService) Deny(ctx context.Context, id, decidedBy, reason string) (*JoinRequest, error) {
+	return s.decide(ctx, id, JoinStatusDenied, decidedBy, reason)
+}
+
+// Claim releases the token of an approved request only once. The request is
+// reserved while issue creates the guest's token; only when issue succeeds is
+// the guest's link used up and the request marked joined. A failed issue leaves
+// the request approved, so the guest can poll again.
+func (s *WaitingRoomService) Claim(ctx context.Context, id string, issue func(req *JoinRequest) error) (*JoinRequest, error) {
+	req, err := s.GetRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status == JoinStatusJoined {
+		return nil, ErrJoinRequestAlreadyTaken
+	}
+	if req.Status != JoinStatusApproved {
+		return nil, ErrJoinRequestNotApproved
+	}
+
+	client := s.redis.StateClient()
+	claimedKey := s.requestKey(id) + ":claimed"
+	ok, err := client.SetNX(ctx, claimedKey, 1, s.cfg.WaitingRoom.Retention).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJoinRequestAlreadyTaken
+	}
+
+	if _, err := s.linkService.getUsableLink(ctx, req.LinkID); err != nil {
+		if LinkErrorCode(err) == "" {
+			client.Del(ctx, claimedKey)
+		}
+		return nil, err
+	}
+
+	if err := issue(req); err != nil {
+		client.Del(ctx, claimedKey)
+		return nil, err
+	}
+
+	if _, err := s.linkService.ClaimLink(ctx, req.LinkID, req.UserName); err != nil {
+		// Let the guest try again unless the link itself can no longer be used
+		if LinkErrorCode(err) == "" {
+			client.Del(ctx, claimedKey)
+		}
+		return nil, err
+	}
+
+	req.Status = JoinStatusJoined
+	if err := s.save(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// ExpireOverdue expires pending requests whose timeout has passed
+func (s *WaitingRoomService) ExpireOverdue(ctx context.Context) error {
+	if s.redis == nil {
+		return nil
+	}
+
+	client := s.redis.StateClient()
+	rooms, err := client.SMembers(ctx, waitingRoomKeyPrefix+"rooms").Result()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	for _, room := range rooms {
+		ids, err := client.ZRangeByScore(ctx, s.roomKey(room), &redis.ZRangeBy{
+			Min: "-inf",
+			Max: strconv.FormatInt(now, 10),
+		}).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			_, err := s.GetRequest(ctx, id)
+			switch {
+			case errors.Is(err, ErrJoinRequestNotFound):
+				client.ZRem(ctx, s.roomKey(room), id)
+			case err != nil:
+				log.Error().Err(err).Str("id", id).Msg("Failed to expire join request")
+			}
+		}
+
+		if count, err := client.ZCard(ctx, s.roomKey(room)).Result(); err == nil && count == 0 {
+			client.SRem(ctx, waitingRoomKeyPrefix+"rooms", room)
+		}
+	}
+
+	return nil
+}
+
+// decide records the outcome of a pending request. Only the first decision wins.
+func (s *WaitingRoomService) decide(ctx context.Context, id, status, decidedBy, reason string) (*JoinRequest, error) {
+	req, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !inTenant(ctx, req) {
+		return nil, ErrJoinRequestNotFound
+	}
+	if req.Status != JoinStatusPending {
+		return nil, ErrJoinRequestDecided
+	}
+
+	client := s.redis.StateClient()
+	ok, err := client.SetNX(ctx, s.requestKey(id)+":decision", status, s.cfg.WaitingRoom.Timeout+s.cfg.WaitingRoom.Retention).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJoinRequestDecided
+	}
+
+	now := time.Now()
+	req.Status = status
+	req.DecidedBy = decidedBy
+	req.Reason = reason
+	req.DecidedAt = &now
+
+	if err := s.save(ctx, req); err != nil {
+		return nil, err
+	}
+	client.ZRem(ctx, s.roomKey(req.Room), id)
+
+	switch status {
+	case JoinStatusApproved:
+		s.audit(ctx, req, LinkStatusJoinApproved, decidedBy)
+	case JoinStatusDenied:
+		s.audit(ctx, req, LinkStatusJoinDenied, decidedBy)
+	case JoinStatusExpired:
+		s.audit(ctx, req, LinkStatusJoinExpired, decidedBy)
+	}
+
+	if s.notifier != nil {
+		s.notifier.NotifyJoinAnswer(req)
+	}
+
+	return req, nil
+}
+
+// roomService returns the service of a room, falling back to the link's service
+// for rooms that are not in room_conference
+func (s *WaitingRoomService) roomService(ctx context.Context, room string, link *models.LinkConnect) (int, error) {
+	if s.roomRepo != nil {
+		roomConf, err := s.roomRepo.GetByRoom(ctx, room)
+		if err != nil {
+			return 0, err
+		}
+		if roomConf != nil && roomConf.Service.Valid {
+			return int(roomConf.Service.Int32), nil
+		}
+	}
+	return int(utils.NullIntValue(link.Service)), nil
+}
+
+// inTenant reports whether the caller may see and decide a request. Callers
+// scoped to a tenant only reach the requests of their own service.
+func inTenant(ctx context.Context, req *JoinRequest) bool {
+	tenant, ok := repository.TenantFromContext(ctx)
+	if !ok {
+		return true
+	}
+	return tenant.Service != 0 && tenant.Service == req.Service
+}
+
+// load reads a join request from Redis
+func (s *WaitingRoomService) load(ctx context.Context, id string) (*JoinRequest, error) {
+	if s.redis == nil {
+		return nil, ErrWaitingRoomUnavailable
+	}
+
+	data, err := s.redis.StateClient().Get(ctx, s.requestKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrJoinRequestNotFound
+		}
+		return nil, err
+	}
+
+	var req JoinRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// save writes a join request to Redis, keeping it until the retention period after its timeout
+func (s *WaitingRoomService) save(ctx context.Context, req *JoinRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(req.ExpiresAt) + s.cfg.WaitingRoom.Retention
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	return s.redis.StateClient().Set(ctx, s.requestKey(req.ID), data, ttl).Err()
+}
+
+// audit records a waiting room transition in usage_status_log
+func (s *WaitingRoomService) audit(ctx context.Context, req *JoinRequest, status, actor string) {
+	if s.usageLog == nil {
+		return
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"requestId": req.ID,
+		"guest":     req.UserName,
+		"reason":    req.Reason,
+	})
+
+	_, err := s.usageLog.AddStatusLog(ctx, repository.AddStatusLogParams{
+		LinkID:    req.LinkID,
+		Room:      req.Room,
+		UserName:  actor,
+		UserType:  req.UserType,
+		Status:    status,
+		UserAgent: req.UserAgent,
+		Data:      string(data),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("id", req.ID).Str("status", status).Msg("Failed to log join request")
+	}
+}
+
+// hashPollToken returns the form a poll token is stored in
+func hashPollToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *WaitingRoomService) requestKey(id string) string {
+	return waitingRoomKeyPrefix + "request:" + id
+}
+
+func (s *WaitingRoomService) roomKey(room string) string {
+	return waitingRoomKeyPrefix + "room:" + room
+}
+
+// newJoinRequestID returns an unguessable ID, used for request IDs and poll tokens
+func newJoinRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Start starts the waiting room timeout sweeper
+func (s *WaitingRoomService) Start() {
+	if s.redis == nil {
+		return
+	}
+
+	interval := s.cfg.WaitingRoom.SweepInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	s.ticker = time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := s.ExpireOverdue(ctx); err != nil {
+					log.Error().Err(err).Msg("Waiting room sweep failed")
+				}
+				cancel()
+			}
+		}
+	}()
+
+	log.Info().Dur("interval", interval).Msg("Waiting room sweeper started")
+}
+
+// Stop stops the waiting room timeout sweeper
+func (s *WaitingRoomService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		if s.ticker != nil {
+			s.ticker.Stop()
+		}
+		log.Info().Msg("Waiting room sweeper stopped")
+	})
+}
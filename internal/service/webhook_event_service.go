@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+
+	"api-gateway-go/internal/config"
+	"api-gateway-go/internal/models"
+	"api-gateway-go/internal/repository"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	webhookRoomBatchSize = 500
+	webhookShardBuffer   = 256
+	webhookEventTimeout  = 2 * time.Minute
+	// webhookEventStaleAfter is how long an event may stay claimed before
+	// another instance takes it over; well past webhookEventTimeout
+	webhookEventStaleAfter = 5 * time.Minute
+)
+
+var ErrWebhookRoomRequired = errors.New("room is required")
+
+// WebhookEventProcessor applies a stored webhook event
+type WebhookEventProcessor func(ctx context.Context, event *livekit.WebhookEvent) error
+
+// WebhookEventService stores LiveKit webhook events before they are acknowledged
+// and processes them in order per room. Each room is always handled by the same
+// worker, and every event is claimed in the database before it runs, so events of
+// one room never run concurrently, even when several gateway instances share the table.
+// A failed event is retried with backoff and holds up the later events of its
+// room; once out of attempts it stays failed, blocking the room until it is replayed.
+type WebhookEventService struct {
+	eventRepo *repository.WebhookEventRepository
+	cfg       *config.Config
+	owner     string
+	processor WebhookEventProcessor
+	shards    []chan string
+	ticker    *time.Ticker
+	done      chan struct{}
+	wg        sync.WaitGroup
+	stopOnce  sync.Once
+}
+
+// NewWebhookEventService creates a new WebhookEventService
+func NewWebhookEventService(eventRepo *repository.WebhookEventRepository, cfg *config.Config) *WebhookEventService {
+	workers := cfg.LiveKitWebhook.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	shards := make([]chan string, workers)
+	for i := range shards {
+		shards[i] = make(chan string, webhookShardBuffer)
+	}
+
+	return &WebhookEventService{
+		eventRepo: eventRepo,
+		cfg:       cfg,
+		owner:     newWebhookEventOwner(),
+		shards:    shards,
+		done:      make(chan struct{}),
+	}
+}
+
+// SetProcessor sets the function that applies stored events. It must be set before Start.
+func (s *WebhookEventService) SetProcessor(processor WebhookEventProcessor) {
+	s.processor = processor
+}
+
+// Store persists a verified webhook event and queues its room for processing.
+// Returns false when the event was already stored, so it must not be processed again.
+func (s *WebhookEventService) Store(ctx context.Context, event *livekit.WebhookEvent, payload []byte) (bool, error) {
+	eventID := event.GetId()
+	if eventID == "" {
+		sum := sha256.Sum256(payload)
+		eventID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	room := webhookEventRoom(event)
+
+	created, err := s.eventRepo.Create(ctx, repository.CreateWebhookEventParams{
+		EventID:   eventID,
+		Event:     event.GetEvent(),
+		Room:      room,
+		CreatedAt: event.GetCreatedAt(),
+		Payload:   string(payload),
+	})
+	if err != nil || !created {
+		return false, err
+	}
+
+	s.notify(room)
+	return true, nil
+}
+
+// ListEvents lists the stored events of a room in processing order
+func (s *WebhookEventService) ListEvents(ctx context.Context, room string) ([]models.WebhookEvent, error) {
+	if room == "" {
+		return nil, ErrWebhookRoomRequired
+	}
+	return s.eventRepo.GetByRoom(ctx, room)
+}
+
+// Replay retries the failed and pending events of a room in order, which
+// unblocks a room held up by a failed event. With all set, every stored event
+// of the room is processed again, including the ones already done.
+func (s *WebhookEventService) Replay(ctx context.Context, room string, all bool) (int64, error) {
+	if room == "" {
+		return 0, ErrWebhookRoomRequired
+	}
+
+	count, err := s.eventRepo.ResetRoom(ctx, room, all)
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		s.notify(room)
+	}
+	return count, nil
+}
+
+// notify queues a room on its worker. When the worker is backed up the room
+// is left for the next sweep, which picks up every room with pending events.
+func (s *WebhookEventService) notify(room string) {
+	select {
+	case s.shards[s.shardOf(room)] <- room:
+	default:
+		log.Warn().Str("room", room).Msg("Webhook worker queue full, room left for the next sweep")
+	}
+}
+
+// shardOf returns the worker that owns a room
+func (s *WebhookEventService) shardOf(room string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(room))
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+// Sweep releases events left claimed by a stopped instance and queues every room that has pending events
+func (s *WebhookEventService) Sweep(ctx context.Context) error {
+	released, err := s.eventRepo.ReleaseStale(ctx, time.Now().Add(-webhookEventStaleAfter))
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		log.Warn().Int64("count", released).Msg("Released stale webhook events")
+	}
+
+	rooms, err := s.eventRepo.GetPendingRooms(ctx, webhookRoomBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, room := range rooms {
+		s.notify(room)
+	}
+	return nil
+}
+
+// processRoom processes the pending events of a room one at a time, oldest first.
+// It stops when the room has no pending event left or another instance is
+// processing the room's oldest event.
+func (s *WebhookEventService) processRoom(room string) {
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), webhookEventTimeout)
+		event, err := s.eventRepo.ClaimNext(ctx, room, s.owner)
+		cancel()
+		if err != nil {
+			log.Error().Err(err).Str("room", room).Msg("Failed to claim webhook event")
+			return
+		}
+		if event == nil {
+			return
+		}
+
+		s.processEvent(event)
+	}
+}
+
+// processEvent applies one stored event and records the outcome. A failed event
+// is queued again after a backoff, or marked failed once it is out of attempts.
+func (s *WebhookEventService) processEvent(stored *models.WebhookEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookEventTimeout)
+	defer cancel()
+
+	processErr := s.apply(ctx, stored)
+	if processErr == nil {
+		if err := s.eventRepo.MarkDone(ctx, stored.ID, s.owner); err != nil {
+			log.Error().Err(err).Uint("id", stored.ID).Msg("Failed to mark webhook event done")
+		}
+		return
+	}
+
+	attempts := stored.Attempts + 1
+	status := "pending"
+	if attempts >= s.maxAttempts() {
+		status = "failed"
+		log.Error().Err(processErr).
+			Str("eventId", stored.EventID).
+			Str("event", stored.Event).
+			Str("room", stored.Room).
+			Int("attempts", attempts).
+			Msg("Webhook event failed, its room is blocked until the event is replayed")
+	} else {
+		log.Warn().Err(processErr).
+			Str("eventId", stored.EventID).
+			Str("event", stored.Event).
+			Str("room", stored.Room).
+			Int("attempts", attempts).
+			Msg("Webhook event failed, retrying")
+	}
+
+	nextAttempt := time.Now().Add(s.backoff(attempts))
+	if err := s.eventRepo.MarkFailed(ctx, stored.ID, s.owner, status, processErr.Error(), nextAttempt); err != nil {
+		log.Error().Err(err).Uint("id", stored.ID).Msg("Failed to mark webhook event failed")
+	}
+}
+
+// maxAttempts returns how often an event is tried before it is marked failed
+func (s *WebhookEventService) maxAttempts() int {
+	if s.cfg.LiveKitWebhook.MaxAttempts <= 0 {
+		return 1
+	}
+	return s.cfg.LiveKitWebhook.MaxAttempts
+}
+
+// backoff returns the delay before the next attempt
+func (s *WebhookEventService) backoff(attempts int) time.Duration {
+	delay := s.cfg.LiveKitWebhook.BackoffBase
+	if delay <= 0 {
+		delay = 10 * time.Second
+	}
+
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if s.cfg.LiveKitWebhook.BackoffMax > 0 && delay >= s.cfg.LiveKitWebhook.BackoffMax {
+			return s.cfg.LiveKitWebhook.BackoffMax
+		}
+	}
+
+	return delay
+}
+
+// apply decodes a stored event and runs the processor on it
+func (s *WebhookEventService) apply(ctx context.Context, stored *models.WebhookEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	var event livekit.WebhookEvent
+	unmarshalOpts := protojson.UnmarshalOptions{DiscardUnknown: true, AllowPartial: true}
+	if err := unmarshalOpts.Unmarshal([]byte(stored.Payload), &event); err != nil {
+		return fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+
+	if s.processor == nil {
+		return errors.New("no webhook event processor")
+	}
+	return s.processor(ctx, &event)
+}
+
+// newWebhookEventOwner returns the name this instance claims events under
+func newWebhookEventOwner() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "gateway"
+	}
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
+
+// webhookEventRoom returns the room an event belongs to, which sets its ordering
+func webhookEventRoom(event *livekit.WebhookEvent) string {
+	if name := event.GetRoom().GetName(); name != "" {
+		return name
+	}
+	if name := event.GetEgressInfo().GetRoomName(); name != "" {
+		return name
+	}
+	return event.GetIngressInfo().GetRoomName()
+}
+
+// Start starts the room workers and the sweeper. The first sweep runs
+// immediately so events left pending by a restart are picked up.
+func (s *WebhookEventService) Start() {
+	for _, shard := range s.shards {
+		s.wg.Add(1)
+		go func(rooms chan string) {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-s.done:
+					return
+				case room := <-rooms:
+					s.processRoom(room)
+				}
+			}
+		}(shard)
+	}
+
+	interval := s.cfg.LiveKitWebhook.SweepInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	s.ticker = time.NewTicker(interval)
+
+	go func() {
+		sweep := func() {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if err := s.Sweep(ctx); err != nil {
+				log.Error().Err(err).Msg("Webhook event sweep failed")
+			}
+			cancel()
+		}
+
+		sweep()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.ticker.C:
+				sweep()
+			}
+		}
+	}()
+
+	log.Info().Int("workers", len(s.shards)).Dur("interval", interval).Msg("Webhook event workers started")
+}
+
+// Stop stops the workers after the events they are processing finish
+func (s *WebhookEventService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		if s.ticker != nil {
+			s.ticker.Stop()
+		}
+		s.wg.Wait()
+		log.Info().Msg("Webhook event workers stopped")
+	})
+}
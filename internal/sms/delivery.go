@@ -0,0 +1,27 @@
+package sms
+
+import "strings"
+
+// Delivery statuses reported by carriers
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+	DeliveryExpired   = "expired"
+)
+
+// NormalizeDeliveryStatus maps carrier and SMPP receipt states to a delivery status
+func NormalizeDeliveryStatus(status string) string {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "DELIVRD", "DELIVERED", "SUCCESS", "OK", "1":
+		return DeliveryDelivered
+	case "EXPIRED", "EXPIRE":
+		return DeliveryExpired
+	case "UNDELIV", "UNDELIVERED", "REJECTD", "REJECTED", "FAILED", "FAIL", "DELETED", "UNKNOWN", "2":
+		return DeliveryFailed
+	case "ACCEPTD", "ACCEPTED", "ENROUTE", "SENT", "PENDING", "BUFFERED":
+		return DeliveryPending
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,59 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"api-gateway-go/pkg/utils"
+)
+
+// FileProvider appends messages to a local file instead of sending them, for development
+type FileProvider struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileProvider creates a new FileProvider
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Name returns the provider name
+func (p *FileProvider) Name() string {
+	return ProviderFile
+}
+
+// Send writes the message as a JSON line
+func (p *FileProvider) Send(ctx context.Context, msg Message) (string, error) {
+	messageID := fmt.Sprintf("file-%d", time.Now().UnixNano())
+
+	line, err := json.Marshal(map[string]string{
+		"messageId": messageID,
+		"to":        msg.To,
+		"sender":    msg.Sender,
+		"message":   msg.Text,
+		"createdAt": utils.FormatDateTimeNow(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open SMS file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return "", fmt.Errorf("failed to write SMS file: %w", err)
+	}
+
+	return messageID, nil
+}
@@ -0,0 +1,140 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"api-gateway-go/internal/config"
+)
+
+// JSONProvider posts messages as JSON to a generic HTTP gateway
+type JSONProvider struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewJSONProvider creates a new JSONProvider
+func NewJSONProvider(apiURL string) *JSONProvider {
+	return &JSONProvider{
+		url: apiURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Name returns the provider name
+func (p *JSONProvider) Name() string {
+	return ProviderJSON
+}
+
+// Send sends a message
+func (p *JSONProvider) Send(ctx context.Context, msg Message) (string, error) {
+	body := map[string]string{
+		"phoneNumber": msg.To,
+		"message":     msg.Text,
+	}
+	if msg.Sender != "" {
+		body["sender"] = msg.Sender
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doRequest(p.httpClient, req)
+}
+
+// FormProvider posts messages as form fields to an HTTP gateway
+type FormProvider struct {
+	cfg        config.SMSFormConfig
+	httpClient *http.Client
+}
+
+// NewFormProvider creates a new FormProvider
+func NewFormProvider(cfg config.SMSFormConfig) *FormProvider {
+	return &FormProvider{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Name returns the provider name
+func (p *FormProvider) Name() string {
+	return ProviderForm
+}
+
+// Send sends a message
+func (p *FormProvider) Send(ctx context.Context, msg Message) (string, error) {
+	form := url.Values{}
+	form.Set("username", p.cfg.Username)
+	form.Set("password", p.cfg.Password)
+	form.Set("to", msg.To)
+	form.Set("message", msg.Text)
+	if msg.Sender != "" {
+		form.Set("sender", msg.Sender)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.cfg.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return doRequest(p.httpClient, req)
+}
+
+// doRequest executes a gateway request and extracts the message ID from the response
+func doRequest(client *http.Client, req *http.Request) (string, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("SMS API error: %s", string(body))
+	}
+
+	return parseMessageID(body), nil
+}
+
+// parseMessageID reads the message ID from a JSON gateway response
+func parseMessageID(body []byte) string {
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return ""
+	}
+
+	if data, ok := result["data"].(map[string]interface{}); ok {
+		result = data
+	}
+
+	for _, key := range []string{"messageId", "message_id", "msgId", "id"} {
+		switch v := result[key].(type) {
+		case string:
+			return v
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		}
+	}
+
+	return ""
+}
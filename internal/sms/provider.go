@@ -0,0 +1,63 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"api-gateway-go/internal/config"
+)
+
+// Provider names
+const (
+	ProviderJSON = "json"
+	ProviderForm = "form"
+	ProviderSMPP = "smpp"
+	ProviderFile = "file"
+)
+
+var (
+	ErrUnknownProvider       = errors.New("unknown SMS provider")
+	ErrProviderNotConfigured = errors.New("SMS provider is not configured")
+)
+
+// Message is an SMS message to be sent through a provider
+type Message struct {
+	To     string
+	Sender string
+	Text   string
+}
+
+// Provider sends SMS messages through a gateway
+type Provider interface {
+	// Name returns the provider name
+	Name() string
+	// Send sends a message and returns the gateway message ID when available
+	Send(ctx context.Context, msg Message) (string, error)
+}
+
+// NewProvider creates a provider by name using the application configuration
+func NewProvider(name string, cfg *config.Config) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ProviderJSON:
+		if cfg.SMSAPIURL == "" {
+			return nil, fmt.Errorf("%w: %s", ErrProviderNotConfigured, ProviderJSON)
+		}
+		return NewJSONProvider(cfg.SMSAPIURL), nil
+	case ProviderForm:
+		if cfg.SMSForm.URL == "" {
+			return nil, fmt.Errorf("%w: %s", ErrProviderNotConfigured, ProviderForm)
+		}
+		return NewFormProvider(cfg.SMSForm), nil
+	case ProviderSMPP:
+		if cfg.SMPP.Host == "" {
+			return nil, fmt.Errorf("%w: %s", ErrProviderNotConfigured, ProviderSMPP)
+		}
+		return NewSMPPProvider(cfg.SMPP), nil
+	case ProviderFile:
+		return NewFileProvider(cfg.SMSFilePath), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+}
@@ -0,0 +1,233 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf16"
+
+	"api-gateway-go/internal/config"
+)
+
+// SMPP command IDs
+const (
+	smppGenericNack        uint32 = 0x80000000
+	smppBindTransmitter    uint32 = 0x00000002
+	smppBindTransmitterRsp uint32 = 0x80000002
+	smppSubmitSM           uint32 = 0x00000004
+	smppSubmitSMResp       uint32 = 0x80000004
+	smppUnbind             uint32 = 0x00000006
+	smppUnbindResp         uint32 = 0x80000006
+)
+
+// SMPP parameter values
+const (
+	smppInterfaceVersion = 0x34
+	smppTonInternational = 0x01
+	smppTonAlphanumeric  = 0x05
+	smppNpiISDN          = 0x01
+	smppCodingDefault    = 0x00
+	smppCodingUCS2       = 0x08
+	smppTagPayload       = 0x0424
+	smppMaxShortMessage  = 254
+)
+
+var ErrSMPPCommandFailed = errors.New("SMPP command failed")
+
+// SMPPProvider sends messages over SMPP 3.4 as a transmitter.
+// Each send opens its own session: bind, submit_sm, unbind.
+type SMPPProvider struct {
+	cfg      config.SMPPConfig
+	sequence uint32
+}
+
+// NewSMPPProvider creates a new SMPPProvider
+func NewSMPPProvider(cfg config.SMPPConfig) *SMPPProvider {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMPPProvider{cfg: cfg}
+}
+
+// Name returns the provider name
+func (p *SMPPProvider) Name() string {
+	return ProviderSMPP
+}
+
+// Send sends a message
+func (p *SMPPProvider) Send(ctx context.Context, msg Message) (string, error) {
+	dialer := net.Dialer{Timeout: p.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(p.cfg.Host, p.cfg.Port))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect SMPP: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(p.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	// Bind
+	bind := new(bytes.Buffer)
+	writeCString(bind, p.cfg.SystemID)
+	writeCString(bind, p.cfg.Password)
+	writeCString(bind, p.cfg.SystemType)
+	bind.WriteByte(smppInterfaceVersion)
+	bind.WriteByte(0) // addr_ton
+	bind.WriteByte(0) // addr_npi
+	writeCString(bind, "")
+
+	if _, err := p.call(conn, smppBindTransmitter, smppBindTransmitterRsp, bind.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to bind SMPP: %w", err)
+	}
+
+	// Submit
+	resp, err := p.call(conn, smppSubmitSM, smppSubmitSMResp, buildSubmitSM(msg))
+	if err != nil {
+		return "", fmt.Errorf("failed to submit SMPP: %w", err)
+	}
+	messageID := string(bytes.TrimRight(resp, "\x00"))
+
+	// Unbind, ignore errors since the message is already accepted
+	p.call(conn, smppUnbind, smppUnbindResp, nil)
+
+	return messageID, nil
+}
+
+// call writes a PDU and waits for its response
+func (p *SMPPProvider) call(conn net.Conn, commandID, respID uint32, body []byte) ([]byte, error) {
+	seq := atomic.AddUint32(&p.sequence, 1)
+
+	header := make([]byte, 16)
+	binary.BigEndian.PutUint32(header[0:], uint32(16+len(body)))
+	binary.BigEndian.PutUint32(header[4:], commandID)
+	binary.BigEndian.PutUint32(header[12:], seq)
+
+	if _, err := conn.Write(append(header, body...)); err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return nil, err
+		}
+
+		length := binary.BigEndian.Uint32(header[0:])
+		id := binary.BigEndian.Uint32(header[4:])
+		status := binary.BigEndian.Uint32(header[8:])
+		respSeq := binary.BigEndian.Uint32(header[12:])
+
+		if length < 16 {
+			return nil, fmt.Errorf("%w: invalid PDU length %d", ErrSMPPCommandFailed, length)
+		}
+
+		respBody := make([]byte, length-16)
+		if _, err := io.ReadFull(conn, respBody); err != nil {
+			return nil, err
+		}
+
+		// Skip unrelated PDUs such as enquire_link from the server
+		if respSeq != seq || (id != respID && id != smppGenericNack) {
+			continue
+		}
+
+		if status != 0 || id == smppGenericNack {
+			return nil, fmt.Errorf("%w: command 0x%08x status 0x%08x", ErrSMPPCommandFailed, commandID, status)
+		}
+
+		return respBody, nil
+	}
+}
+
+// buildSubmitSM builds the submit_sm body for a message
+func buildSubmitSM(msg Message) []byte {
+	coding := byte(smppCodingDefault)
+	text := []byte(msg.Text)
+	if !isASCII(msg.Text) {
+		coding = smppCodingUCS2
+		text = encodeUCS2(msg.Text)
+	}
+
+	sourceTon, sourceNpi := byte(smppTonAlphanumeric), byte(0)
+	if isNumeric(msg.Sender) {
+		sourceTon, sourceNpi = smppTonInternational, smppNpiISDN
+	}
+
+	body := new(bytes.Buffer)
+	writeCString(body, "") // service_type
+	body.WriteByte(sourceTon)
+	body.WriteByte(sourceNpi)
+	writeCString(body, msg.Sender)
+	body.WriteByte(smppTonInternational)
+	body.WriteByte(smppNpiISDN)
+	writeCString(body, msg.To)
+	body.WriteByte(0)      // esm_class
+	body.WriteByte(0)      // protocol_id
+	body.WriteByte(0)      // priority_flag
+	writeCString(body, "") // schedule_delivery_time
+	writeCString(body, "") // validity_period
+	body.WriteByte(1)      // registered_delivery, request delivery receipt
+	body.WriteByte(0)      // replace_if_present_flag
+	body.WriteByte(coding)
+	body.WriteByte(0) // sm_default_msg_id
+
+	if len(text) <= smppMaxShortMessage {
+		body.WriteByte(byte(len(text)))
+		body.Write(text)
+		return body.Bytes()
+	}
+
+	// Long messages go in the message_payload TLV
+	body.WriteByte(0)
+	tlv := make([]byte, 4)
+	binary.BigEndian.PutUint16(tlv[0:], smppTagPayload)
+	binary.BigEndian.PutUint16(tlv[2:], uint16(len(text)))
+	body.Write(tlv)
+	body.Write(text)
+
+	return body.Bytes()
+}
+
+func writeCString(buf *bytes.Buffer, s string) {
+	buf.WriteString(s)
+	buf.WriteByte(0)
+}
+
+func encodeUCS2(s string) []byte {
+	codes := utf16.Encode([]rune(s))
+	out := make([]byte, len(codes)*2)
+	for i, c := range codes {
+		binary.BigEndian.PutUint16(out[i*2:], c)
+	}
+	return out
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package sms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// submitSM holds the submit_sm fields the tests check
+type submitSM struct {
+	sourceTon byte
+	sourceNpi byte
+	sender    string
+	to        string
+	coding    byte
+	short     []byte
+	payload   []byte
+}
+
+// parseSubmitSM decodes a body built by buildSubmitSM
+func parseSubmitSM(t *testing.T, body []byte) submitSM {
+	t.Helper()
+
+	buf := bytes.NewBuffer(body)
+	cstring := func() string {
+		s, err := buf.ReadString(0)
+		if err != nil {
+			t.Fatalf("unterminated C string: %v", err)
+		}
+		return strings.TrimSuffix(s, "\x00")
+	}
+	next := func() byte {
+		b, err := buf.ReadByte()
+		if err != nil {
+			t.Fatalf("short submit_sm body: %v", err)
+		}
+		return b
+	}
+
+	var pdu submitSM
+	cstring() // service_type
+	pdu.sourceTon = next()
+	pdu.sourceNpi = next()
+	pdu.sender = cstring()
+	next() // dest_addr_ton
+	next() // dest_addr_npi
+	pdu.to = cstring()
+	next() // esm_class
+	next() // protocol_id
+	next() // priority_flag
+	cstring()
+	cstring()
+	next() // registered_delivery
+	next() // replace_if_present_flag
+	pdu.coding = next()
+	next() // sm_default_msg_id
+	pdu.short = buf.Next(int(next()))
+
+	if buf.Len() > 0 {
+		if buf.Len() < 4 {
+			t.Fatalf("short TLV header: %d bytes", buf.Len())
+		}
+		tag := binary.BigEndian.Uint16(buf.Next(2))
+		length := binary.BigEndian.Uint16(buf.Next(2))
+		if tag != smppTagPayload {
+			t.Fatalf("TLV tag = 0x%04x, want 0x%04x", tag, smppTagPayload)
+		}
+		pdu.payload = buf.Next(int(length))
+		if buf.Len() > 0 {
+			t.Fatalf("%d trailing bytes after message_payload", buf.Len())
+		}
+	}
+
+	return pdu
+}
+
+func TestBuildSubmitSM(t *testing.T) {
+	longASCII := strings.Repeat("a", smppMaxShortMessage+1)
+	longThai := strings.Repeat("ก", smppMaxShortMessage/2+1)
+
+	tests := []struct {
+		name      string
+		msg       Message
+		coding    byte
+		sourceTon byte
+		sourceNpi byte
+		short     []byte
+		payload   []byte
+	}{
+		{
+			name:      "ascii with alphanumeric sender",
+			msg:       Message{To: "66812345678", Sender: "Clinic", Text: "Hello"},
+			coding:    smppCodingDefault,
+			sourceTon: smppTonAlphanumeric,
+			short:     []byte("Hello"),
+		},
+		{
+			name:      "numeric sender",
+			msg:       Message{To: "66812345678", Sender: "6621234567", Text: "Hi"},
+			coding:    smppCodingDefault,
+			sourceTon: smppTonInternational,
+			sourceNpi: smppNpiISDN,
+			short:     []byte("Hi"),
+		},
+		{
+			name:      "thai as ucs-2",
+			msg:       Message{To: "66812345678", Sender: "Clinic", Text: "สวัสดี"},
+			coding:    smppCodingUCS2,
+			sourceTon: smppTonAlphanumeric,
+			short:     encodeUCS2("สวัสดี"),
+		},
+		{
+			name:      "ascii at short message limit",
+			msg:       Message{To: "66812345678", Sender: "Clinic", Text: longASCII[1:]},
+			coding:    smppCodingDefault,
+			sourceTon: smppTonAlphanumeric,
+			short:     []byte(longASCII[1:]),
+		},
+		{
+			name:      "long ascii in message_payload",
+			msg:       Message{To: "66812345678", Sender: "Clinic", Text: longASCII},
+			coding:    smppCodingDefault,
+			sourceTon: smppTonAlphanumeric,
+			short:     []byte{},
+			payload:   []byte(longASCII),
+		},
+		{
+			name:      "long ucs-2 in message_payload",
+			msg:       Message{To: "66812345678", Sender: "Clinic", Text: longThai},
+			coding:    smppCodingUCS2,
+			sourceTon: smppTonAlphanumeric,
+			short:     []byte{},
+			payload:   encodeUCS2(longThai),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdu := parseSubmitSM(t, buildSubmitSM(tt.msg))
+
+			if pdu.sender != tt.msg.Sender || pdu.to != tt.msg.To {
+				t.Errorf("addresses = %q -> %q, want %q -> %q", pdu.sender, pdu.to, tt.msg.Sender, tt.msg.To)
+			}
+			if pdu.sourceTon != tt.sourceTon || pdu.sourceNpi != tt.sourceNpi {
+				t.Errorf("source ton/npi = %d/%d, want %d/%d", pdu.sourceTon, pdu.sourceNpi, tt.sourceTon, tt.sourceNpi)
+			}
+			if pdu.coding != tt.coding {
+				t.Errorf("data_coding = 0x%02x, want 0x%02x", pdu.coding, tt.coding)
+			}
+			if !bytes.Equal(pdu.short, tt.short) {
+				t.Errorf("short_message = %x, want %x", pdu.short, tt.short)
+			}
+			if !bytes.Equal(pdu.payload, tt.payload) {
+				t.Errorf("message_payload = %x, want %x", pdu.payload, tt.payload)
+			}
+		})
+	}
+}
+
+func TestEncodeUCS2(t *testing.T) {
+	tests := []struct {
+		text string
+		want []byte
+	}{
+		{"", []byte{}},
+		{"A", []byte{0x00, 0x41}},
+		{"ก", []byte{0x0e, 0x01}},
+		{"😀", []byte{0xd8, 0x3d, 0xde, 0x00}},
+	}
+
+	for _, tt := range tests {
+		if got := encodeUCS2(tt.text); !bytes.Equal(got, tt.want) {
+			t.Errorf("encodeUCS2(%q) = %x, want %x", tt.text, got, tt.want)
+		}
+	}
+}
@@ -22,6 +22,8 @@ const (
 	EventRoomRecord     EventType = "room_record"
 	EventQueueUpdate    EventType = "queue_update"
 	EventNewCase        EventType = "newcase"
+	EventJoinRequest    EventType = "join_request"
+	EventJoinAnswer     EventType = "join_answer"
 )
 
 // CrossInstanceEvent represents an event to be published across instances
@@ -55,6 +55,7 @@ type Hub struct {
 	chatService *service.ChatService
 	carService  *service.CarService
 	linkService *service.LinkService
+	waitingRoom *service.WaitingRoomService
 
 	// Repositories
 	roomRepo *repository.RoomRepository
@@ -79,6 +80,7 @@ func NewHub(
 	chatService *service.ChatService,
 	carService *service.CarService,
 	linkService *service.LinkService,
+	waitingRoom *service.WaitingRoomService,
 	roomRepo *repository.RoomRepository,
 	userRepo *repository.UserRepository,
 	chatRepo *repository.ChatRepository,
@@ -107,6 +109,7 @@ func NewHub(
 		chatService: chatService,
 		carService:  carService,
 		linkService: linkService,
+		waitingRoom: waitingRoom,
 		roomRepo:    roomRepo,
 		userRepo:    userRepo,
 		chatRepo:    chatRepo,
@@ -327,6 +330,10 @@ func (h *Hub) setupCrossInstanceEvents(namespace, room string) {
 			h.server.BroadcastToRoom(namespace, room, "chat-message", data)
 		case EventRoomRecord:
 			h.server.BroadcastToRoom(namespace, room, "room-record", data)
+		case EventJoinRequest:
+			h.server.BroadcastToRoom(namespace, room, "auth-join-conference", data)
+		case EventJoinAnswer:
+			h.server.BroadcastToRoom(namespace, room, "auth-join-conference-answer", data)
 		}
 	}
 
@@ -385,12 +392,93 @@ func (h *Hub) handleUserConference(s socketio.Conn, room string, data map[string
 }
 
 func (h *Hub) handleAuthJoinConference(s socketio.Conn, room string, data map[string]interface{}) {
-	// Handle auth join conference request
-	h.server.BroadcastToRoom("/"+room, room, "auth-join-conference", data)
+	// Guests join the waiting room through /user/joingenerate; agents can ask for the current queue
+	if h.waitingRoom == nil {
+		return
+	}
+
+	ctx := context.Background()
+	if h.roomAgentName(ctx, s, room) == "" {
+		s.Emit("error", map[string]string{"message": "only agents can list join requests"})
+		return
+	}
+
+	requests, err := h.waitingRoom.ListPending(ctx, room)
+	if err != nil {
+		logger.Error("Failed to list waiting room for %s: %v", room, err)
+		return
+	}
+
+	for _, req := range requests {
+		s.Emit("auth-join-conference", req)
+	}
 }
 
 func (h *Hub) handleAuthJoinConferenceAnswer(s socketio.Conn, room string, data map[string]interface{}) {
-	h.server.BroadcastToRoom("/"+room, room, "auth-join-conference-answer", data)
+	if h.waitingRoom == nil {
+		return
+	}
+
+	ctx := context.Background()
+	agent := h.roomAgentName(ctx, s, room)
+	if agent == "" {
+		s.Emit("error", map[string]string{"message": "only agents can answer join requests"})
+		return
+	}
+
+	requestID, _ := data["requestId"].(string)
+	approved, _ := data["approved"].(bool)
+	reason, _ := data["reason"].(string)
+
+	var err error
+	if approved {
+		_, err = h.waitingRoom.Approve(ctx, requestID, agent)
+	} else {
+		_, err = h.waitingRoom.Deny(ctx, requestID, agent, reason)
+	}
+	if err != nil {
+		s.Emit("error", map[string]string{"message": err.Error()})
+	}
+}
+
+// roomAgentName returns the user name of an admin or host connected on this socket, or ""
+func (h *Hub) roomAgentName(ctx context.Context, s socketio.Conn, room string) string {
+	state, ok := s.Context().(*SocketState)
+	if !ok || state.Identity == "" || h.userService == nil {
+		return ""
+	}
+
+	user, err := h.userService.GetUserDetail(ctx, room, state.Identity, "")
+	if err != nil || user == nil {
+		return ""
+	}
+
+	userType := user.UserType.String
+	if userType != "admin" && userType != "host" {
+		return ""
+	}
+
+	if user.UserName.Valid && user.UserName.String != "" {
+		return user.UserName.String
+	}
+	return state.Identity
+}
+
+// NotifyJoinRequest tells the agents of a room, on every instance, that a guest is waiting.
+// The request ID is not a secret: guests poll with the poll token they alone received.
+func (h *Hub) NotifyJoinRequest(req *service.JoinRequest) {
+	h.eventMgr.PublishEvent(context.Background(), req.Room, EventJoinRequest, req)
+}
+
+// NotifyJoinAnswer tells a room, on every instance, that a join request was decided
+func (h *Hub) NotifyJoinAnswer(req *service.JoinRequest) {
+	h.eventMgr.PublishEvent(context.Background(), req.Room, EventJoinAnswer, map[string]interface{}{
+		"requestId": req.ID,
+		"userName":  req.UserName,
+		"status":    req.Status,
+		"decidedBy": req.DecidedBy,
+		"reason":    req.Reason,
+	})
 }
 
 // Room event handlers
@@ -0,0 +1,4 @@
+-- Per-service SMS provider selection (json, form, smpp, file).
+-- NULL uses SMS_PROVIDER from the environment.
+ALTER TABLE `services`
+  ADD COLUMN `smsProvider` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `smsSenderName`;
@@ -0,0 +1,27 @@
+-- Durable outbox for outgoing SMS with retry tracking
+CREATE TABLE IF NOT EXISTS `sms_outbox` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `service` int DEFAULT NULL,
+  `linkID` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `mobile` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `sender` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `provider` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `messageType` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `message` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'pending',
+  `attempts` int NOT NULL DEFAULT '0',
+  `maxAttempts` int NOT NULL DEFAULT '5',
+  `providerMessageId` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `lastError` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmNextAttempt` datetime DEFAULT NULL,
+  `dtmSent` datetime DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_sms_outbox_status_next` (`status`,`dtmNextAttempt`),
+  KEY `idx_sms_outbox_room` (`room`),
+  KEY `idx_sms_outbox_linkID` (`linkID`),
+  KEY `idx_sms_outbox_providerMessageId` (`providerMessageId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,8 @@
+-- Delivery receipts (DLR) reported by SMS carriers
+ALTER TABLE `sms_outbox`
+  ADD COLUMN `deliveryStatus` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `lastError`,
+  ADD COLUMN `deliveryError` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `deliveryStatus`,
+  ADD COLUMN `dtmDelivered` datetime DEFAULT NULL AFTER `dtmSent`;
+
+ALTER TABLE `link_connect`
+  ADD COLUMN `smsDeliveryStatus` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `sms`;
@@ -0,0 +1,15 @@
+-- SMS message templates per service, message type and locale
+-- service 0 holds the defaults used when a service has no template of its own
+CREATE TABLE IF NOT EXISTS `sms_template` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `service` int NOT NULL DEFAULT '0',
+  `messageType` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `locale` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'th',
+  `body` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `enabled` int DEFAULT '1',
+  `updatedBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_sms_template_service_type_locale` (`service`,`messageType`,`locale`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
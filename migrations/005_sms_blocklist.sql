@@ -0,0 +1,13 @@
+-- Mobile numbers that must not receive SMS (opt-out, abuse, carrier block)
+-- service 0 blocks the number for every service
+CREATE TABLE IF NOT EXISTS `sms_blocklist` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `mobile` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `service` int NOT NULL DEFAULT '0',
+  `source` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `reason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_sms_blocklist_mobile_service` (`mobile`,`service`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+-- Time a one-time link was redeemed; NULL while unused
+ALTER TABLE `link_connect`
+  ADD COLUMN `dtmRedeemed` datetime DEFAULT NULL AFTER `dtmExpired`;
+
+-- Link passwords are stored as bcrypt hashes from now on. Existing plaintext
+-- passwords keep working and are re-hashed on their next successful use.
@@ -0,0 +1,35 @@
+-- Link IDs and room names are looked up as public identifiers and must be unique.
+-- Inserts retry with a new random value when these keys reject a collision.
+--
+-- Both columns compare case-sensitively (utf8mb4_bin): IDs are drawn from
+-- mixed-case charsets, and a case-insensitive key would treat "abcDEF" and
+-- "ABCdef" as one ID, rejecting existing rows and weakening every lookup.
+-- Comparisons with the room columns of other tables use the binary collation too.
+ALTER TABLE `link_connect`
+  MODIFY `linkID` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL;
+
+ALTER TABLE `room_conference`
+  MODIFY `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL;
+
+-- Exact duplicates made by the old 6-character math/rand IDs are renamed before
+-- the keys are added: the oldest row keeps the value and every later row gets
+-- "-<id>" appended, which the generators never produce. A duplicated value could
+-- only ever be looked up as one of its rows, so the later rows were unreachable.
+-- List the duplicates beforehand to review them:
+--   SELECT linkID, COUNT(*) FROM link_connect WHERE linkID IS NOT NULL GROUP BY linkID HAVING COUNT(*) > 1;
+--   SELECT room, COUNT(*) FROM room_conference WHERE room IS NOT NULL GROUP BY room HAVING COUNT(*) > 1;
+UPDATE `link_connect` l
+  JOIN `link_connect` e ON e.`linkID` = l.`linkID` AND e.`id` < l.`id`
+  SET l.`linkID` = CONCAT(l.`linkID`, '-', l.`id`);
+
+UPDATE `room_conference` r
+  JOIN `room_conference` e ON e.`room` = r.`room` AND e.`id` < r.`id`
+  SET r.`room` = CONCAT(r.`room`, '-', r.`id`);
+
+ALTER TABLE `link_connect`
+  DROP INDEX `idx_link_connect_linkID`,
+  ADD UNIQUE KEY `uq_link_connect_linkID` (`linkID`);
+
+ALTER TABLE `room_conference`
+  DROP INDEX `idx_room_conference_room`,
+  ADD UNIQUE KEY `uq_room_conference_room` (`room`);
@@ -0,0 +1,36 @@
+-- Staff accounts used to log in to the agent console
+CREATE TABLE IF NOT EXISTS `staff_account` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `userName` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `passwordHash` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `displayName` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `service` int NOT NULL DEFAULT '0',
+  `organization` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `enabled` int DEFAULT '1',
+  `failedAttempts` int NOT NULL DEFAULT '0',
+  `dtmLocked` datetime DEFAULT NULL,
+  `dtmLastLogin` datetime DEFAULT NULL,
+  `dtmPasswordChanged` datetime DEFAULT NULL,
+  `createdBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_staff_account_userName` (`userName`),
+  KEY `idx_staff_account_service` (`service`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Hashed refresh tokens issued at login
+CREATE TABLE IF NOT EXISTS `staff_refresh_token` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `staffId` int unsigned NOT NULL,
+  `tokenHash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `userAgent` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `ip` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmExpired` datetime DEFAULT NULL,
+  `dtmRevoked` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_staff_refresh_token_tokenHash` (`tokenHash`),
+  KEY `idx_staff_refresh_token_staffId` (`staffId`),
+  CONSTRAINT `FK_staff_refresh_token_staff_account` FOREIGN KEY (`staffId`) REFERENCES `staff_account` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,8 @@
+-- Refresh tokens are rotated on every use. Tokens issued from the same login
+-- share a familyId so that reuse of a rotated token revokes the whole chain.
+ALTER TABLE `staff_refresh_token`
+  ADD COLUMN `familyId` char(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `tokenHash`,
+  ADD COLUMN `replacedBy` int unsigned DEFAULT NULL AFTER `dtmRevoked`,
+  ADD KEY `idx_staff_refresh_token_familyId` (`familyId`);
+
+UPDATE `staff_refresh_token` SET `familyId` = LEFT(`tokenHash`, 32) WHERE `familyId` IS NULL;
@@ -0,0 +1,3 @@
+-- Access role of a staff account: admin, supervisor or agent
+ALTER TABLE `staff_account`
+  ADD COLUMN `role` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'agent' AFTER `displayName`;
@@ -0,0 +1,18 @@
+-- LiveKit webhook events, stored before they are acknowledged and processed in order per room
+CREATE TABLE IF NOT EXISTS `webhook_event` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `eventId` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `event` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `room` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `createdAt` bigint NOT NULL DEFAULT '0',
+  `payload` mediumtext CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'pending',
+  `attempts` int NOT NULL DEFAULT '0',
+  `lastError` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `dtmReceived` datetime DEFAULT NULL,
+  `dtmProcessed` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_webhook_event_eventId` (`eventId`),
+  KEY `idx_webhook_event_room_status` (`room`,`status`),
+  KEY `idx_webhook_event_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
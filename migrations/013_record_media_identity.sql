@@ -0,0 +1,4 @@
+-- Participant a track composite or track recording belongs to; empty for room composite recordings
+ALTER TABLE `record_media`
+  ADD COLUMN `identity` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `room`,
+  ADD KEY `idx_record_media_room_status` (`room`,`status`);
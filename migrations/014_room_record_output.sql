@@ -0,0 +1,6 @@
+-- Recording output of a room. encodingOptionsPreset holds a LiveKit preset name
+-- or custom encoding options as JSON, so it is widened to fit the JSON.
+ALTER TABLE `room_conference`
+  MODIFY COLUMN `encodingOptionsPreset` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  ADD COLUMN `recordLayout` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `encodingOptionsPreset`,
+  ADD COLUMN `recordFileType` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `recordLayout`;
@@ -0,0 +1,28 @@
+-- Post-recording encode jobs: HLS transcode and thumbnail by the encode service
+CREATE TABLE IF NOT EXISTS `encode_job` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `recordId` int NOT NULL,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `source` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'queued',
+  `attempts` int NOT NULL DEFAULT '0',
+  `maxAttempts` int NOT NULL DEFAULT '3',
+  `callbackToken` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `encoderJobId` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `hls` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `thumbnail` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `lastError` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `dtmNextAttempt` datetime DEFAULT NULL,
+  `dtmSubmitted` datetime DEFAULT NULL,
+  `dtmCompleted` datetime DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_encode_job_recordId` (`recordId`),
+  KEY `idx_encode_job_status_next` (`status`,`dtmNextAttempt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Encoded output of a recording
+ALTER TABLE `record_media`
+  MODIFY COLUMN `hls` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  ADD COLUMN `thumbnail` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `hls`;
@@ -0,0 +1,43 @@
+-- Per-service recording retention: archive after archiveDays, delete after retainDays (0 disables either)
+CREATE TABLE IF NOT EXISTS `retention_policy` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `service` int NOT NULL,
+  `retainDays` int NOT NULL DEFAULT '0',
+  `archiveDays` int NOT NULL DEFAULT '0',
+  `enabled` tinyint NOT NULL DEFAULT '1',
+  `updatedBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  `dtmUpdated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_retention_policy_service` (`service`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Audit trail of recording deletions, archives and legal holds
+CREATE TABLE IF NOT EXISTS `record_audit` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `action` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `recordId` int DEFAULT NULL,
+  `caseId` int DEFAULT NULL,
+  `room` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `service` int DEFAULT NULL,
+  `files` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `reason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `actor` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `dtmCreated` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_record_audit_recordId` (`recordId`),
+  KEY `idx_record_audit_room` (`room`),
+  KEY `idx_record_audit_dtmCreated` (`dtmCreated`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Archived recordings keep their row; files move to RECORD_ARCHIVE_PATH
+ALTER TABLE `record_media`
+  ADD COLUMN `archivePath` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `thumbnail`,
+  ADD COLUMN `dtmArchived` datetime DEFAULT NULL AFTER `dtmCompleted`;
+
+-- Recordings of a case under legal hold are never archived or deleted
+ALTER TABLE `case_data`
+  ADD COLUMN `legalHold` tinyint NOT NULL DEFAULT '0' AFTER `organization`,
+  ADD COLUMN `legalHoldReason` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `legalHold`,
+  ADD COLUMN `legalHoldBy` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `legalHoldReason`,
+  ADD COLUMN `dtmLegalHold` datetime DEFAULT NULL AFTER `legalHoldBy`;
@@ -0,0 +1,5 @@
+-- Gateway instances claim webhook events before processing them, so each event
+-- runs once and the events of a room run in order across instances
+ALTER TABLE `webhook_event`
+  ADD COLUMN `owner` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci DEFAULT NULL AFTER `lastError`,
+  ADD COLUMN `dtmClaimed` datetime DEFAULT NULL AFTER `dtmReceived`;
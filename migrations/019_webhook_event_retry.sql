@@ -0,0 +1,4 @@
+-- Failed webhook events are retried after a backoff; until they succeed or are
+-- replayed they hold up the later events of their room
+ALTER TABLE `webhook_event`
+  ADD COLUMN `dtmNextAttempt` datetime DEFAULT NULL AFTER `dtmClaimed`;
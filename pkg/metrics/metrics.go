@@ -0,0 +1,46 @@
+// Package metrics defines the Prometheus metrics of the gateway and serves them
+package metrics
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// namespace prefixes every gateway metric
+const namespace = "gateway"
+
+// LiveKit webhook metrics
+var (
+	WebhookEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "livekit_webhook",
+		Name:      "events_total",
+		Help:      "LiveKit webhook events accepted, by event type.",
+	}, []string{"event"})
+
+	WebhookRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "livekit_webhook",
+		Name:      "rejected_total",
+		Help:      "LiveKit webhook calls rejected, by reason.",
+	}, []string{"reason"})
+
+	WebhookDuplicates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "livekit_webhook",
+		Name:      "duplicates_total",
+		Help:      "LiveKit webhook events already stored and skipped, by event type.",
+	}, []string{"event"})
+)
+
+// Handler serves the default Prometheus registry
+func Handler() fiber.Handler {
+	handler := fasthttpadaptor.NewFastHTTPHandler(promhttp.Handler())
+	return func(c *fiber.Ctx) error {
+		handler(c.Context())
+		return nil
+	}
+}
@@ -2,10 +2,11 @@ package utils
 
 import (
 	"crypto/md5"
+	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"time"
 )
 
@@ -14,35 +15,53 @@ const (
 	DefaultCharset = "ABCDEFGHIJKLMOPQRSTUVWXYZabcdefghijklmopqrstuvwxyz"
 	// AlphanumericCharset includes letters and numbers
 	AlphanumericCharset = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// RoomNameCharset is the charset for room names
+	RoomNameCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	// DefaultLinkIDLength is the link ID length used when none is configured
+	DefaultLinkIDLength = 10
+	// DefaultRoomNameLength is the room name length used when none is configured
+	DefaultRoomNameLength = 10
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
+// randomInt returns a uniformly distributed random number in [0, n) from crypto/rand
+func randomInt(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return int(v.Int64())
 }
 
-// GenerateRandomString generates a random string of specified length using the given charset
+// GenerateRandomString generates a cryptographically random string of specified length using the given charset
 func GenerateRandomString(length int, charset string) string {
 	if charset == "" {
 		charset = DefaultCharset
 	}
 	b := make([]byte, length)
 	for i := range b {
-		b[i] = charset[rand.Intn(len(charset))]
+		b[i] = charset[randomInt(len(charset))]
 	}
 	return string(b)
 }
 
-// GenerateRoomName generates a random 6-character alphabetic room name
-func GenerateRoomName() string {
-	return GenerateRandomString(6, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+// GenerateRoomName generates a random alphabetic room name
+func GenerateRoomName(length int) string {
+	if length <= 0 {
+		length = DefaultRoomNameLength
+	}
+	return GenerateRandomString(length, RoomNameCharset)
 }
 
-// GenerateLinkID generates a random 6-character link ID
-func GenerateLinkID(charset string) string {
+// GenerateLinkID generates a random link ID
+func GenerateLinkID(charset string, length int) string {
 	if charset == "" {
 		charset = DefaultCharset
 	}
-	return GenerateRandomString(6, charset)
+	if length <= 0 {
+		length = DefaultLinkIDLength
+	}
+	return GenerateRandomString(length, charset)
 }
 
 // GenerateIdentity generates a 10-character identity string
@@ -57,12 +76,12 @@ func GenerateViewerIdentity() string {
 
 // GenerateGuestName generates a guest username
 func GenerateGuestName() string {
-	return fmt.Sprintf("Guest-%d", rand.Intn(100))
+	return fmt.Sprintf("Guest-%d", randomInt(100))
 }
 
 // GenerateUserName generates a username
 func GenerateUserName() string {
-	return fmt.Sprintf("User-%d", rand.Intn(100))
+	return fmt.Sprintf("User-%d", randomInt(100))
 }
 
 // MD5Hash creates an MD5 hash of a string
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// onlyCharset reports whether every character of s is in charset
+func onlyCharset(s, charset string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune(charset, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGenerateLinkID(t *testing.T) {
+	tests := []struct {
+		name        string
+		charset     string
+		length      int
+		wantCharset string
+		wantLength  int
+	}{
+		{"defaults", "", 0, DefaultCharset, DefaultLinkIDLength},
+		{"negative length", "", -1, DefaultCharset, DefaultLinkIDLength},
+		{"custom charset", "abc", 0, "abc", DefaultLinkIDLength},
+		{"custom length", "", 24, DefaultCharset, 24},
+		{"single character", "x", 5, "x", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateLinkID(tt.charset, tt.length)
+			if len(got) != tt.wantLength {
+				t.Errorf("len(GenerateLinkID()) = %d, want %d", len(got), tt.wantLength)
+			}
+			if !onlyCharset(got, tt.wantCharset) {
+				t.Errorf("GenerateLinkID() = %q, want only characters of %q", got, tt.wantCharset)
+			}
+		})
+	}
+}
+
+func TestGenerateLinkIDIsRandom(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := GenerateLinkID("", 0)
+		if seen[id] {
+			t.Fatalf("GenerateLinkID() repeated %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestGenerateRoomName(t *testing.T) {
+	tests := []struct {
+		name       string
+		length     int
+		wantLength int
+	}{
+		{"default", 0, DefaultRoomNameLength},
+		{"negative length", -5, DefaultRoomNameLength},
+		{"custom length", 16, 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateRoomName(tt.length)
+			if len(got) != tt.wantLength {
+				t.Errorf("len(GenerateRoomName()) = %d, want %d", len(got), tt.wantLength)
+			}
+			if !onlyCharset(got, RoomNameCharset) {
+				t.Errorf("GenerateRoomName() = %q, want only letters", got)
+			}
+		})
+	}
+}
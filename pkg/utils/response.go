@@ -5,6 +5,7 @@ import "github.com/gofiber/fiber/v2"
 // Response represents a standard API response
 type Response struct {
 	Status  string      `json:"status"`
+	Code    string      `json:"code,omitempty"`
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 }
@@ -50,6 +51,15 @@ func ErrorResponseWithStatus(c *fiber.Ctx, statusCode int, message string) error
 	})
 }
 
+// ErrorResponseWithCode sends an error response with HTTP status code and a machine-readable error code
+func ErrorResponseWithCode(c *fiber.Ctx, statusCode int, code, message string) error {
+	return c.Status(statusCode).JSON(Response{
+		Status:  "FAIL",
+		Code:    code,
+		Message: message,
+	})
+}
+
 // NotFoundResponse sends a 404 not found response
 func NotFoundResponse(c *fiber.Ctx, message string) error {
 	if message == "" {
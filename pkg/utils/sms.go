@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// SMS encodings
+const (
+	SMSEncodingGSM7 = "GSM-7"
+	SMSEncodingUCS2 = "UCS-2"
+)
+
+// gsm7Basic is the GSM 03.38 basic character set
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension holds characters sent as an escape plus one septet
+const gsm7Extension = "^{}\\[~]|€\f"
+
+// SMSSegmentInfo describes how a message is split into SMS segments
+type SMSSegmentInfo struct {
+	Encoding   string `json:"encoding"`
+	Length     int    `json:"length"`
+	Segments   int    `json:"segments"`
+	PerSegment int    `json:"perSegment"`
+	Remaining  int    `json:"remaining"`
+}
+
+// CalculateSMSSegments returns the encoding, length in encoding units and
+// number of segments for a message. Thai and other non GSM-7 text is sent as UCS-2.
+func CalculateSMSSegments(message string) SMSSegmentInfo {
+	info := SMSSegmentInfo{Encoding: SMSEncodingGSM7}
+
+	for _, r := range message {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			info.Length++
+		case strings.ContainsRune(gsm7Extension, r):
+			info.Length += 2
+		default:
+			info.Encoding = SMSEncodingUCS2
+		}
+		if info.Encoding == SMSEncodingUCS2 {
+			break
+		}
+	}
+
+	single, multi := 160, 153
+	if info.Encoding == SMSEncodingUCS2 {
+		info.Length = len(utf16.Encode([]rune(message)))
+		single, multi = 70, 67
+	}
+
+	switch {
+	case info.Length == 0:
+		info.Segments = 0
+		info.PerSegment = single
+	case info.Length <= single:
+		info.Segments = 1
+		info.PerSegment = single
+	default:
+		info.Segments = (info.Length + multi - 1) / multi
+		info.PerSegment = multi
+	}
+
+	info.Remaining = info.Segments*info.PerSegment - info.Length
+	if info.Segments == 0 {
+		info.Remaining = single
+	}
+
+	return info
+}
+
+// NormalizeMobile converts a Thai mobile number to international digits,
+// e.g. "081-234-5678" and "+66812345678" both become "66812345678"
+func NormalizeMobile(mobile string) string {
+	var b strings.Builder
+	for _, r := range mobile {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	digits := b.String()
+	if strings.HasPrefix(digits, "0") && len(digits) == 10 {
+		return "66" + digits[1:]
+	}
+
+	return digits
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCalculateSMSSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    SMSSegmentInfo
+	}{
+		{"empty", "", SMSSegmentInfo{SMSEncodingGSM7, 0, 0, 160, 160}},
+		{"basic", "Hello", SMSSegmentInfo{SMSEncodingGSM7, 5, 1, 160, 155}},
+		{"extension characters", "{€}", SMSSegmentInfo{SMSEncodingGSM7, 6, 1, 160, 154}},
+		{"gsm7 single limit", strings.Repeat("a", 160), SMSSegmentInfo{SMSEncodingGSM7, 160, 1, 160, 0}},
+		{"gsm7 over single limit", strings.Repeat("a", 161), SMSSegmentInfo{SMSEncodingGSM7, 161, 2, 153, 145}},
+		{"gsm7 two segments full", strings.Repeat("a", 306), SMSSegmentInfo{SMSEncodingGSM7, 306, 2, 153, 0}},
+		{"gsm7 three segments", strings.Repeat("a", 307), SMSSegmentInfo{SMSEncodingGSM7, 307, 3, 153, 152}},
+		{"extension crosses single limit", strings.Repeat("a", 159) + "€", SMSSegmentInfo{SMSEncodingGSM7, 161, 2, 153, 145}},
+		{"thai", "สวัสดี", SMSSegmentInfo{SMSEncodingUCS2, 6, 1, 70, 64}},
+		{"ucs2 single limit", strings.Repeat("ก", 70), SMSSegmentInfo{SMSEncodingUCS2, 70, 1, 70, 0}},
+		{"ucs2 over single limit", strings.Repeat("ก", 71), SMSSegmentInfo{SMSEncodingUCS2, 71, 2, 67, 63}},
+		{"ucs2 two segments full", strings.Repeat("ก", 134), SMSSegmentInfo{SMSEncodingUCS2, 134, 2, 67, 0}},
+		{"mixed text switches to ucs2", "Hi {ก}", SMSSegmentInfo{SMSEncodingUCS2, 6, 1, 70, 64}},
+		{"surrogate pair", "ok 😀", SMSSegmentInfo{SMSEncodingUCS2, 5, 1, 70, 65}},
+		{"surrogate pairs over single limit", strings.Repeat("😀", 36), SMSSegmentInfo{SMSEncodingUCS2, 72, 2, 67, 62}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateSMSSegments(tt.message); got != tt.want {
+				t.Errorf("CalculateSMSSegments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}